@@ -0,0 +1,95 @@
+package xsql
+
+import (
+	"context"
+	"fmt"
+)
+
+// BatchItem is one query of a [QueryBatch], created with [BatchQuery] or
+// [BatchGet]. It pairs the SQL and its args with a typed destination.
+type BatchItem interface {
+	run(ctx context.Context, q Querier) error
+}
+
+type batchQuery[T any] struct {
+	dst   *[]T
+	query string
+	args  []any
+}
+
+func (b batchQuery[T]) run(ctx context.Context, q Querier) error {
+	out, err := Query[T](ctx, q, b.query, b.args...)
+	if err != nil {
+		return err
+	}
+	*b.dst = out
+	return nil
+}
+
+type batchGet[T any] struct {
+	dst   *T
+	query string
+	args  []any
+}
+
+func (b batchGet[T]) run(ctx context.Context, q Querier) error {
+	out, err := Get[T](ctx, q, b.query, b.args...)
+	if err != nil {
+		return err
+	}
+	*b.dst = out
+	return nil
+}
+
+// BatchQuery returns a batch item that scans all rows of query into *dst, like [Query].
+func BatchQuery[T any](dst *[]T, query string, args ...any) BatchItem {
+	return batchQuery[T]{dst: dst, query: query, args: args}
+}
+
+// BatchGet returns a batch item that scans the first row of query into *dst,
+// like [Get]. A query without rows fails the batch with [sql.ErrNoRows].
+func BatchGet[T any](dst *T, query string, args ...any) BatchItem {
+	return batchGet[T]{dst: dst, query: query, args: args}
+}
+
+// QueryBatch runs several queries sequentially on one connection reserved from
+// db and scans each into its destination. Reusing a single connection avoids
+// a pool checkout per query and keeps session state (search_path, temporary
+// tables) consistent across the batch, which suits endpoints that issue a
+// handful of small reads.
+//
+// Items run in order and the batch stops at the first failure; the returned
+// error names the 1-based item and wraps the cause. Destinations of items that
+// did not run are left untouched. The connection is returned to the pool
+// before QueryBatch returns.
+//
+// Example:
+//
+//	var (
+//	    user   User
+//	    orders []Order
+//	    total  int64
+//	)
+//	err := xsql.QueryBatch(ctx, db,
+//	    xsql.BatchGet(&user, `SELECT id, email FROM users WHERE id = $1`, id),
+//	    xsql.BatchQuery(&orders, `SELECT id, total FROM orders WHERE user_id = $1`, id),
+//	    xsql.BatchGet(&total, `SELECT count(*) FROM orders WHERE user_id = $1`, id),
+//	)
+func QueryBatch(ctx context.Context, db Conner, items ...BatchItem) (err error) {
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := conn.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}()
+
+	for i, it := range items {
+		if err := it.run(ctx, conn); err != nil {
+			return fmt.Errorf("xsql: batch item %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package xsql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestQueryBatch_ScansEachItem(t *testing.T) {
+	db := newTestDB(t, func(q string, args []driver.NamedValue) ([]string, [][]driver.Value, error) {
+		switch q {
+		case "user":
+			return []string{"id", "email"}, [][]driver.Value{{args[0].Value, []byte("a@x")}}, nil
+		case "orders":
+			return []string{"id"}, [][]driver.Value{{int64(10)}, {int64(11)}}, nil
+		case "count":
+			return []string{"n"}, [][]driver.Value{{int64(2)}}, nil
+		}
+		t.Fatalf("unexpected query %q", q)
+		return nil, nil, nil
+	})
+	defer func() { _ = db.Close() }()
+
+	var (
+		user   joinUser
+		orders []int64
+		total  int
+	)
+	err := QueryBatch(context.Background(), db,
+		BatchGet(&user, "user", int64(5)),
+		BatchQuery(&orders, "orders"),
+		BatchGet(&total, "count"),
+	)
+	if err != nil {
+		t.Fatalf("QueryBatch: %v", err)
+	}
+	if user.ID != 5 || user.Email != "a@x" {
+		t.Fatalf("user: %+v", user)
+	}
+	if len(orders) != 2 || orders[1] != 11 {
+		t.Fatalf("orders: %v", orders)
+	}
+	if total != 2 {
+		t.Fatalf("total: %d", total)
+	}
+}
+
+func TestQueryBatch_StopsAtFirstError(t *testing.T) {
+	sentinel := errors.New("boom")
+	db := newTestDB(t, func(q string, args []driver.NamedValue) ([]string, [][]driver.Value, error) {
+		switch q {
+		case "bad":
+			return nil, nil, sentinel
+		case "empty":
+			return []string{"n"}, nil, nil
+		}
+		return []string{"n"}, [][]driver.Value{{int64(1)}}, nil
+	})
+	defer func() { _ = db.Close() }()
+
+	var a, b, c int64
+	err := QueryBatch(context.Background(), db,
+		BatchGet(&a, "ok"),
+		BatchGet(&b, "bad"),
+		BatchGet(&c, "ok"),
+	)
+	if !errors.Is(err, sentinel) || !strings.Contains(err.Error(), "item 2") {
+		t.Fatalf("want wrapped item 2 error, got %v", err)
+	}
+	if a != 1 || c != 0 {
+		t.Fatalf("a=%d c=%d", a, c)
+	}
+
+	var list []int64
+	err = QueryBatch(context.Background(), db, BatchQuery(&list, "bad"))
+	if !errors.Is(err, sentinel) {
+		t.Fatalf("want %v, got %v", sentinel, err)
+	}
+	err = QueryBatch(context.Background(), db, BatchGet(&a, "empty"))
+	if !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("want sql.ErrNoRows, got %v", err)
+	}
+}
+
+type failingConner struct{ err error }
+
+func (f failingConner) Conn(context.Context) (*sql.Conn, error) { return nil, f.err }
+
+func TestQueryBatch_ConnError(t *testing.T) {
+	sentinel := errors.New("no conn")
+	if err := QueryBatch(context.Background(), failingConner{sentinel}); !errors.Is(err, sentinel) {
+		t.Fatalf("want %v, got %v", sentinel, err)
+	}
+}
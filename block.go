@@ -0,0 +1,65 @@
+package xsql
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// blockSteps maps cols onto a struct with ,block fields, for self-joins such
+// as `SELECT a.*, b.* FROM users a JOIN users b …` scanned into
+//
+//	type Pair struct {
+//	    Left  User `db:",block"`
+//	    Right User `db:",block"`
+//	}
+//
+// Columns are assigned to the blocks in order: the first block takes columns
+// until a name repeats, which starts the next block, and so on. Within a block
+// columns are matched by name against the block's struct; columns it does not
+// map, and repeats after the last block, fall back to rt's other fields.
+func (m *Mapper) blockSteps(rt reflect.Type, idx *fieldIndex, cols []string) ([]step, []string, error) {
+	subs := make([]*fieldIndex, len(idx.blocks))
+	for b, fi := range idx.blocks {
+		sf := rt.Field(fi)
+		if !m.isRowStruct(sf.Type) {
+			return nil, nil, fmt.Errorf("xsql: block field %s.%s must be a struct, not %s", rt, sf.Name, sf.Type)
+		}
+		subs[b] = m.structIndex(derefPtr(sf.Type))
+	}
+
+	steps := make([]step, len(cols))
+	var unmapped []string
+	b, seen := 0, make(map[string]bool)
+	for i, c := range cols {
+		if c == "" { // masked out by the caller
+			steps[i] = step{kind: stepDrop}
+			continue
+		}
+		if seen[c] {
+			b++
+			clear(seen)
+		}
+		seen[c] = true
+
+		var fp []int
+		if b < len(subs) {
+			if sp, ok := subs[b].byName[c]; ok {
+				fp = append([]int{idx.blocks[b]}, sp...)
+			}
+		}
+		if fp == nil {
+			fp = idx.byName[c]
+		}
+		if fp == nil {
+			steps[i] = step{kind: stepDrop}
+			unmapped = append(unmapped, c)
+			continue
+		}
+		st, err := m.makeFieldStep(rt, fp)
+		if err != nil {
+			return nil, nil, err
+		}
+		steps[i] = st
+	}
+	return steps, unmapped, nil
+}
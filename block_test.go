@@ -0,0 +1,49 @@
+package xsql
+
+import (
+	"context"
+	"database/sql/driver"
+	"strings"
+	"testing"
+)
+
+func TestBlockFields_SelfJoin(t *testing.T) {
+	type User struct {
+		ID   int64  `db:"id"`
+		Name string `db:"name"`
+	}
+	type Mentorship struct {
+		Mentor User  `db:",block"`
+		Mentee *User `db:",block"`
+		Since  int64 `db:"since"`
+	}
+	db := newTestDB(t, func(q string, _ []driver.NamedValue) ([]string, [][]driver.Value, error) {
+		// a.*, b.* with an extra column on each side the struct does not map.
+		return []string{"id", "name", "email", "id", "name", "email", "since"}, [][]driver.Value{
+			{int64(1), "ann", "a@x", int64(2), "bob", "b@x", int64(2020)},
+		}, nil
+	})
+	defer func() { _ = db.Close() }()
+	ctx := context.Background()
+
+	got, err := Get[Mentorship](ctx, db, "q")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Mentor != (User{1, "ann"}) || got.Mentee == nil || *got.Mentee != (User{2, "bob"}) || got.Since != 2020 {
+		t.Fatalf("unexpected value: %+v %+v", got, got.Mentee)
+	}
+
+	m := NewMapper()
+	m.Strict = true
+	if _, err := GetWith[Mentorship](ctx, m, db, "q"); err == nil || !strings.Contains(err.Error(), "email") {
+		t.Fatalf("strict mode should report email, got %v", err)
+	}
+
+	type Bad struct {
+		N int64 `db:",block"`
+	}
+	if _, err := Get[Bad](ctx, db, "q"); err == nil || !strings.Contains(err.Error(), "must be a struct") {
+		t.Fatalf("expected block type error, got %v", err)
+	}
+}
@@ -0,0 +1,118 @@
+package xsql
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"strings"
+)
+
+// Statement is a bound SQL statement: final query text and its args.
+type Statement struct {
+	Query string
+	Args  []any
+}
+
+// RebindChunked is like [Rebind] with a single struct or map[string]any, for
+// queries whose slice parameters may expand past the driver's parameter limit
+// (65535 on PostgreSQL, 2100 on SQL Server, 1000 list entries on Oracle).
+// If the bound statement has at most limit args it is returned alone. Otherwise
+// the largest slice parameter is split into chunks, and one statement is
+// returned per chunk, each with at most limit args; all other parameters are
+// repeated in every statement.
+//
+// The statements are meant to be run one after another and their results
+// combined, so the query must be one whose result is the union of its parts,
+// such as `SELECT … WHERE id IN (:ids)` or `DELETE … WHERE id IN (:ids)`.
+// Aggregates, ORDER BY with LIMIT, and NOT IN do not split that way.
+//
+// Example:
+//
+//	stmts, err := xsql.RebindChunked(`SELECT id, email FROM users WHERE id IN (:ids)`,
+//	    xsql.PlaceholderDollar, 65535, map[string]any{"ids": ids})
+//	var users []User
+//	for _, s := range stmts {
+//	    part, err := xsql.Query[User](ctx, db, s.Query, s.Args...)
+//	    if err != nil {
+//	        return err
+//	    }
+//	    users = append(users, part...)
+//	}
+func RebindChunked(query string, ph Placeholder, limit int, params any) ([]Statement, error) {
+	if limit < 1 {
+		return nil, fmt.Errorf("xsql: invalid parameter limit %d", limit)
+	}
+	if !looksBindable(params) {
+		return nil, ErrUnsupportedArg
+	}
+	s, err := PrepareNamed(query, ph)
+	if err != nil {
+		return nil, err
+	}
+	q, args, err := s.Bind(params)
+	if err != nil {
+		return nil, err
+	}
+	if len(args) <= limit {
+		return []Statement{{Query: q, Args: args}}, nil
+	}
+
+	lut, err := buildParamLookup(params)
+	if err != nil {
+		return nil, err
+	}
+	// Split the largest slice; w is the number of args each of its elements
+	// adds: one per occurrence, or one per column of a :name(cols) row list or
+	// value of a tuple (native and ?N parameters are passed once).
+	var name string
+	var rv reflect.Value
+	w := make(map[string]int)
+	for _, t := range s.toks {
+		if t.name == "" {
+			continue
+		}
+		key := strings.ToLower(t.name)
+		val, _ := lut.lookup(key)
+		v := reflect.ValueOf(val)
+		each := max(len(t.cols), 1)
+		if t.cols == nil && isSliceOrArray(v) && v.Len() > 0 {
+			if e := reflect.ValueOf(v.Index(0).Interface()); isSliceOrArray(e) {
+				each = e.Len()
+			}
+		}
+		if !ph.passesOnce() {
+			w[key] += each
+		} else {
+			w[key] = each
+		}
+		if (isSliceOrArray(v) || t.cols != nil) && (!rv.IsValid() || v.Len() > rv.Len()) {
+			name, rv = key, v
+		}
+	}
+	if !rv.IsValid() {
+		return nil, fmt.Errorf("xsql: statement has %d args, over the limit of %d, and no slice parameter to split", len(args), limit)
+	}
+	k := w[name]
+	n := rv.Len()
+	per := (limit - (len(args) - k*n)) / k
+	if per < 1 {
+		return nil, fmt.Errorf("xsql: cannot split :%s to fit the limit of %d args", name, limit)
+	}
+
+	out := make([]Statement, 0, (n+per-1)/per)
+	for lo := 0; lo < n; lo += per {
+		hi := min(lo+per, n)
+		chunk := make([]any, hi-lo)
+		for i := range chunk {
+			chunk[i] = rv.Index(lo + i).Interface()
+		}
+		m := maps.Clone(lut.m)
+		m[name] = chunk
+		q, args, err := s.Bind(m)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, Statement{Query: q, Args: args})
+	}
+	return out, nil
+}
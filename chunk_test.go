@@ -0,0 +1,59 @@
+package xsql
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+)
+
+func TestRebindChunked(t *testing.T) {
+	type filter struct {
+		Tenant int   `db:"tenant"`
+		IDs    []int `db:"ids"`
+	}
+	f := filter{Tenant: 9, IDs: []int{1, 2, 3, 4, 5}}
+
+	stmts, err := RebindChunked(`SELECT * FROM t WHERE tenant=:tenant AND id IN (:ids)`, PlaceholderDollar, 3, f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Statement{
+		{`SELECT * FROM t WHERE tenant=$1 AND id IN ($2,$3)`, []any{9, 1, 2}},
+		{`SELECT * FROM t WHERE tenant=$1 AND id IN ($2,$3)`, []any{9, 3, 4}},
+		{`SELECT * FROM t WHERE tenant=$1 AND id IN ($2)`, []any{9, 5}},
+	}
+	if !reflect.DeepEqual(stmts, want) {
+		t.Fatalf("got %#v\nwant %#v", stmts, want)
+	}
+
+	// Under the limit: a single statement, identical to Rebind.
+	stmts, err = RebindChunked(`SELECT * FROM t WHERE id IN (:ids)`, PlaceholderQuestion, 10, f)
+	if err != nil || len(stmts) != 1 || stmts[0].Query != `SELECT * FROM t WHERE id IN (?,?,?,?,?)` {
+		t.Fatalf("unsplit: %#v, %v", stmts, err)
+	}
+
+	// A repeated slice counts once per occurrence, except for native names.
+	stmts, err = RebindChunked(`SELECT :ids UNION SELECT :ids`, PlaceholderQuestion, 4, map[string]any{"ids": []int{1, 2, 3}})
+	if err != nil || len(stmts) != 2 || len(stmts[0].Args) != 4 || len(stmts[1].Args) != 2 {
+		t.Fatalf("repeated: %#v, %v", stmts, err)
+	}
+	stmts, err = RebindChunked(`SELECT :ids UNION SELECT :ids`, PlaceholderAtName, 2, map[string]any{"ids": []int{1, 2, 3}})
+	if err != nil || len(stmts) != 2 || !reflect.DeepEqual(stmts[1].Args, []any{sql.Named("ids_1", 3)}) {
+		t.Fatalf("native: %#v, %v", stmts, err)
+	}
+
+	for name, tc := range map[string]struct {
+		max    int
+		params any
+	}{
+		"no slice":    {1, map[string]any{"tenant": 1, "ids": 2}},
+		"no room":     {1, f},
+		"bad limit":   {0, f},
+		"positional":  {10, 1},
+		"missing key": {10, map[string]any{"ids": []int{1}}},
+	} {
+		if _, err := RebindChunked(`SELECT :tenant, :ids`, PlaceholderQuestion, tc.max, tc.params); err == nil {
+			t.Fatalf("%s: expected error", name)
+		}
+	}
+}
@@ -0,0 +1,218 @@
+// Command xsqlgen generates reflection-free field accessors for structs
+// scanned by github.com/go-mizu/xsql.
+//
+// For each named struct type T it writes a function
+//
+//	func xsqlTField(v *T, i int) any
+//
+// that returns the address of the field of *v at struct index i, the list
+// xsqlTFields of the field names it covers, and an init func that registers
+// both with xsql.RegisterFields for every mapper. With -init=false the init
+// func is left out, so the accessors can be registered on chosen mappers
+// with Mapper.RegisterFields instead. Mappers keep resolving
+// columns to fields with their own rules (tags, name mapper, strictness,
+// duplicate policy) and conversions (converters, enums, unmarshalers, time
+// location, …), once per result set; the accessor only replaces the
+// reflection they use to reach a field on every row. Rows whose columns all
+// scan straight into top-level fields skip reflection entirely. Typical use is
+// a go:generate directive next to the type:
+//
+//	//go:generate go run github.com/go-mizu/xsql/cmd/xsqlgen -type User,Order
+//
+// Flags:
+//
+//	-type    comma-separated list of struct type names; required
+//	-output  output file name; default <first type>_fields.go in lower case
+//	-dir     directory of the package holding the types; default "."
+//	-init    register the accessors process-wide in an init func; default true
+//
+// Rerun it whenever a struct's fields change: the registration panics at
+// startup if the accessors no longer match the struct.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+func main() {
+	typeNames := flag.String("type", "", "comma-separated list of struct type names; required")
+	output := flag.String("output", "", "output file name; default <first type>_fields.go")
+	dir := flag.String("dir", ".", "directory of the package holding the types")
+	register := flag.Bool("init", true, "register the accessors process-wide in an init func")
+	flag.Parse()
+	if *typeNames == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	types := strings.Split(*typeNames, ",")
+	name := *output
+	if name == "" {
+		name = strings.ToLower(types[0]) + "_fields.go"
+	}
+	src, err := generate(*dir, name, types, *register)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "xsqlgen:", err)
+		os.Exit(1)
+	}
+	if err := os.WriteFile(filepath.Join(*dir, name), src, 0o644); err != nil {
+		fmt.Fprintln(os.Stderr, "xsqlgen:", err)
+		os.Exit(1)
+	}
+}
+
+// structType is a struct type to generate accessors for.
+type structType struct {
+	name   string
+	fields []string // per struct index: the field name, or "" if not accessible
+}
+
+// generate returns the formatted source of the accessors for the named types
+// of the package in dir, skipping test files and the file named output. With
+// register set it includes an init func registering them process-wide.
+func generate(dir, output string, typeNames []string, register bool) ([]byte, error) {
+	pkg, decls, err := parseStructs(dir, output)
+	if err != nil {
+		return nil, err
+	}
+	types := make([]structType, 0, len(typeNames))
+	for _, name := range typeNames {
+		ts, ok := decls[name]
+		if !ok {
+			return nil, fmt.Errorf("struct type %s not found in %s", name, dir)
+		}
+		if ts.TypeParams != nil {
+			return nil, fmt.Errorf("%s: generic types are not supported", name)
+		}
+		types = append(types, newStructType(name, ts.Type.(*ast.StructType)))
+	}
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by xsqlgen; DO NOT EDIT.\n\n")
+	fmt.Fprintf(&b, "package %s\n\n", pkg)
+	if register {
+		fmt.Fprintf(&b, "import \"github.com/go-mizu/xsql\"\n\n")
+		fmt.Fprintf(&b, "func init() {\n")
+		for _, t := range types {
+			fmt.Fprintf(&b, "\txsql.RegisterFields(%s, %s...)\n", t.funcName(), t.namesVar())
+		}
+		fmt.Fprintf(&b, "}\n")
+	}
+	for _, t := range types {
+		t.write(&b)
+	}
+	return format.Source(b.Bytes())
+}
+
+// parseStructs returns the package name and the struct type declarations in
+// the non-test Go files of dir other than output.
+func parseStructs(dir, output string) (string, map[string]*ast.TypeSpec, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return "", nil, err
+	}
+	fset := token.NewFileSet()
+	pkg := ""
+	structs := make(map[string]*ast.TypeSpec)
+	for _, path := range paths {
+		base := filepath.Base(path)
+		if strings.HasSuffix(base, "_test.go") || base == output {
+			continue
+		}
+		f, err := parser.ParseFile(fset, path, nil, parser.SkipObjectResolution)
+		if err != nil {
+			return "", nil, err
+		}
+		pkg = f.Name.Name
+		ast.Inspect(f, func(n ast.Node) bool {
+			if ts, ok := n.(*ast.TypeSpec); ok {
+				if _, ok := ts.Type.(*ast.StructType); ok {
+					structs[ts.Name.Name] = ts
+				}
+			}
+			return true
+		})
+	}
+	if pkg == "" {
+		return "", nil, fmt.Errorf("no Go files in %s", dir)
+	}
+	return pkg, structs, nil
+}
+
+// newStructType lists the fields of st, the struct type name, by struct
+// index. Unexported and blank fields are left out, as the mapper never scans
+// them directly.
+func newStructType(name string, st *ast.StructType) structType {
+	t := structType{name: name}
+	for _, f := range st.Fields.List {
+		if len(f.Names) == 0 { // embedded: named after its type
+			t.fields = append(t.fields, exported(embeddedName(f.Type)))
+			continue
+		}
+		for _, id := range f.Names {
+			t.fields = append(t.fields, exported(id.Name))
+		}
+	}
+	return t
+}
+
+// exported returns name if it is exported, or "".
+func exported(name string) string {
+	if !token.IsExported(name) {
+		return ""
+	}
+	return name
+}
+
+// embeddedName returns the field name of an embedded field of type x.
+func embeddedName(x ast.Expr) string {
+	switch x := x.(type) {
+	case *ast.Ident:
+		return x.Name
+	case *ast.StarExpr:
+		return embeddedName(x.X)
+	case *ast.SelectorExpr:
+		return x.Sel.Name
+	case *ast.IndexExpr: // instantiated generic type
+		return embeddedName(x.X)
+	case *ast.IndexListExpr:
+		return embeddedName(x.X)
+	}
+	return ""
+}
+
+func (t structType) funcName() string {
+	return "xsql" + strings.ToUpper(t.name[:1]) + t.name[1:] + "Field"
+}
+
+func (t structType) namesVar() string { return t.funcName() + "s" }
+
+// write writes the field name list and the accessor function for t to b.
+func (t structType) write(b *bytes.Buffer) {
+	fmt.Fprintf(b, "\n// %s lists the fields %s covers by struct index.\n", t.namesVar(), t.funcName())
+	fmt.Fprintf(b, "var %s = []string{", t.namesVar())
+	for i, f := range t.fields {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(strconv.Quote(f))
+	}
+	fmt.Fprintf(b, "}\n")
+	fmt.Fprintf(b, "\n// %s returns the address of the field of v at struct index i, or nil.\n", t.funcName())
+	fmt.Fprintf(b, "func %s(v *%s, i int) any {\n\tswitch i {\n", t.funcName(), t.name)
+	for i, f := range t.fields {
+		if f != "" {
+			fmt.Fprintf(b, "\tcase %d:\n\t\treturn &v.%s\n", i, f)
+		}
+	}
+	fmt.Fprintf(b, "\t}\n\treturn nil\n}\n")
+}
@@ -0,0 +1,219 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, src := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(src), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+const modelsSrc = `package main
+
+import "time"
+
+type ID int64
+
+type Base struct{ Version int }
+
+type Box[T any] struct{ V T }
+
+type User struct {
+	ID
+	Email     string    ` + "`db:\"email|mail,required\"`" + `
+	Name      *string
+	CreatedAt time.Time
+	secret    string
+	Active    bool
+	Base
+	_         int
+	Score, Rank int32
+}
+`
+
+func TestGenerate(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{"models.go": modelsSrc})
+	got, err := generate(dir, "user_fields.go", []string{"User"}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	accessors := `
+// xsqlUserFields lists the fields xsqlUserField covers by struct index.
+var xsqlUserFields = []string{"ID", "Email", "Name", "CreatedAt", "", "Active", "Base", "", "Score", "Rank"}
+
+// xsqlUserField returns the address of the field of v at struct index i, or nil.
+func xsqlUserField(v *User, i int) any {
+	switch i {
+	case 0:
+		return &v.ID
+	case 1:
+		return &v.Email
+	case 2:
+		return &v.Name
+	case 3:
+		return &v.CreatedAt
+	case 5:
+		return &v.Active
+	case 6:
+		return &v.Base
+	case 8:
+		return &v.Score
+	case 9:
+		return &v.Rank
+	}
+	return nil
+}
+`
+	want := `// Code generated by xsqlgen; DO NOT EDIT.
+
+package main
+
+import "github.com/go-mizu/xsql"
+
+func init() {
+	xsql.RegisterFields(xsqlUserField, xsqlUserFields...)
+}
+` + accessors
+	if string(got) != want {
+		t.Fatalf("generated:\n%s\nwant:\n%s", got, want)
+	}
+
+	got, err = generate(dir, "user_fields.go", []string{"User"}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "// Code generated by xsqlgen; DO NOT EDIT.\n\npackage main\n" + accessors; string(got) != want {
+		t.Fatalf("generated without init:\n%s\nwant:\n%s", got, want)
+	}
+
+	for typ, msg := range map[string]string{
+		"Missing": "struct type Missing not found",
+		"Box":     "Box: generic types are not supported",
+	} {
+		if _, err := generate(dir, "out.go", []string{typ}, true); err == nil || !strings.Contains(err.Error(), msg) {
+			t.Errorf("%s: err = %v, want %q", typ, err, msg)
+		}
+	}
+}
+
+// mainSrc scans rows from a fake driver into User with a snake_case mapper
+// and a converter, so that the generated accessors and the mapper's
+// conversions both take part.
+const mainSrc = `package main
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"reflect"
+	"time"
+
+	"github.com/go-mizu/xsql"
+)
+
+type fakeConn struct{}
+
+func (fakeConn) Prepare(string) (driver.Stmt, error) { return nil, driver.ErrSkip }
+func (fakeConn) Close() error                        { return nil }
+func (fakeConn) Begin() (driver.Tx, error)           { return nil, driver.ErrSkip }
+func (fakeConn) QueryContext(_ context.Context, q string, _ []driver.NamedValue) (driver.Rows, error) {
+	return &fakeRows{}, nil
+}
+
+type fakeConnector struct{}
+
+func (fakeConnector) Connect(context.Context) (driver.Conn, error) { return fakeConn{}, nil }
+func (fakeConnector) Driver() driver.Driver                        { return nil }
+
+type fakeRows struct{ n int }
+
+func (*fakeRows) Columns() []string {
+	return []string{"id", "MAIL", "name", "created_at", "active", "version", "score", "rank", "extra"}
+}
+func (*fakeRows) Close() error { return nil }
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.n == 2 {
+		return io.EOF
+	}
+	r.n++
+	var name any
+	if r.n == 1 {
+		name = "ann"
+	}
+	vals := []driver.Value{int64(r.n), "u@x", name, time.Unix(0, 0).UTC(), "t", int64(3), int64(10 * r.n), "7", nil}
+	copy(dest, vals)
+	return nil
+}
+
+func main() {
+	db := sql.OpenDB(fakeConnector{})
+	m := xsql.NewMapper(xsql.WithNameMapper(xsql.SnakeCase))
+	m.RegisterConverter(reflect.TypeOf(int32(0)), func(src any) (any, error) {
+		if s, ok := src.(string); ok {
+			return int32(len(s) * 100), nil
+		}
+		return int32(src.(int64)), nil
+	})
+	users, err := xsql.QueryWith[User](context.Background(), m, db, "q")
+	if err != nil {
+		fmt.Println("error:", err)
+		return
+	}
+	for _, u := range users {
+		name := "<nil>"
+		if u.Name != nil {
+			name = *u.Name
+		}
+		fmt.Println(u.ID, u.Email, name, u.CreatedAt.Unix(), u.Active, u.Version, u.Score, u.Rank)
+	}
+}
+`
+
+func TestGenerate_BuildsAndScans(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds a module with the go command")
+	}
+	goBin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go command not found")
+	}
+	root, err := filepath.Abs("../..")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"go.mod":    "module example.com/gen\n\ngo 1.24\n\nrequire github.com/go-mizu/xsql v0.0.0\n\nreplace github.com/go-mizu/xsql => " + root + "\n",
+		"models.go": modelsSrc,
+		"main.go":   mainSrc,
+	})
+	src, err := generate(dir, "user_fields.go", []string{"User"}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeFiles(t, dir, map[string]string{"user_fields.go": string(src)})
+
+	cmd := exec.Command(goBin, "run", ".")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod", "GOWORK=off")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("go run: %v\n%s", err, out)
+	}
+	want := "1 u@x ann 0 true 3 10 100\n2 u@x <nil> 0 true 3 20 100\n"
+	if string(out) != want {
+		t.Fatalf("output:\n%s\nwant:\n%s", out, want)
+	}
+}
@@ -0,0 +1,131 @@
+package xsql
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// EncodeFunc serializes v, a pointer to the field value, for storage in a
+// column.
+type EncodeFunc func(v any) ([]byte, error)
+
+// DecodeFunc deserializes data into v, a pointer to a new field value.
+type DecodeFunc func(data []byte, v any) error
+
+type codec struct {
+	enc EncodeFunc
+	dec DecodeFunc
+}
+
+var codecs = struct {
+	mu sync.RWMutex
+	m  map[string]codec
+}{m: map[string]codec{
+	"json": {enc: json.Marshal, dec: json.Unmarshal},
+}}
+
+// RegisterCodec registers a named serialization format for struct fields
+// tagged `db:"col,codec=name"`. Scanning such a field reads the column as
+// bytes and calls dec with a pointer to a new value of the field's type
+// (the element type for pointer fields); binding the struct with the named
+// functions (NamedExec, Rebind, …) passes enc a pointer to the field value
+// and sends the bytes to the driver. NULL columns leave the field zero, and
+// nil pointer, slice, or map fields bind as NULL.
+//
+// A "json" codec backed by encoding/json is built in. Registering a name again
+// replaces the codec; plans built before the call are not reused.
+//
+// Example:
+//
+//	xsql.RegisterCodec("proto",
+//	    func(v any) ([]byte, error) { return proto.Marshal(v.(proto.Message)) },
+//	    func(b []byte, v any) error { return proto.Unmarshal(b, v.(proto.Message)) })
+//
+//	type Event struct {
+//	    ID      int64          `db:"id"`
+//	    Payload *pb.Payload    `db:"payload,codec=proto"`
+//	    Meta    map[string]any `db:"meta,codec=json"`
+//	}
+func RegisterCodec(name string, enc EncodeFunc, dec DecodeFunc) {
+	codecs.mu.Lock()
+	defer codecs.mu.Unlock()
+	codecs.m[name] = codec{enc: enc, dec: dec}
+	globalConverters.gen.Add(1) // codecs are resolved while planning
+}
+
+func lookupCodec(name string) (codec, error) {
+	codecs.mu.RLock()
+	defer codecs.mu.RUnlock()
+	c, ok := codecs.m[name]
+	if !ok {
+		return codec{}, fmt.Errorf("xsql: unknown codec %q", name)
+	}
+	return c, nil
+}
+
+// pickCodec returns a step conversion for a field tagged with codec=name: the
+// column is scanned into an interface value and its bytes decoded.
+func pickCodec(dstType reflect.Type, name string) (reflect.Type, func(dst, src reflect.Value) error, error) {
+	c, err := lookupCodec(name)
+	if err != nil {
+		return nil, nil, err
+	}
+	return reflect.TypeOf((*any)(nil)).Elem(), func(dst, src reflect.Value) error {
+		var data []byte
+		switch v := src.Interface().(type) {
+		case nil:
+			dst.SetZero()
+			return nil
+		case []byte:
+			data = v
+		case string:
+			data = []byte(v)
+		default:
+			return fmt.Errorf("xsql: codec %s: cannot decode %T into %s", name, v, dstType)
+		}
+		if dstType.Kind() == reflect.Ptr {
+			p := reflect.New(dstType.Elem())
+			if err := c.dec(data, p.Interface()); err != nil {
+				return fmt.Errorf("xsql: codec %s: decode %s: %w", name, dstType, err)
+			}
+			dst.Set(p)
+			return nil
+		}
+		p := reflect.New(dstType)
+		if err := c.dec(data, p.Interface()); err != nil {
+			return fmt.Errorf("xsql: codec %s: decode %s: %w", name, dstType, err)
+		}
+		dst.Set(p.Elem())
+		return nil
+	}, nil
+}
+
+// encodeField serializes a struct field tagged with codec=name for use as a
+// query argument.
+func encodeField(v reflect.Value, name string) (any, error) {
+	c, err := lookupCodec(name)
+	if err != nil {
+		return nil, err
+	}
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Map, reflect.Interface:
+		if v.IsNil() {
+			return nil, nil
+		}
+	}
+	var p any
+	if v.Kind() == reflect.Ptr {
+		p = v.Interface()
+	} else {
+		pv := reflect.New(v.Type())
+		pv.Elem().Set(v)
+		p = pv.Interface()
+	}
+	b, err := c.enc(p)
+	if err != nil {
+		return nil, fmt.Errorf("xsql: codec %s: encode %s: %w", name, v.Type(), err)
+	}
+	return b, nil
+}
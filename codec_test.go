@@ -0,0 +1,87 @@
+package xsql
+
+import (
+	"bytes"
+	"context"
+	"database/sql/driver"
+	"encoding/gob"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type gobPoint struct{ X, Y int }
+
+func init() {
+	RegisterCodec("gob",
+		func(v any) ([]byte, error) {
+			var buf bytes.Buffer
+			err := gob.NewEncoder(&buf).Encode(v)
+			return buf.Bytes(), err
+		},
+		func(b []byte, v any) error { return gob.NewDecoder(bytes.NewReader(b)).Decode(v) })
+}
+
+func TestCodec_Scan(t *testing.T) {
+	type Row struct {
+		Meta  map[string]int `db:"meta,codec=json"`
+		Tags  []string       `db:",codec=json"`
+		Where *gobPoint      `db:"where,codec=gob"`
+	}
+	var pt bytes.Buffer
+	if err := gob.NewEncoder(&pt).Encode(&gobPoint{X: 1, Y: 2}); err != nil {
+		t.Fatal(err)
+	}
+	db := newTestDB(t, func(q string, _ []driver.NamedValue) ([]string, [][]driver.Value, error) {
+		if q == "bad" {
+			return []string{"meta"}, [][]driver.Value{{"not json"}}, nil
+		}
+		return []string{"meta", "tags", "where"}, [][]driver.Value{
+			{[]byte(`{"a":1}`), `["x","y"]`, pt.Bytes()},
+			{nil, nil, nil},
+		}, nil
+	})
+	defer func() { _ = db.Close() }()
+	ctx := context.Background()
+
+	got, err := Query[Row](ctx, db, "q")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got[0].Meta, map[string]int{"a": 1}) || !reflect.DeepEqual(got[0].Tags, []string{"x", "y"}) ||
+		got[0].Where == nil || *got[0].Where != (gobPoint{1, 2}) {
+		t.Fatalf("row 0: %+v", got[0])
+	}
+	if got[1].Meta != nil || got[1].Tags != nil || got[1].Where != nil {
+		t.Fatalf("row 1: %+v", got[1])
+	}
+
+	if _, err := Query[Row](ctx, db, "bad"); err == nil || !strings.Contains(err.Error(), "codec json") {
+		t.Fatalf("expected decode error, got %v", err)
+	}
+	type Unknown struct {
+		Meta []byte `db:"meta,codec=nope"`
+	}
+	if _, err := Query[Unknown](ctx, db, "bad"); err == nil || !strings.Contains(err.Error(), `unknown codec "nope"`) {
+		t.Fatalf("expected unknown codec error, got %v", err)
+	}
+}
+
+func TestCodec_Bind(t *testing.T) {
+	type Params struct {
+		ID    int64          `db:"id"`
+		Meta  map[string]int `db:"meta,codec=json"`
+		Where *gobPoint      `db:"where,codec=gob"`
+	}
+	q, args, err := Rebind(`UPDATE t SET meta = :meta, at = :where WHERE id = :id`, PlaceholderDollar,
+		Params{ID: 3, Meta: map[string]int{"a": 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q != `UPDATE t SET meta = $1, at = $2 WHERE id = $3` {
+		t.Fatalf("query = %q", q)
+	}
+	if string(args[0].([]byte)) != `{"a":1}` || args[1] != nil || args[2] != int64(3) {
+		t.Fatalf("args = %#v", args)
+	}
+}
@@ -0,0 +1,52 @@
+package xsql
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ColumnsOf returns the column names struct type T maps, in field declaration
+// order, as the default mapper sees them: `db` tags, inline prefixes, and the
+// mapper's name mapping for untagged fields all apply. Fields hidden by an
+// earlier field with the same column, and nested structs that are not
+// inlined, are left out. For types that are not
+// mapped column by column (primitives, scanners) it returns nil.
+//
+// The result is a fresh slice the caller may modify.
+//
+// Example:
+//
+//	type User struct {
+//	    ID    int64  `db:"id"`
+//	    Email string `db:"email"`
+//	}
+//	cols := xsql.ColumnsOf[User]() // ["id", "email"]
+func ColumnsOf[T any]() []string {
+	m := getMapper()
+	rt := reflect.TypeOf((*T)(nil)).Elem()
+	if !m.isRowStruct(rt) {
+		return nil
+	}
+	return append([]string(nil), m.structIndex(rt).columns...)
+}
+
+// SelectFor returns "SELECT <columns of T> FROM table", keeping explicit
+// column lists in sync with the struct instead of relying on SELECT *. Append
+// WHERE, ORDER BY, and other clauses as needed. Column names and table are
+// used verbatim, without quoting.
+//
+// It panics if T maps no columns, since the statement could never be valid.
+//
+// Example:
+//
+//	var selectUsers = xsql.SelectFor[User]("users")
+//
+//	users, err := xsql.Query[User](ctx, db, selectUsers+" WHERE active = $1", true)
+func SelectFor[T any](table string) string {
+	cols := ColumnsOf[T]()
+	if len(cols) == 0 {
+		panic(fmt.Sprintf("xsql: SelectFor: %s maps no columns", reflect.TypeOf((*T)(nil)).Elem()))
+	}
+	return "SELECT " + strings.Join(cols, ", ") + " FROM " + table
+}
@@ -0,0 +1,45 @@
+package xsql
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestColumnsOfAndSelectFor(t *testing.T) {
+	type Address struct {
+		City string `db:"city"`
+	}
+	type Base struct {
+		ID int64 `db:"id"`
+	}
+	type User struct {
+		Base
+		ID      int64   `db:"id"` // hidden by Base.ID, declared first
+		Email   string  `db:"Email"`
+		Home    Address `db:"home_,inline"`
+		Skip    string  `db:"-"`
+		Profile struct {
+			Bio string `db:"bio"`
+		} `db:"profile"`
+	}
+	want := []string{"id", "Email", "home_city"} // nested Profile is reached via "profile.bio"
+	if got := ColumnsOf[User](); !reflect.DeepEqual(got, want) {
+		t.Fatalf("ColumnsOf = %v, want %v", got, want)
+	}
+	if got := ColumnsOf[*User](); !reflect.DeepEqual(got, want) {
+		t.Fatalf("ColumnsOf[*User] = %v", got)
+	}
+	if got := ColumnsOf[int64](); got != nil {
+		t.Fatalf("ColumnsOf[int64] = %v, want nil", got)
+	}
+
+	if got := SelectFor[Base]("users u"); got != "SELECT id FROM users u" {
+		t.Fatalf("SelectFor = %q", got)
+	}
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("SelectFor on a type without columns should panic")
+		}
+	}()
+	_ = SelectFor[int]("t")
+}
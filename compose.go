@@ -0,0 +1,55 @@
+package xsql
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Fragment is a piece of SQL written with ? placeholders and the args for
+// them, to be assembled by [Compose].
+type Fragment struct {
+	SQL  string
+	Args []any
+}
+
+// Frag returns the Fragment for sql and args.
+func Frag(sql string, args ...any) Fragment {
+	return Fragment{SQL: sql, Args: args}
+}
+
+// Compose joins the fragments with single spaces, skipping empty ones, and
+// rewrites their ? placeholders for ph, numbering them across the whole
+// statement. Each fragment must have as many ? placeholders as args; slice
+// args expand as with [In]. This makes dynamic WHERE clauses independent of
+// the placeholder style and free of index bookkeeping.
+//
+// Example:
+//
+//	frags := []xsql.Fragment{xsql.Frag(`SELECT id, email FROM users WHERE tenant_id = ?`, tenant)}
+//	if status != "" {
+//	    frags = append(frags, xsql.Frag(`AND status = ?`, status))
+//	}
+//	if len(ids) > 0 {
+//	    frags = append(frags, xsql.Frag(`AND id IN (?)`, ids))
+//	}
+//	q, args, err := xsql.Compose(xsql.PlaceholderDollar, frags...)
+//	// q => SELECT id, email FROM users WHERE tenant_id = $1 AND status = $2 AND id IN ($3,$4)
+func Compose(ph Placeholder, frags ...Fragment) (string, []any, error) {
+	parts := make([]string, 0, len(frags))
+	var args []any
+	for i, f := range frags {
+		if strings.TrimSpace(f.SQL) == "" {
+			if len(f.Args) > 0 {
+				return "", nil, fmt.Errorf("xsql: fragment %d has args but no SQL", i+1)
+			}
+			continue
+		}
+		q, a, err := In(f.SQL, f.Args...)
+		if err != nil {
+			return "", nil, fmt.Errorf("xsql: fragment %d: %w", i+1, err)
+		}
+		parts = append(parts, strings.TrimSpace(q))
+		args = append(args, a...)
+	}
+	return rewritePlaceholders(strings.Join(parts, " "), ph), args, nil
+}
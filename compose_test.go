@@ -0,0 +1,34 @@
+package xsql
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCompose(t *testing.T) {
+	frags := []Fragment{
+		Frag(`SELECT id FROM users WHERE tenant_id = ?`, 9),
+		Frag(""),
+		Frag(`  AND status = ? `, "on"),
+		Frag(`AND id IN (?) AND note <> '?'`, []int{1, 2}),
+		Frag(`AND data ?| ?`, "k"),
+	}
+	q, args, err := Compose(PlaceholderDollar, frags...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `SELECT id FROM users WHERE tenant_id = $1 AND status = $2 AND id IN ($3,$4) AND note <> '?' AND data ?| $5`; q != want {
+		t.Fatalf("sql mismatch:\n got=%q\nwant=%q", q, want)
+	}
+	eqSlice(t, args, []any{9, "on", 1, 2, "k"}, "args")
+
+	if q, _, _ := Compose(PlaceholderColonNum, frags[:3]...); q != `SELECT id FROM users WHERE tenant_id = :1 AND status = :2` {
+		t.Fatalf("oracle: %q", q)
+	}
+	if _, _, err := Compose(PlaceholderDollar, Frag(`a = ?`)); err == nil || !strings.Contains(err.Error(), "fragment 1") {
+		t.Fatalf("expected arg count error, got %v", err)
+	}
+	if _, _, err := Compose(PlaceholderDollar, Frag(" ", 1)); err == nil {
+		t.Fatal("expected error for args without SQL")
+	}
+}
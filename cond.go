@@ -0,0 +1,101 @@
+package xsql
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// condBlock is a conditional section of a named query: the text from a
+// /*:if name*/ comment through its matching /*:end*/ comment.
+type condBlock struct {
+	name  string
+	start int // offset of the /*:if name*/ comment
+	end   int // offset just past the /*:end*/ comment
+}
+
+// findConditions returns the /*:if name*/ … /*:end*/ sections of query,
+// ordered by start. Sections may nest; the markers are recognized only in
+// code, not inside quoted strings or dollar-quoted bodies.
+func findConditions(query string) ([]condBlock, error) {
+	if !strings.Contains(query, "/*:") {
+		return nil, nil
+	}
+	var out, open []condBlock
+	i := 0
+	for i < len(query) {
+		j, err := skipNonCode(query, i)
+		if err != nil {
+			return nil, err
+		}
+		if j == i {
+			i++
+			continue
+		}
+		if hasPrefix(query[i:], "/*:") {
+			body := strings.TrimSpace(query[i+2 : j-2])
+			switch {
+			case body == ":end":
+				if len(open) == 0 {
+					return nil, fmt.Errorf("xsql: named bind: /*:end*/ without /*:if*/ at offset %d", i)
+				}
+				b := open[len(open)-1]
+				open = open[:len(open)-1]
+				b.end = j
+				out = append(out, b)
+			case hasPrefix(body, ":if ") || hasPrefix(body, ":if\t"):
+				name, end := parseParamName(body, skipSpaces(body, 3))
+				if name == "" || end != len(body) {
+					return nil, fmt.Errorf("xsql: named bind: invalid condition %q", query[i:j])
+				}
+				open = append(open, condBlock{name: name, start: i})
+			}
+		}
+		i = j
+	}
+	if len(open) > 0 {
+		b := open[len(open)-1]
+		return nil, fmt.Errorf("xsql: named bind: /*:if %s*/ at offset %d has no /*:end*/", b.name, b.start)
+	}
+	sort.Slice(out, func(a, b int) bool { return out[a].start < out[b].start })
+	return out, nil
+}
+
+// applyConditions removes the sections of query whose parameter is empty in
+// lut (see emptyParam) and returns the remaining text. Kept sections keep
+// their marker comments.
+func applyConditions(query string, conds []condBlock, lut *paramLookup) string {
+	var b strings.Builder
+	last := 0
+	for _, c := range conds {
+		if c.start < last {
+			continue // inside a removed section
+		}
+		if val, ok := lut.lookup(c.name); ok && !emptyParam(val) {
+			continue
+		}
+		b.WriteString(query[last:c.start])
+		last = c.end
+	}
+	if last == 0 {
+		return query
+	}
+	b.WriteString(query[last:])
+	return b.String()
+}
+
+// emptyParam reports whether a condition on a parameter holding v is false:
+// v is nil, a nil pointer, or an empty slice, array, map, or string.
+func emptyParam(v any) bool {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Invalid:
+		return true
+	case reflect.Pointer, reflect.Interface:
+		return rv.IsNil()
+	case reflect.Slice, reflect.Array, reflect.Map, reflect.String:
+		return rv.Len() == 0
+	}
+	return false
+}
@@ -0,0 +1,78 @@
+package xsql
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRebind_Conditions(t *testing.T) {
+	const q = `SELECT id FROM users WHERE tenant_id = :tenant` +
+		` /*:if status*/AND status = :status/*:end*/` +
+		` /*:if ids*/AND id IN (:ids) /*:if note*/AND note = ':x /*:end*/'/*:end*//*:end*/`
+
+	for name, tc := range map[string]struct {
+		params any
+		want   string
+		args   []any
+	}{
+		"all removed": {
+			map[string]any{"tenant": 1, "status": "", "ids": []int{}},
+			`SELECT id FROM users WHERE tenant_id = $1  `,
+			[]any{1},
+		},
+		"absent": {
+			map[string]any{"tenant": 1},
+			`SELECT id FROM users WHERE tenant_id = $1  `,
+			[]any{1},
+		},
+		"outer kept, inner removed": {
+			map[string]any{"tenant": 1, "status": "on", "ids": []int{7, 8}},
+			`SELECT id FROM users WHERE tenant_id = $1 /*:if status*/AND status = $2/*:end*/ /*:if ids*/AND id IN ($3,$4) /*:end*/`,
+			[]any{1, "on", 7, 8},
+		},
+		"struct": {
+			struct {
+				Tenant int     `db:"tenant"`
+				Status *string `db:"status"`
+				IDs    []int   `db:"ids"`
+				Note   string  `db:"note"`
+			}{Tenant: 2, IDs: []int{5}, Note: "n"},
+			`SELECT id FROM users WHERE tenant_id = $1  /*:if ids*/AND id IN ($2) /*:if note*/AND note = ':x /*:end*/'/*:end*//*:end*/`,
+			[]any{2, 5},
+		},
+	} {
+		got, args, err := Rebind(q, PlaceholderDollar, tc.params)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if got != tc.want {
+			t.Fatalf("%s: sql mismatch:\n got=%q\nwant=%q", name, got, tc.want)
+		}
+		eqSlice(t, args, tc.args, name)
+
+		// NamedStmt, native placeholders, and strict mode agree.
+		s, err := PrepareNamed(q, PlaceholderDollar)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got2, _, err := s.Bind(tc.params, WithStrictParams()); err != nil || got2 != got {
+			t.Fatalf("%s: NamedStmt: %q, %v", name, got2, err)
+		}
+	}
+
+	if q, args, err := Rebind(`a /*:if x*/AND b = :x/*:end*/`, PlaceholderAtName, map[string]any{"x": 0}); err != nil ||
+		q != `a /*:if x*/AND b = @x/*:end*/` || len(args) != 1 {
+		t.Fatalf("native: %q %v %v", q, args, err)
+	}
+
+	for _, bad := range []string{
+		`a /*:if x*/ b`,
+		`a /*:end*/`,
+		`a /*:if x y*/ b /*:end*/`,
+		`a /*:if */ b /*:end*/`,
+	} {
+		if _, _, err := Rebind(bad, PlaceholderQuestion, map[string]any{"x": 1}); err == nil || !strings.Contains(err.Error(), "xsql: named bind") {
+			t.Fatalf("%q: expected error, got %v", bad, err)
+		}
+	}
+}
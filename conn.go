@@ -0,0 +1,39 @@
+package xsql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// WithConn reserves one connection from db's pool, runs fn with it, and
+// returns it to the pool, even if fn panics. Use it for work that depends on
+// session state kept by the connection across several calls: SET statements,
+// temporary tables, advisory locks. fn's error is returned; when fn succeeds,
+// an error closing the connection is returned instead.
+//
+// Session state outlives WithConn: the connection goes back to the pool as it
+// is, so reset anything later users must not see (RESET ALL, DROP TABLE,
+// pg_advisory_unlock) before returning.
+//
+// Example:
+//
+//	err := xsql.WithConn(ctx, db, func(c *sql.Conn) error {
+//	    if _, err := xsql.Exec(ctx, c, `SELECT pg_advisory_lock($1)`, jobID); err != nil {
+//	        return err
+//	    }
+//	    defer xsql.Exec(ctx, c, `SELECT pg_advisory_unlock($1)`, jobID)
+//	    return runJob(ctx, c)
+//	})
+func WithConn(ctx context.Context, db Conner, fn func(c *sql.Conn) error) (err error) {
+	c, err := db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := c.Close(); cerr != nil && err == nil {
+			err = fmt.Errorf("xsql: close conn: %w", cerr)
+		}
+	}()
+	return fn(c)
+}
@@ -0,0 +1,53 @@
+package xsql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"testing"
+)
+
+func TestWithConn(t *testing.T) {
+	db := newTestDB(t, func(string, []driver.NamedValue) ([]string, [][]driver.Value, error) {
+		return []string{"n"}, [][]driver.Value{{int64(1)}}, nil
+	})
+	defer func() { _ = db.Close() }()
+	ctx := context.Background()
+
+	var conn *sql.Conn
+	err := WithConn(ctx, db, func(c *sql.Conn) error {
+		conn = c
+		n, err := Get[int64](ctx, c, `SELECT 1`)
+		if err == nil && n != 1 {
+			t.Errorf("n = %d", n)
+		}
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := conn.PingContext(ctx); !errors.Is(err, sql.ErrConnDone) {
+		t.Fatalf("conn not closed: %v", err)
+	}
+
+	boom := errors.New("boom")
+	if err := WithConn(ctx, db, func(c *sql.Conn) error { conn = c; return boom }); err != boom {
+		t.Fatalf("err = %v", err)
+	}
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("expected panic")
+			}
+		}()
+		_ = WithConn(ctx, db, func(c *sql.Conn) error { conn = c; panic("oops") })
+	}()
+	if err := conn.PingContext(ctx); !errors.Is(err, sql.ErrConnDone) {
+		t.Fatalf("conn not closed after panic: %v", err)
+	}
+	if db.Stats().InUse != 0 {
+		t.Fatalf("connections in use: %d", db.Stats().InUse)
+	}
+}
@@ -0,0 +1,372 @@
+package xsql
+
+import (
+	"bytes"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// converterRegistry holds user-supplied conversions: converters turn scanned
+// column values into destination types, and valuers turn argument values into
+// driver values. It is safe for concurrent use.
+type converterRegistry struct {
+	mu       sync.RWMutex
+	convs    map[reflect.Type]func(any) (any, error)
+	valuers  map[reflect.Type]func(any) (driver.Value, error)
+	impls    map[reflect.Type]reflect.Type       // interface -> concrete scan type
+	fields   map[reflect.Type]func(any, int) any // struct -> field accessor; nil opts out
+	gen      atomic.Uint64                       // bumped on every converter change; part of plan cache keys
+	nvaluers atomic.Int32                        // lets argument conversion skip the lock when empty
+}
+
+// globalConverters is the process-wide registry behind RegisterConverter and
+// RegisterValuer.
+var globalConverters converterRegistry
+
+// RegisterConverter registers fn to build values of type T from scanned
+// column values, process-wide. Fields of type T or *T, and T itself as the
+// type parameter of Query or Get, are then scanned through fn instead of the
+// built-in rules, including sql.Scanner.
+//
+// fn receives the value as delivered by the driver (int64, float64, bool,
+// []byte, string, time.Time) or nil for NULL. For *T destinations NULL yields
+// a nil pointer without calling fn. Registering a converter for a type that
+// already has one replaces it; plans built before the call are not reused.
+//
+// Example:
+//
+//	xsql.RegisterConverter(func(src any) (decimal.Decimal, error) {
+//	    switch v := src.(type) {
+//	    case nil:
+//	        return decimal.Zero, nil
+//	    case []byte:
+//	        return decimal.NewFromString(string(v))
+//	    case string:
+//	        return decimal.NewFromString(v)
+//	    case float64:
+//	        return decimal.NewFromFloat(v), nil
+//	    }
+//	    return decimal.Zero, fmt.Errorf("unsupported %T", src)
+//	})
+func RegisterConverter[T any](fn func(src any) (T, error)) {
+	globalConverters.setConverter(reflect.TypeOf((*T)(nil)).Elem(), func(src any) (any, error) { return fn(src) })
+}
+
+// RegisterValuer registers fn to turn arguments of type T into driver values,
+// process-wide. Query, Get, Exec, and the functions built on them apply it to
+// every argument of type T (including sql.Named values) before calling the
+// driver. It is the parameter-side counterpart of [RegisterConverter].
+//
+// Example:
+//
+//	xsql.RegisterValuer(func(d decimal.Decimal) (driver.Value, error) {
+//	    return d.String(), nil
+//	})
+func RegisterValuer[T any](fn func(v T) (driver.Value, error)) {
+	globalConverters.setValuer(reflect.TypeOf((*T)(nil)).Elem(), func(v any) (driver.Value, error) { return fn(v.(T)) })
+}
+
+func (r *converterRegistry) setConverter(t reflect.Type, fn func(any) (any, error)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.convs == nil {
+		r.convs = make(map[reflect.Type]func(any) (any, error))
+	}
+	r.convs[t] = fn
+	r.gen.Add(1)
+}
+
+func (r *converterRegistry) setValuer(t reflect.Type, fn func(any) (driver.Value, error)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.valuers == nil {
+		r.valuers = make(map[reflect.Type]func(any) (driver.Value, error))
+	}
+	if _, ok := r.valuers[t]; !ok {
+		r.nvaluers.Add(1)
+	}
+	r.valuers[t] = fn
+}
+
+func (r *converterRegistry) converter(t reflect.Type) (func(any) (any, error), bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	fn, ok := r.convs[t]
+	return fn, ok
+}
+
+func (r *converterRegistry) valuer(t reflect.Type) (func(any) (driver.Value, error), bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	fn, ok := r.valuers[t]
+	return fn, ok
+}
+
+// RegisterConverter registers fn to build values of type typ from scanned
+// column values for this mapper only, with the same semantics as the
+// package-level [RegisterConverter]. Converters registered on m take
+// precedence over global ones for the same type, so libraries and tests can
+// configure conversions without touching process-wide state.
+//
+// fn must return values assignable to typ (or nil for the zero value);
+// anything else fails the scan with an error.
+//
+// Example:
+//
+//	m := xsql.NewMapper()
+//	m.RegisterConverter(reflect.TypeOf(decimal.Decimal{}), func(src any) (any, error) {
+//	    return decimal.NewFromString(string(src.([]byte)))
+//	})
+//	prices, err := xsql.QueryWith[decimal.Decimal](ctx, m, db, `SELECT price FROM items`)
+func (m *Mapper) RegisterConverter(typ reflect.Type, fn func(src any) (any, error)) {
+	m.convs.setConverter(typ, fn)
+}
+
+// converter returns the converter for t, preferring m's own registry.
+func (m *Mapper) converter(t reflect.Type) (func(any) (any, error), bool) {
+	if fn, ok := m.convs.converter(t); ok {
+		return fn, true
+	}
+	return globalConverters.converter(t)
+}
+
+// hasConverter reports whether a converter is registered for t or, if t is a
+// pointer, for its element type.
+func (m *Mapper) hasConverter(t reflect.Type) bool {
+	if _, ok := m.converter(t); ok {
+		return true
+	}
+	if t.Kind() == reflect.Ptr {
+		_, ok := m.converter(t.Elem())
+		return ok
+	}
+	return false
+}
+
+// pickConverter returns a step conversion for dstType (T or *T) if a
+// converter is registered for T: the column is scanned into an interface
+// value and passed to the converter.
+func (m *Mapper) pickConverter(dstType reflect.Type) (reflect.Type, func(dst, src reflect.Value) error, bool) {
+	base, isPtr := dstType, false
+	fn, ok := m.converter(base)
+	if !ok && base.Kind() == reflect.Ptr {
+		base, isPtr = base.Elem(), true
+		fn, ok = m.converter(base)
+	}
+	if !ok {
+		return nil, nil, false
+	}
+	return reflect.TypeOf((*any)(nil)).Elem(), func(dst, src reflect.Value) error {
+		v := src.Interface()
+		if v == nil && isPtr {
+			dst.SetZero()
+			return nil
+		}
+		out, err := fn(v)
+		if err != nil {
+			return fmt.Errorf("xsql: convert to %s: %w", base, err)
+		}
+		val := reflect.New(base).Elem()
+		if out != nil {
+			ov := reflect.ValueOf(out)
+			if !ov.Type().AssignableTo(base) {
+				return fmt.Errorf("xsql: converter for %s returned %s", base, ov.Type())
+			}
+			val.Set(ov)
+		}
+		if isPtr {
+			dst.Set(val.Addr())
+		} else {
+			dst.Set(val)
+		}
+		return nil
+	}, true
+}
+
+// convertArgs applies registered valuers to args, returning args itself when
+// nothing needs converting.
+func convertArgs(args []any) ([]any, error) {
+	if globalConverters.nvaluers.Load() == 0 {
+		return args, nil
+	}
+	var out []any
+	for i, a := range args {
+		v, changed, err := convertArg(a)
+		if err != nil {
+			return nil, fmt.Errorf("xsql: arg %d: %w", i+1, err)
+		}
+		if changed && out == nil {
+			out = append(make([]any, 0, len(args)), args[:i]...)
+		}
+		if out != nil {
+			out = append(out, v)
+		}
+	}
+	if out == nil {
+		return args, nil
+	}
+	return out, nil
+}
+
+func convertArg(a any) (any, bool, error) {
+	if na, ok := a.(sql.NamedArg); ok {
+		v, changed, err := convertArg(na.Value)
+		if changed {
+			na.Value = v
+		}
+		return na, changed, err
+	}
+	if a == nil {
+		return a, false, nil
+	}
+	fn, ok := globalConverters.valuer(reflect.TypeOf(a))
+	if !ok {
+		return a, false, nil
+	}
+	v, err := fn(a)
+	return v, true, err
+}
+
+// assignValue stores the driver value src in dst, mirroring the conversions
+// database/sql applies when scanning: sql.Scanner destinations scan src, text
+// parses into numbers, bools and times, numbers format into text, and pointer
+// destinations are allocated (or set to nil for a nil src).
+func assignValue(dst reflect.Value, src any) error {
+	if dst.Kind() == reflect.Ptr {
+		if src == nil {
+			dst.SetZero()
+			return nil
+		}
+		p := reflect.New(dst.Type().Elem())
+		if err := assignValue(p.Elem(), src); err != nil {
+			return err
+		}
+		dst.Set(p)
+		return nil
+	}
+	if dst.CanAddr() {
+		if s, ok := dst.Addr().Interface().(sql.Scanner); ok {
+			return s.Scan(src)
+		}
+	}
+	if src == nil {
+		if dst.Kind() == reflect.Interface || dst.Kind() == reflect.Slice || dst.Kind() == reflect.Map {
+			dst.SetZero()
+			return nil
+		}
+		return fmt.Errorf("xsql: converting NULL to %s is unsupported", dst.Type())
+	}
+	if b, ok := src.([]byte); ok {
+		src = bytes.Clone(b)
+	}
+	sv := reflect.ValueOf(src)
+	if sv.Type().AssignableTo(dst.Type()) {
+		dst.Set(sv)
+		return nil
+	}
+	text, isText := "", false
+	switch v := src.(type) {
+	case string:
+		text, isText = v, true
+	case []byte:
+		text, isText = string(v), true
+	}
+	switch dst.Kind() {
+	case reflect.String:
+		switch v := src.(type) {
+		case time.Time:
+			dst.SetString(v.Format(time.RFC3339Nano))
+		default:
+			if isText {
+				dst.SetString(text)
+			} else {
+				dst.SetString(fmt.Sprint(v))
+			}
+		}
+		return nil
+	case reflect.Slice:
+		if dst.Type().Elem().Kind() == reflect.Uint8 && isText {
+			dst.SetBytes([]byte(text))
+			return nil
+		}
+	case reflect.Bool:
+		b, err := parseBool(src)
+		if err != nil {
+			return err
+		}
+		dst.SetBool(b)
+		return nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var n int64
+		switch {
+		case isText:
+			v, err := strconv.ParseInt(strings.TrimSpace(text), 10, dst.Type().Bits())
+			if err != nil {
+				return fmt.Errorf("xsql: converting %q to %s: %w", text, dst.Type(), err)
+			}
+			n = v
+		case sv.CanInt():
+			n = sv.Int()
+		case sv.CanFloat() && sv.Float() == math.Trunc(sv.Float()):
+			n = int64(sv.Float())
+		default:
+			return fmt.Errorf("xsql: cannot convert %T to %s", src, dst.Type())
+		}
+		if dst.OverflowInt(n) {
+			return fmt.Errorf("xsql: value %d overflows %s", n, dst.Type())
+		}
+		dst.SetInt(n)
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if isText {
+			n, err := strconv.ParseUint(strings.TrimSpace(text), 10, dst.Type().Bits())
+			if err != nil {
+				return fmt.Errorf("xsql: converting %q to %s: %w", text, dst.Type(), err)
+			}
+			dst.SetUint(n)
+			return nil
+		}
+		if sv.CanInt() && sv.Int() >= 0 && !dst.OverflowUint(uint64(sv.Int())) {
+			dst.SetUint(uint64(sv.Int()))
+			return nil
+		}
+	case reflect.Float32, reflect.Float64:
+		switch {
+		case isText:
+			f, err := strconv.ParseFloat(strings.TrimSpace(text), dst.Type().Bits())
+			if err != nil {
+				return fmt.Errorf("xsql: converting %q to %s: %w", text, dst.Type(), err)
+			}
+			dst.SetFloat(f)
+			return nil
+		case sv.CanInt():
+			dst.SetFloat(float64(sv.Int()))
+			return nil
+		case sv.CanFloat():
+			dst.SetFloat(sv.Float())
+			return nil
+		}
+	case reflect.Struct:
+		if dst.Type() == reflect.TypeOf(time.Time{}) && isText {
+			t, err := time.Parse(time.RFC3339Nano, text)
+			if err != nil {
+				return fmt.Errorf("xsql: converting %q to time.Time: %w", text, err)
+			}
+			dst.Set(reflect.ValueOf(t))
+			return nil
+		}
+	}
+	if sv.Type().ConvertibleTo(dst.Type()) && sv.Kind() == dst.Kind() {
+		dst.Set(sv.Convert(dst.Type()))
+		return nil
+	}
+	return fmt.Errorf("xsql: cannot convert %T to %s", src, dst.Type())
+}
@@ -0,0 +1,217 @@
+package xsql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// convCents is money stored as a decimal string ("12.34") and held in cents.
+type convCents int64
+
+func parseCents(src any) (convCents, error) {
+	var s string
+	switch v := src.(type) {
+	case nil:
+		return 0, nil
+	case []byte:
+		s = string(v)
+	case string:
+		s = v
+	default:
+		return 0, fmt.Errorf("unsupported %T", src)
+	}
+	whole, frac, _ := strings.Cut(s, ".")
+	w, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	f, _ := strconv.ParseInt((frac + "00")[:2], 10, 64)
+	return convCents(w*100 + f), nil
+}
+
+func TestRegisterConverter_FieldsAndWhole(t *testing.T) {
+	type Row struct {
+		Price convCents  `db:"price"`
+		Tax   *convCents `db:"tax"`
+	}
+	db := newTestDB(t, func(q string, _ []driver.NamedValue) ([]string, [][]driver.Value, error) {
+		switch q {
+		case "bad":
+			return []string{"price"}, [][]driver.Value{{int64(5)}}, nil
+		case "one":
+			return []string{"price"}, [][]driver.Value{{"7.01"}}, nil
+		}
+		return []string{"price", "tax"}, [][]driver.Value{{"12.34", nil}, {[]byte("1.5"), "0.25"}}, nil
+	})
+	defer func() { _ = db.Close() }()
+	ctx := context.Background()
+
+	// Without a converter, int64-based convCents cannot take "12.34".
+	if _, err := Query[Row](ctx, db, "q"); err == nil {
+		t.Fatalf("expected scan error before registration")
+	}
+
+	RegisterConverter(parseCents)
+
+	got, err := Query[Row](ctx, db, "q")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got[0].Price != 1234 || got[0].Tax != nil || got[1].Price != 150 || got[1].Tax == nil || *got[1].Tax != 25 {
+		t.Fatalf("unexpected rows: %+v", got)
+	}
+
+	if c, err := Get[convCents](ctx, db, "one"); err != nil || c != 701 {
+		t.Fatalf("whole convCents: %v, %v", c, err)
+	}
+
+	_, err = Get[convCents](ctx, db, "bad")
+	if err == nil || !strings.Contains(err.Error(), "convert to xsql.convCents") {
+		t.Fatalf("expected converter error, got %v", err)
+	}
+}
+
+type convTag struct{ name string }
+
+func TestRegisterValuer_Args(t *testing.T) {
+	var got []driver.NamedValue
+	db := newTestDB(t, func(q string, args []driver.NamedValue) ([]string, [][]driver.Value, error) {
+		got = args
+		return []string{"n"}, [][]driver.Value{{int64(1)}}, nil
+	})
+	defer func() { _ = db.Close() }()
+	ctx := context.Background()
+
+	RegisterValuer(func(v convTag) (driver.Value, error) {
+		if v.name == "" {
+			return nil, errors.New("empty tag")
+		}
+		return "tag:" + v.name, nil
+	})
+
+	if _, err := Query[int64](ctx, db, "q", 1, convTag{"a"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0].Value != int64(1) || got[1].Value != "tag:a" {
+		t.Fatalf("driver args = %+v", got)
+	}
+
+	if _, err := Get[int64](ctx, db, "q", sql.Named("t", convTag{"b"})); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].Name != "t" || got[0].Value != "tag:b" {
+		t.Fatalf("named driver args = %+v", got)
+	}
+
+	if _, err := Query[int64](ctx, db, "q", convTag{}); err == nil || !strings.Contains(err.Error(), "arg 1: empty tag") {
+		t.Fatalf("expected valuer error, got %v", err)
+	}
+}
+
+type convLabel string
+
+func TestMapperRegisterConverter_Isolated(t *testing.T) {
+	type Row struct {
+		L convLabel `db:"l"`
+	}
+	db := newTestDB(t, func(string, []driver.NamedValue) ([]string, [][]driver.Value, error) {
+		return []string{"l"}, [][]driver.Value{{"x"}}, nil
+	})
+	defer func() { _ = db.Close() }()
+	ctx := context.Background()
+
+	upper, lower := NewMapper(), NewMapper()
+	// Cache a plan on upper before registering, to prove registration invalidates it.
+	if r, err := GetWith[Row](ctx, upper, db, "q"); err != nil || r.L != "x" {
+		t.Fatalf("before registration: %+v, %v", r, err)
+	}
+	upper.RegisterConverter(reflect.TypeOf(convLabel("")), func(src any) (any, error) {
+		return convLabel(strings.ToUpper(src.(string))), nil
+	})
+	lower.RegisterConverter(reflect.TypeOf(convLabel("")), func(src any) (any, error) {
+		return convLabel("lower:" + src.(string)), nil
+	})
+
+	if r, err := GetWith[Row](ctx, upper, db, "q"); err != nil || r.L != "X" {
+		t.Fatalf("upper: %+v, %v", r, err)
+	}
+	if l, err := GetWith[convLabel](ctx, lower, db, "q"); err != nil || l != "lower:x" {
+		t.Fatalf("lower: %q, %v", l, err)
+	}
+	if r, err := Get[Row](ctx, db, "q"); err != nil || r.L != "x" {
+		t.Fatalf("package mapper must be unaffected: %+v, %v", r, err)
+	}
+
+	bad := NewMapper()
+	bad.RegisterConverter(reflect.TypeOf(convLabel("")), func(any) (any, error) { return 42, nil })
+	if _, err := GetWith[Row](ctx, bad, db, "q"); err == nil || !strings.Contains(err.Error(), "returned int") {
+		t.Fatalf("expected type mismatch error, got %v", err)
+	}
+}
+
+func TestAssignValue(t *testing.T) {
+	type Level int8
+	var (
+		s     string
+		n     Level
+		u     uint16
+		f     float32
+		b     bool
+		bs    []byte
+		ts    time.Time
+		ptr   *int64
+		iface any
+	)
+	at := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	ok := []struct {
+		dst  any
+		src  any
+		want any
+	}{
+		{&s, int64(42), "42"},
+		{&s, []byte("hi"), "hi"},
+		{&s, at, "2024-01-02T03:04:05Z"},
+		{&n, "7", Level(7)},
+		{&n, float64(3), Level(3)},
+		{&u, int64(9), uint16(9)},
+		{&f, "1.5", float32(1.5)},
+		{&b, "t", true},
+		{&bs, "raw", []byte("raw")},
+		{&ts, "2024-01-02T03:04:05Z", at},
+		{&iface, []byte("x"), []byte("x")},
+	}
+	for _, tc := range ok {
+		dst := reflect.ValueOf(tc.dst).Elem()
+		if err := assignValue(dst, tc.src); err != nil {
+			t.Fatalf("assignValue(%T, %#v): %v", tc.dst, tc.src, err)
+		}
+		if !reflect.DeepEqual(dst.Interface(), tc.want) {
+			t.Fatalf("assignValue(%T, %#v) = %#v, want %#v", tc.dst, tc.src, dst.Interface(), tc.want)
+		}
+	}
+	if err := assignValue(reflect.ValueOf(&ptr).Elem(), int64(5)); err != nil || ptr == nil || *ptr != 5 {
+		t.Fatalf("pointer: %v, %v", ptr, err)
+	}
+	if err := assignValue(reflect.ValueOf(&ptr).Elem(), nil); err != nil || ptr != nil {
+		t.Fatalf("nil pointer: %v, %v", ptr, err)
+	}
+	for _, bad := range []struct{ dst, src any }{
+		{&n, int64(1000)},
+		{&n, "x"},
+		{&u, int64(-1)},
+		{&b, "maybe"},
+		{&s, nil},
+	} {
+		if err := assignValue(reflect.ValueOf(bad.dst).Elem(), bad.src); err == nil {
+			t.Fatalf("assignValue(%T, %#v) should fail", bad.dst, bad.src)
+		}
+	}
+}
@@ -0,0 +1,172 @@
+package xsql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+	"sync/atomic"
+)
+
+// DefaultCursorBatch is the batch size OpenCursor uses when batch <= 0.
+const DefaultCursorBatch = 1000
+
+// cursorSeq numbers server-side cursors so concurrent cursors never collide.
+var cursorSeq atomic.Uint64
+
+// Cursor iterates over a large result set in batches of T. On PostgreSQL
+// (PlaceholderDollar) it declares a server-side cursor and retrieves each batch
+// with FETCH, so the server only materializes one batch at a time and no single
+// statement runs for the whole export. On other engines it streams the rows of
+// one result set and groups them into batches.
+//
+// Either way the cursor runs inside a transaction that it owns; Close ends the
+// transaction. A Cursor is not safe for concurrent use.
+type Cursor[T any] struct {
+	ctx    context.Context
+	tx     *sql.Tx
+	name   string    // server-side cursor name; empty when streaming
+	rows   *sql.Rows // streaming mode only
+	batch  int
+	m      *Mapper
+	strict bool
+	cancel context.CancelFunc // releases the WithTimeout context, if any
+	buf    []T
+	seen   int // rows scanned so far, across batches
+	done   bool
+	err    error
+}
+
+// OpenCursor begins a transaction on db and opens a cursor over query. ph
+// selects the strategy: PlaceholderDollar uses DECLARE CURSOR / FETCH, and any
+// other style streams a single result set. batch is the maximum number of rows
+// per batch (DefaultCursorBatch if batch <= 0).
+//
+// ctx governs the whole life of the cursor, as it does for [sql.Rows], and
+// args may include [QueryOpt] values: [WithTimeout] bounds it until Close.
+//
+// Example:
+//
+//	cur, err := xsql.OpenCursor[Event](ctx, db, xsql.PlaceholderDollar, 5000,
+//	    `SELECT id, kind, payload FROM events WHERE created_at >= $1 ORDER BY id`, since)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	defer cur.Close()
+//
+//	for cur.Next() {
+//	    for _, ev := range cur.Batch() {
+//	        export(ev)
+//	    }
+//	}
+//	if err := cur.Err(); err != nil {
+//	    log.Fatal(err)
+//	}
+func OpenCursor[T any](ctx context.Context, db Beginner, ph Placeholder, batch int, query string, args ...any) (*Cursor[T], error) {
+	if batch <= 0 {
+		batch = DefaultCursorBatch
+	}
+	args, cfg, err := callArgs(args)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := cfg.context(ctx)
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	m, strict := cfg.scanMapper()
+	c := &Cursor[T]{ctx: ctx, tx: tx, batch: batch, m: m, strict: strict, cancel: cancel}
+
+	if ph == PlaceholderDollar {
+		c.name = "xsql_cursor_" + strconv.FormatUint(cursorSeq.Add(1), 10)
+		_, err = tx.ExecContext(ctx, "DECLARE "+c.name+" NO SCROLL CURSOR FOR "+query, args...)
+	} else {
+		c.rows, err = tx.QueryContext(ctx, query, args...)
+	}
+	if err != nil {
+		_ = tx.Rollback()
+		cancel()
+		return nil, err
+	}
+	return c, nil
+}
+
+// Next loads the next batch, making it available through Batch. It returns
+// false when the rows are exhausted or an error occurs; check Err afterwards.
+func (c *Cursor[T]) Next() bool {
+	if c.done || c.err != nil {
+		return false
+	}
+	c.buf = c.buf[:0]
+	if c.name != "" {
+		c.err = c.fetch()
+	} else {
+		c.err = c.read(c.rows)
+	}
+	if c.err != nil {
+		return false
+	}
+	if len(c.buf) < c.batch {
+		c.done = true
+	}
+	return len(c.buf) > 0
+}
+
+// fetch retrieves the next batch from the server-side cursor.
+func (c *Cursor[T]) fetch() (err error) {
+	rows, err := c.tx.QueryContext(c.ctx, "FETCH FORWARD "+strconv.Itoa(c.batch)+" FROM "+c.name)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := rows.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}()
+	return c.read(rows)
+}
+
+// read appends up to c.batch rows from rows to c.buf.
+func (c *Cursor[T]) read(rows *sql.Rows) error {
+	s := rowScanner[T]{m: c.m, strict: c.strict}
+	for len(c.buf) < c.batch && rows.Next() {
+		v, err := s.scan(rows)
+		if err != nil {
+			return atRow(err, c.seen)
+		}
+		c.buf = append(c.buf, v)
+		c.seen++
+	}
+	return rows.Err()
+}
+
+// Batch returns the rows loaded by the last call to Next. The slice is reused
+// by the following call to Next; copy it if it must outlive the iteration.
+func (c *Cursor[T]) Batch() []T { return c.buf }
+
+// Err returns the error, if any, encountered during iteration.
+func (c *Cursor[T]) Err() error { return c.err }
+
+// Close releases the cursor and ends its transaction: it commits after a
+// clean iteration and rolls back if iteration failed. Close is idempotent.
+func (c *Cursor[T]) Close() error {
+	if c.tx == nil {
+		return nil
+	}
+	tx := c.tx
+	c.tx, c.done = nil, true
+	defer c.cancel()
+
+	var err error
+	if c.rows != nil {
+		err = c.rows.Close()
+	} else if c.err == nil {
+		_, err = tx.ExecContext(c.ctx, "CLOSE "+c.name)
+	}
+	if err != nil || c.err != nil {
+		return errors.Join(err, tx.Rollback())
+	}
+	return tx.Commit()
+}
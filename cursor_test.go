@@ -0,0 +1,162 @@
+package xsql
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+type cursorRow struct {
+	ID int64 `db:"id"`
+}
+
+func cursorData(n int) [][]driver.Value {
+	out := make([][]driver.Value, n)
+	for i := range out {
+		out[i] = []driver.Value{int64(i + 1)}
+	}
+	return out
+}
+
+func drainCursor(t *testing.T, cur *Cursor[cursorRow]) (sizes []int, ids []int64) {
+	t.Helper()
+	for cur.Next() {
+		sizes = append(sizes, len(cur.Batch()))
+		for _, r := range cur.Batch() {
+			ids = append(ids, r.ID)
+		}
+	}
+	if err := cur.Err(); err != nil {
+		t.Fatalf("cursor error: %v", err)
+	}
+	return sizes, ids
+}
+
+func TestCursor_Postgres_DeclareFetch(t *testing.T) {
+	data := cursorData(5)
+	var stmts []string
+	db := newTestDB(t, func(q string, args []driver.NamedValue) ([]string, [][]driver.Value, error) {
+		stmts = append(stmts, q)
+		if strings.HasPrefix(q, "FETCH FORWARD 2 FROM ") {
+			n := min(2, len(data))
+			page := data[:n]
+			data = data[n:]
+			return []string{"id"}, page, nil
+		}
+		return nil, nil, nil
+	})
+	defer func() { _ = db.Close() }()
+
+	cur, err := OpenCursor[cursorRow](context.Background(), db, PlaceholderDollar, 2,
+		`SELECT id FROM t WHERE id > $1`, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sizes, ids := drainCursor(t, cur)
+	if err := cur.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(sizes) != 3 || sizes[0] != 2 || sizes[1] != 2 || sizes[2] != 1 {
+		t.Fatalf("batch sizes = %v", sizes)
+	}
+	if len(ids) != 5 || ids[0] != 1 || ids[4] != 5 {
+		t.Fatalf("ids = %v", ids)
+	}
+	if !strings.HasPrefix(stmts[0], "DECLARE xsql_cursor_") ||
+		!strings.HasSuffix(stmts[0], " NO SCROLL CURSOR FOR SELECT id FROM t WHERE id > $1") {
+		t.Fatalf("declare = %q", stmts[0])
+	}
+	// A short batch ends iteration without another FETCH.
+	if got := len(stmts); got != 5 || !strings.HasPrefix(stmts[4], "CLOSE xsql_cursor_") {
+		t.Fatalf("statements = %q", stmts)
+	}
+}
+
+func TestCursor_Streaming(t *testing.T) {
+	db := newTestDB(t, func(q string, _ []driver.NamedValue) ([]string, [][]driver.Value, error) {
+		return []string{"id"}, cursorData(4), nil
+	})
+	defer func() { _ = db.Close() }()
+
+	cur, err := OpenCursor[cursorRow](context.Background(), db, PlaceholderQuestion, 2, `SELECT id FROM t`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sizes, ids := drainCursor(t, cur)
+	if len(sizes) != 2 || sizes[0] != 2 || sizes[1] != 2 || len(ids) != 4 || ids[3] != 4 {
+		t.Fatalf("sizes=%v ids=%v", sizes, ids)
+	}
+	if err := cur.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := cur.Close(); err != nil {
+		t.Fatalf("second Close: %v", err)
+	}
+	if cur.Next() {
+		t.Fatalf("Next after Close should be false")
+	}
+}
+
+func TestCursor_Errors(t *testing.T) {
+	boom := errors.New("boom")
+	db := newTestDB(t, func(q string, _ []driver.NamedValue) ([]string, [][]driver.Value, error) {
+		if strings.HasPrefix(q, "DECLARE") {
+			return nil, nil, boom
+		}
+		return []string{"id", "x"}, cursorData(1), nil
+	})
+	defer func() { _ = db.Close() }()
+
+	if _, err := OpenCursor[cursorRow](context.Background(), db, PlaceholderDollar, 0, `bad`); !errors.Is(err, boom) {
+		t.Fatalf("want declare error, got %v", err)
+	}
+
+	cur, err := OpenCursor[int64](context.Background(), db, PlaceholderQuestion, 0, `SELECT id, x`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cur.Next() {
+		t.Fatalf("Next should fail scanning two columns into int64")
+	}
+	if cur.Err() == nil {
+		t.Fatalf("expected scan error")
+	}
+	if err := cur.Close(); err != nil {
+		t.Fatalf("Close after failed iteration: %v", err)
+	}
+}
+
+func TestCursor_QueryOpts(t *testing.T) {
+	db := newTestDB(t, func(q string, args []driver.NamedValue) ([]string, [][]driver.Value, error) {
+		if len(args) != 1 {
+			t.Fatalf("options reached the driver: %v", args)
+		}
+		return []string{"id", "extra"}, [][]driver.Value{{int64(1), nil}}, nil
+	})
+	defer func() { _ = db.Close() }()
+	ctx := context.Background()
+
+	cur, err := OpenCursor[cursorRow](ctx, db, PlaceholderQuestion, 2, `SELECT id FROM t WHERE id > ?`, 0, WithTimeout(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ids := drainCursor(t, cur); len(ids) != 1 {
+		t.Fatalf("ids = %v", ids)
+	}
+	if err := cur.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	cur, err = OpenCursor[cursorRow](ctx, db, PlaceholderQuestion, 2, `SELECT id FROM t WHERE id > ?`, 0, WithStrict())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cur.Next() || !errors.Is(cur.Err(), ErrUnmappedColumn) {
+		t.Fatalf("strict: %v", cur.Err())
+	}
+	_ = cur.Close()
+}
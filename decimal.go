@@ -0,0 +1,63 @@
+package xsql
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// RegisterDecimal registers T as an arbitrary-precision decimal type, such as
+// shopspring's decimal.Decimal or cockroachdb's apd.Decimal, process-wide.
+// It is a [RegisterConverter] and [RegisterValuer] pair built from the type's
+// own text conversions:
+//
+//   - Scanning: NUMERIC and DECIMAL columns, which drivers deliver as string
+//     or []byte, are parsed with parse; integer columns and float64 values
+//     are formatted exactly (floats in their shortest round-trip form) and
+//     parsed the same way. NULL fails for T and leaves a *T nil.
+//   - Binding: arguments of type T and *T, including struct fields and slice
+//     elements bound by the named functions, are sent as the text produced by
+//     format; a nil *T binds as NULL.
+//
+// Example:
+//
+//	xsql.RegisterDecimal(decimal.NewFromString, decimal.Decimal.String)
+//
+//	type Invoice struct {
+//	    ID    int64           `db:"id"`
+//	    Total decimal.Decimal `db:"total"`
+//	}
+func RegisterDecimal[T any](parse func(string) (T, error), format func(T) string) {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	RegisterConverter(func(src any) (T, error) {
+		var zero T
+		var text string
+		switch v := src.(type) {
+		case nil:
+			return zero, fmt.Errorf("converting NULL to %s is unsupported", t)
+		case string:
+			text = v
+		case []byte:
+			text = string(v)
+		case int64:
+			text = strconv.FormatInt(v, 10)
+		case float64:
+			text = strconv.FormatFloat(v, 'f', -1, 64)
+		default:
+			return zero, fmt.Errorf("cannot convert %T to %s", src, t)
+		}
+		d, err := parse(text)
+		if err != nil {
+			return zero, fmt.Errorf("parse %q: %w", text, err)
+		}
+		return d, nil
+	})
+	RegisterValuer(func(d T) (driver.Value, error) { return format(d), nil })
+	RegisterValuer(func(d *T) (driver.Value, error) {
+		if d == nil {
+			return nil, nil
+		}
+		return format(*d), nil
+	})
+}
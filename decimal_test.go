@@ -0,0 +1,77 @@
+package xsql
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"math/big"
+	"reflect"
+	"testing"
+)
+
+// testDecimal is a minimal arbitrary-precision type for RegisterDecimal.
+type testDecimal struct{ r *big.Rat }
+
+func parseTestDecimal(s string) (testDecimal, error) {
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return testDecimal{}, errors.New("invalid decimal")
+	}
+	return testDecimal{r}, nil
+}
+
+func (d testDecimal) String() string { return d.r.FloatString(2) }
+
+func init() { RegisterDecimal(parseTestDecimal, testDecimal.String) }
+
+func TestRegisterDecimal(t *testing.T) {
+	type Invoice struct {
+		Total    testDecimal  `db:"total"`
+		Discount *testDecimal `db:"discount"`
+	}
+	var args []driver.NamedValue
+	db := newTestDB(t, func(q string, a []driver.NamedValue) ([]string, [][]driver.Value, error) {
+		args = a
+		switch q {
+		case "null":
+			return []string{"total", "discount"}, [][]driver.Value{{nil, nil}}, nil
+		case "bad":
+			return []string{"total", "discount"}, [][]driver.Value{{"1.2.3", nil}}, nil
+		}
+		return []string{"total", "discount"}, [][]driver.Value{
+			{[]byte("12345678901234567890.125"), "0.50"},
+			{int64(7), float64(0.1)},
+		}, nil
+	})
+	defer func() { _ = db.Close() }()
+	ctx := context.Background()
+
+	got, err := Query[Invoice](ctx, db, "q")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got[0].Total.r.FloatString(3) != "12345678901234567890.125" || got[0].Discount.String() != "0.50" {
+		t.Fatalf("row 0: %v %v", got[0].Total, got[0].Discount)
+	}
+	if got[1].Total.String() != "7.00" || got[1].Discount.r.FloatString(1) != "0.1" {
+		t.Fatalf("row 1: %v %v", got[1].Total, got[1].Discount)
+	}
+	if _, err := Query[Invoice](ctx, db, "null"); err == nil {
+		t.Fatalf("NULL into a decimal value should fail")
+	}
+	if _, err := Query[Invoice](ctx, db, "bad"); err == nil {
+		t.Fatalf("expected parse error")
+	}
+
+	in := Invoice{Total: got[0].Total}
+	if _, err := NamedExec(ctx, db, PlaceholderDollar, `UPDATE t SET total = :total, discount = :discount`, in); err != nil {
+		t.Fatal(err)
+	}
+	var bound []any
+	for _, a := range args {
+		bound = append(bound, a.Value)
+	}
+	if !reflect.DeepEqual(bound, []any{"12345678901234567890.13", nil}) {
+		t.Fatalf("bound args = %#v", bound)
+	}
+}
@@ -0,0 +1,86 @@
+package xsql
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// withDefault wraps the step for a field tagged `default=text` so a NULL
+// column yields the default instead of the zero value (or a scan error). The
+// column is scanned into an interface value; non-NULL values go through st's
+// own conversion, and NULL is replaced by text, converted the same way as if
+// the column had returned it. The default is checked when the plan is built.
+func withDefault(st step, ft reflect.Type, text string) (step, error) {
+	apply := stepApplier(st)
+	if err := apply(reflect.New(ft).Elem(), text); err != nil {
+		return step{}, fmt.Errorf("xsql: invalid default %q for %s: %w", text, ft, err)
+	}
+	return nullable(st, func(dst reflect.Value) error { return apply(dst, text) }), nil
+}
+
+// withZeroNull wraps the step for a field tagged `zeronull` so a NULL column
+// leaves the field's zero value instead of failing the scan.
+func withZeroNull(st step) step {
+	return nullable(st, func(dst reflect.Value) error {
+		dst.SetZero()
+		return nil
+	})
+}
+
+// acceptsNull reports whether a destination of type t takes NULL by itself:
+// pointers, interfaces, slices and maps become nil, and sql.Scanner
+// implementations and registered converters receive the nil value. Mappers
+// created with WithNullToZero leave these alone.
+func (m *Mapper) acceptsNull(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Slice, reflect.Map:
+		return true
+	}
+	return implementsScanner(t) || m.hasConverter(t)
+}
+
+// nilPointerOnNull wraps st, a pickIndirect step whose temporary cannot hold
+// NULL, so that a NULL column leaves a pointer destination of type t nil.
+func nilPointerOnNull(st step, t reflect.Type) step {
+	if t.Kind() != reflect.Ptr {
+		return st
+	}
+	return withZeroNull(st)
+}
+
+// nullable returns a step that scans into an interface value, hands NULL to
+// onNull, and converts anything else with st.
+func nullable(st step, onNull func(dst reflect.Value) error) step {
+	apply := stepApplier(st)
+	return step{
+		kind:   stepIndirect,
+		fpath:  st.fpath,
+		convTo: reflect.TypeOf((*any)(nil)).Elem(),
+		post: func(dst, src reflect.Value) error {
+			v := src.Interface()
+			if v == nil {
+				return onNull(dst)
+			}
+			return apply(dst, v)
+		},
+	}
+}
+
+// stepApplier returns a func that stores the driver value v in dst the way
+// st would have after scanning it.
+func stepApplier(st step) func(dst reflect.Value, v any) error {
+	return func(dst reflect.Value, v any) error {
+		if st.kind != stepIndirect {
+			return assignValue(dst, v)
+		}
+		tmp := reflect.New(st.convTo).Elem()
+		if st.convTo.Kind() == reflect.Interface {
+			if v != nil {
+				tmp.Set(reflect.ValueOf(v))
+			}
+		} else if err := assignValue(tmp, v); err != nil {
+			return err
+		}
+		return st.post(dst, tmp)
+	}
+}
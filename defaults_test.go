@@ -0,0 +1,86 @@
+package xsql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDefaultTag(t *testing.T) {
+	type Status string
+	type Row struct {
+		Status  Status         `db:"status,default=pending"`
+		Retries int32          `db:"retries,default=3"`
+		Active  bool           `db:"active,default=yes"`
+		Timeout time.Duration  `db:"timeout,default=30s"`
+		Label   *string        `db:"label,default=none"`
+		Note    sql.NullString `db:"note,default=n/a"`
+	}
+	db := newTestDB(t, func(q string, _ []driver.NamedValue) ([]string, [][]driver.Value, error) {
+		return []string{"status", "retries", "active", "timeout", "label", "note"}, [][]driver.Value{
+			{nil, nil, nil, nil, nil, nil},
+			{"done", int64(0), int64(0), int64(time.Second), "x", "y"},
+		}, nil
+	})
+	defer func() { _ = db.Close() }()
+	ctx := context.Background()
+
+	got, err := Query[Row](ctx, db, "q")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := got[0]
+	if r.Status != "pending" || r.Retries != 3 || !r.Active || r.Timeout != 30*time.Second ||
+		r.Label == nil || *r.Label != "none" || r.Note.String != "n/a" || !r.Note.Valid {
+		t.Fatalf("defaults not applied: %+v", r)
+	}
+	r = got[1]
+	if r.Status != "done" || r.Retries != 0 || r.Active || r.Timeout != time.Second || *r.Label != "x" || r.Note.String != "y" {
+		t.Fatalf("values overridden: %+v", r)
+	}
+	if got[0].Label == got[1].Label {
+		t.Fatalf("pointer defaults must not be shared")
+	}
+
+	type Bad struct {
+		N int `db:"retries,default=many"`
+	}
+	if _, err := Query[Bad](ctx, db, "q"); err == nil || !strings.Contains(err.Error(), `invalid default "many"`) {
+		t.Fatalf("expected invalid default error, got %v", err)
+	}
+}
+
+func TestZeroNullTag(t *testing.T) {
+	type Row struct {
+		Count int64   `db:"count,zeronull"`
+		Ratio float32 `db:"ratio,zeronull"`
+		Name  string  `db:",zeronull"`
+		Level int8    `db:"level"`
+	}
+	db := newTestDB(t, func(q string, _ []driver.NamedValue) ([]string, [][]driver.Value, error) {
+		if q == "strict" {
+			return []string{"level"}, [][]driver.Value{{nil}}, nil
+		}
+		return []string{"count", "ratio", "name"}, [][]driver.Value{
+			{nil, nil, nil},
+			{int64(4), 0.5, "x"},
+		}, nil
+	})
+	defer func() { _ = db.Close() }()
+	ctx := context.Background()
+
+	got, err := Query[Row](ctx, db, "q")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got[0] != (Row{}) || got[1] != (Row{Count: 4, Ratio: 0.5, Name: "x"}) {
+		t.Fatalf("unexpected rows: %+v", got)
+	}
+	// Fields without the option still reject NULL.
+	if _, err := Get[Row](ctx, db, "strict"); err == nil {
+		t.Fatalf("NULL into int8 without zeronull should fail")
+	}
+}
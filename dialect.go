@@ -0,0 +1,199 @@
+package xsql
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Paging is the row-limiting syntax of a [Dialect].
+type Paging int
+
+const (
+	PagingLimitOffset Paging = iota // LIMIT n OFFSET m (PostgreSQL, MySQL, SQLite)
+	PagingOffsetFetch               // OFFSET m ROWS FETCH NEXT n ROWS ONLY (SQL Server 2012+, Oracle 12c+)
+	PagingTop                       // SELECT TOP n (older SQL Server; first page only)
+)
+
+// Dialect bundles the SQL syntax differences between database families that
+// xsql needs to generate or rewrite statements: the placeholder style, how
+// identifiers are quoted, how result rows are limited, and whether INSERT,
+// UPDATE, and DELETE support a RETURNING clause.
+//
+// The predefined dialects cover the common engines; a Dialect is a plain value,
+// so a variant can be derived by copying one and changing a field.
+//
+// Example:
+//
+//	d := xsql.DialectFor("pgx") // => xsql.Postgres
+//	q, args, err := d.Rebind(`SELECT * FROM users WHERE id = :id`, map[string]any{"id": 7})
+//	// q => SELECT * FROM users WHERE id = $1
+type Dialect struct {
+	Name        string
+	Placeholder Placeholder
+	QuoteOpen   byte // opening identifier quote: '"', '`', or '['
+	QuoteClose  byte // closing identifier quote: '"', '`', or ']'
+	Paging      Paging
+	Returning   bool // INSERT/UPDATE/DELETE … RETURNING is supported
+}
+
+// Predefined dialects.
+var (
+	Postgres  = Dialect{Name: "postgres", Placeholder: PlaceholderDollar, QuoteOpen: '"', QuoteClose: '"', Paging: PagingLimitOffset, Returning: true}
+	MySQL     = Dialect{Name: "mysql", Placeholder: PlaceholderQuestion, QuoteOpen: '`', QuoteClose: '`', Paging: PagingLimitOffset}
+	SQLServer = Dialect{Name: "sqlserver", Placeholder: PlaceholderAtP, QuoteOpen: '[', QuoteClose: ']', Paging: PagingOffsetFetch}
+	Oracle    = Dialect{Name: "oracle", Placeholder: PlaceholderColonNum, QuoteOpen: '"', QuoteClose: '"', Paging: PagingOffsetFetch}
+	SQLite    = Dialect{Name: "sqlite", Placeholder: PlaceholderQuestion, QuoteOpen: '"', QuoteClose: '"', Paging: PagingLimitOffset, Returning: true}
+)
+
+// DialectFor returns the dialect for a database/sql driver name, like
+// [PlaceholderFor]. Unknown drivers get SQLite, whose ? placeholders,
+// double-quoted identifiers, and LIMIT … OFFSET are the most widely accepted.
+//
+// Example:
+//
+//	d := xsql.DialectFor("sqlserver") // => xsql.SQLServer
+func DialectFor(driverName string) Dialect {
+	switch strings.ToLower(driverName) {
+	case "mysql", "mariadb":
+		return MySQL
+	case "sqlite", "sqlite3", "libsql":
+		return SQLite
+	}
+	return dialectForPlaceholder(PlaceholderFor(driverName))
+}
+
+// dialectForPlaceholder returns the dialect a placeholder style implies.
+func dialectForPlaceholder(ph Placeholder) Dialect {
+	var d Dialect
+	switch ph {
+	case PlaceholderDollar:
+		d = Postgres
+	case PlaceholderAtP, PlaceholderAtName:
+		d = SQLServer
+	case PlaceholderColonNum, PlaceholderColonName:
+		d = Oracle
+	default:
+		d = SQLite
+	}
+	d.Placeholder = ph
+	return d
+}
+
+// Rebind is [Rebind] with the dialect's placeholder style.
+func (d Dialect) Rebind(query string, params ...any) (string, []any, error) {
+	return Rebind(query, d.Placeholder, params...)
+}
+
+// Paginate appends the dialect's row-limiting clause to query, selecting limit
+// rows after skipping offset. PagingOffsetFetch needs the query to have an
+// ORDER BY; PagingTop inserts TOP after the leading SELECT [DISTINCT] and
+// fails for a nonzero offset.
+//
+// Example:
+//
+//	q, err := xsql.SQLServer.Paginate(`SELECT id FROM users ORDER BY id`, 50, 100)
+//	// q => SELECT id FROM users ORDER BY id OFFSET 100 ROWS FETCH NEXT 50 ROWS ONLY
+func (d Dialect) Paginate(query string, limit, offset int) (string, error) {
+	if limit < 0 || offset < 0 {
+		return "", fmt.Errorf("xsql: invalid limit %d or offset %d", limit, offset)
+	}
+	query = trimStatement(query)
+	switch d.Paging {
+	case PagingOffsetFetch:
+		return query + " OFFSET " + strconv.Itoa(offset) + " ROWS FETCH NEXT " + strconv.Itoa(limit) + " ROWS ONLY", nil
+	case PagingTop:
+		if offset > 0 {
+			return "", fmt.Errorf("xsql: %s: TOP paging cannot skip rows", d.Name)
+		}
+		i := skipKeyword(query, 0, "SELECT")
+		if i == 0 {
+			return "", fmt.Errorf("xsql: %s: TOP paging needs a SELECT statement", d.Name)
+		}
+		if j := skipKeyword(query, skipSpaces(query, i), "DISTINCT"); j > 0 {
+			i = j
+		}
+		return query[:i] + " TOP " + strconv.Itoa(limit) + query[i:], nil
+	default:
+		return query + " LIMIT " + strconv.Itoa(limit) + " OFFSET " + strconv.Itoa(offset), nil
+	}
+}
+
+// skipKeyword returns the offset just past kw if s[i:] starts with it as a
+// whole word (case-insensitively), or 0.
+func skipKeyword(s string, i int, kw string) int {
+	end := i + len(kw)
+	if end > len(s) || !strings.EqualFold(s[i:end], kw) {
+		return 0
+	}
+	if end < len(s) && isTagChar(rune(s[end])) {
+		return 0
+	}
+	return end
+}
+
+// ErrInvalidIdent is returned by [QuoteIdent] for a name that cannot be quoted
+// safely.
+var ErrInvalidIdent = errors.New("xsql: invalid identifier")
+
+// QuoteIdent quotes a table or column name for d, so that names chosen at run
+// time (a sort column, a per-tenant schema) can be spliced into SQL. A dotted
+// name is treated as qualified, and each part is quoted separately:
+// schema.table becomes "schema"."table".
+//
+// It returns [ErrInvalidIdent] for an empty name or part, invalid UTF-8,
+// control characters, or the dialect's quote characters, rather than escaping
+// them: legitimate identifiers do not contain them. Quoting makes the name
+// case-sensitive on PostgreSQL and Oracle, so it must match the stored case.
+// Prefer checking names against an allowlist too; QuoteIdent only guarantees
+// the result is a single identifier.
+//
+// Example:
+//
+//	col, err := xsql.QuoteIdent(xsql.Postgres, req.SortBy)
+//	if err != nil {
+//	    return err
+//	}
+//	q := `SELECT id, email FROM users ORDER BY ` + col
+func QuoteIdent(d Dialect, name string) (string, error) {
+	open, closing := d.QuoteOpen, d.QuoteClose
+	if open == 0 || closing == 0 {
+		open, closing = '"', '"'
+	}
+	var b strings.Builder
+	b.Grow(len(name) + 4)
+	for i, part := range strings.Split(name, ".") {
+		if err := checkIdent(part, open, closing); err != nil {
+			return "", fmt.Errorf("%w %q: %v", ErrInvalidIdent, name, err)
+		}
+		if i > 0 {
+			b.WriteByte('.')
+		}
+		b.WriteByte(open)
+		b.WriteString(part)
+		b.WriteByte(closing)
+	}
+	return b.String(), nil
+}
+
+// checkIdent reports why part cannot be quoted with open and closing, if so.
+func checkIdent(part string, open, closing byte) error {
+	if part == "" {
+		return errors.New("empty name")
+	}
+	if !utf8.ValidString(part) {
+		return errors.New("invalid UTF-8")
+	}
+	for _, r := range part {
+		switch {
+		case unicode.IsControl(r):
+			return fmt.Errorf("control character %U", r)
+		case r == rune(open) || r == rune(closing):
+			return fmt.Errorf("quote character %q", r)
+		}
+	}
+	return nil
+}
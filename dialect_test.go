@@ -0,0 +1,118 @@
+package xsql
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"testing"
+)
+
+func TestDialectFor(t *testing.T) {
+	for name, want := range map[string]Dialect{
+		"pgx":       Postgres,
+		"mysql":     MySQL,
+		"sqlserver": SQLServer,
+		"godror":    Oracle,
+		"sqlite3":   SQLite,
+		"unknown":   SQLite,
+	} {
+		if got := DialectFor(name); got != want {
+			t.Fatalf("%s: got %+v, want %+v", name, got, want)
+		}
+	}
+	if d := dialectForPlaceholder(PlaceholderAtName); d.Paging != PagingOffsetFetch || d.Placeholder != PlaceholderAtName {
+		t.Fatalf("AtName: %+v", d)
+	}
+
+	q, args, err := Postgres.Rebind(`SELECT * FROM t WHERE id IN (:ids)`, map[string]any{"ids": []int{1, 2}})
+	if err != nil || q != `SELECT * FROM t WHERE id IN ($1,$2)` || len(args) != 2 {
+		t.Fatalf("rebind: %q %v %v", q, args, err)
+	}
+}
+
+func TestDialect_Paginate(t *testing.T) {
+	top := SQLServer
+	top.Paging = PagingTop
+	for _, tc := range []struct {
+		d             Dialect
+		query         string
+		limit, offset int
+		want          string
+	}{
+		{Postgres, "SELECT id FROM t;", 10, 20, "SELECT id FROM t LIMIT 10 OFFSET 20"},
+		{Oracle, "SELECT id FROM t ORDER BY id", 10, 0, "SELECT id FROM t ORDER BY id OFFSET 0 ROWS FETCH NEXT 10 ROWS ONLY"},
+		{top, "  select distinct id FROM t", 5, 0, "select distinct TOP 5 id FROM t"},
+		{top, "SELECT\tid FROM t", 5, 0, "SELECT TOP 5\tid FROM t"},
+	} {
+		got, err := tc.d.Paginate(tc.query, tc.limit, tc.offset)
+		if err != nil || got != tc.want {
+			t.Fatalf("%s: got %q, %v; want %q", tc.d.Name, got, err, tc.want)
+		}
+	}
+	for q, offset := range map[string]int{"SELECT id FROM t": 1, "SELECTED": 0, "WITH x AS (SELECT 1) SELECT * FROM x": 0} {
+		if _, err := top.Paginate(q, 5, offset); err == nil {
+			t.Fatalf("%q: expected TOP error", q)
+		}
+	}
+	if _, err := Postgres.Paginate("SELECT 1", -1, 0); err == nil {
+		t.Fatal("expected error for negative limit")
+	}
+}
+
+func TestQueryPage_WithDialect(t *testing.T) {
+	var got string
+	db := newTestDB(t, func(q string, _ []driver.NamedValue) ([]string, [][]driver.Value, error) {
+		got = q
+		return []string{"n"}, nil, nil
+	})
+	defer func() { _ = db.Close() }()
+
+	top := SQLServer
+	top.Paging = PagingTop
+	if _, err := QueryPage[int64](context.Background(), db, "SELECT n FROM t ORDER BY n", 1, 10, WithDialect(top)); err != nil {
+		t.Fatal(err)
+	}
+	if got != "SELECT TOP 11 n FROM t ORDER BY n" {
+		t.Fatalf("query = %q", got)
+	}
+	if _, err := QueryPage[int64](context.Background(), db, "SELECT n FROM t", 2, 10, WithDialect(top)); err == nil {
+		t.Fatal("expected error for TOP with offset")
+	}
+}
+
+func TestQuoteIdent(t *testing.T) {
+	for _, tc := range []struct {
+		d    Dialect
+		name string
+		want string
+	}{
+		{Postgres, "users", `"users"`},
+		{Postgres, "tenant_7.Orders", `"tenant_7"."Orders"`},
+		{MySQL, "order", "`order`"},
+		{SQLServer, "dbo.user data", "[dbo].[user data]"},
+		{SQLServer, `a"b`, `[a"b]`},
+		{Dialect{}, "ünïcode", `"ünïcode"`},
+	} {
+		got, err := QuoteIdent(tc.d, tc.name)
+		if err != nil || got != tc.want {
+			t.Fatalf("%s %q: got %q, %v; want %q", tc.d.Name, tc.name, got, err, tc.want)
+		}
+	}
+	for _, tc := range []struct {
+		d    Dialect
+		name string
+	}{
+		{Postgres, ""},
+		{Postgres, "a..b"},
+		{Postgres, `x"; DROP TABLE users; --`},
+		{MySQL, "a`b"},
+		{SQLServer, "a]b"},
+		{Postgres, "a\x00b"},
+		{Postgres, "a\nb"},
+		{Postgres, "\xff"},
+	} {
+		if _, err := QuoteIdent(tc.d, tc.name); !errors.Is(err, ErrInvalidIdent) {
+			t.Fatalf("%s %q: expected ErrInvalidIdent, got %v", tc.d.Name, tc.name, err)
+		}
+	}
+}
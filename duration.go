@@ -0,0 +1,162 @@
+package xsql
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// durationUnit returns the unit an integer column is counted in for a
+// time.Duration field: `db:"ttl,seconds"` and `db:"ttl,millis"` select
+// seconds and milliseconds; the default is nanoseconds, as Duration itself.
+func durationUnit(tag string) time.Duration {
+	switch {
+	case hasTagOption(tag, "seconds"):
+		return time.Second
+	case hasTagOption(tag, "millis"):
+		return time.Millisecond
+	}
+	return time.Nanosecond
+}
+
+// pickDuration handles time.Duration and *time.Duration destinations. The
+// column is scanned into an interface value: integers and floats count unit,
+// and text holds either a plain number (also in unit), a Go duration
+// ("1h30m"), or a PostgreSQL interval ("1 day 02:03:04.5"). NULL leaves the
+// zero duration or a nil pointer.
+func pickDuration(dstType reflect.Type, unit time.Duration) (reflect.Type, func(dst, src reflect.Value) error, bool) {
+	if dstType != durationType && dstType != reflect.PointerTo(durationType) {
+		return nil, nil, false
+	}
+	isPtr := dstType.Kind() == reflect.Ptr
+	return reflect.TypeOf((*any)(nil)).Elem(), func(dst, src reflect.Value) error {
+		var d time.Duration
+		switch v := src.Interface().(type) {
+		case nil:
+			dst.SetZero()
+			return nil
+		case int64:
+			d = time.Duration(v) * unit
+		case float64:
+			d = time.Duration(math.Round(v * float64(unit)))
+		case string:
+			pd, err := parseDuration(v, unit)
+			if err != nil {
+				return err
+			}
+			d = pd
+		case []byte:
+			pd, err := parseDuration(string(v), unit)
+			if err != nil {
+				return err
+			}
+			d = pd
+		default:
+			return fmt.Errorf("xsql: cannot scan %T into time.Duration", v)
+		}
+		if isPtr {
+			dst.Set(reflect.ValueOf(&d))
+		} else {
+			dst.Set(reflect.ValueOf(d))
+		}
+		return nil
+	}, true
+}
+
+func parseDuration(s string, unit time.Duration) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return time.Duration(n) * unit, nil
+	}
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		return time.Duration(math.Round(f * float64(unit))), nil
+	}
+	if d, err := time.ParseDuration(s); err == nil {
+		return d, nil
+	}
+	if d, ok := parseInterval(s); ok {
+		return d, nil
+	}
+	return 0, fmt.Errorf("xsql: parse duration %q", s)
+}
+
+// intervalUnits maps PostgreSQL interval field names to their length. Months
+// and years use the same approximations as EXTRACT(EPOCH FROM interval).
+var intervalUnits = map[string]time.Duration{
+	"year": 8766 * time.Hour, "years": 8766 * time.Hour,
+	"mon": 720 * time.Hour, "mons": 720 * time.Hour,
+	"day": 24 * time.Hour, "days": 24 * time.Hour,
+	"hour": time.Hour, "hours": time.Hour,
+	"min": time.Minute, "mins": time.Minute,
+	"sec": time.Second, "secs": time.Second,
+}
+
+// parseInterval parses the default ("postgres") interval output style:
+// "[N year[s]] [N mon[s]] [N day[s]] [[+-]HH:MM:SS[.frac]]", e.g.
+// "1 year 2 mons", "-3 days +04:05:06.5", or "00:00:01.25".
+func parseInterval(s string) (time.Duration, bool) {
+	fields := strings.Fields(s)
+	if len(fields) == 0 {
+		return 0, false
+	}
+	var total time.Duration
+	for i := 0; i < len(fields); i++ {
+		f := fields[i]
+		if strings.Contains(f, ":") {
+			d, ok := parseClock(f)
+			if !ok {
+				return 0, false
+			}
+			total += d
+			continue
+		}
+		if i+1 >= len(fields) {
+			return 0, false
+		}
+		n, err := strconv.ParseInt(f, 10, 64)
+		u, ok := intervalUnits[fields[i+1]]
+		if err != nil || !ok {
+			return 0, false
+		}
+		total += time.Duration(n) * u
+		i++
+	}
+	return total, true
+}
+
+// parseClock parses "[+-]H:MM[:SS[.frac]]".
+func parseClock(s string) (time.Duration, bool) {
+	neg := false
+	switch {
+	case strings.HasPrefix(s, "-"):
+		neg, s = true, s[1:]
+	case strings.HasPrefix(s, "+"):
+		s = s[1:]
+	}
+	parts := strings.Split(s, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return 0, false
+	}
+	h, err1 := strconv.ParseUint(parts[0], 10, 32)
+	m, err2 := strconv.ParseUint(parts[1], 10, 8)
+	if err1 != nil || err2 != nil || m >= 60 {
+		return 0, false
+	}
+	d := time.Duration(h)*time.Hour + time.Duration(m)*time.Minute
+	if len(parts) == 3 {
+		sec, err := strconv.ParseFloat(parts[2], 64)
+		if err != nil || sec < 0 || sec >= 60 || strings.ContainsAny(parts[2], "eE+-") {
+			return 0, false
+		}
+		d += time.Duration(math.Round(sec * float64(time.Second)))
+	}
+	if neg {
+		d = -d
+	}
+	return d, true
+}
@@ -0,0 +1,77 @@
+package xsql
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+	"time"
+)
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		in   string
+		unit time.Duration
+		want time.Duration
+	}{
+		{"1500", time.Nanosecond, 1500},
+		{"90", time.Second, 90 * time.Second},
+		{"2.5", time.Millisecond, 2500 * time.Microsecond},
+		{"1h30m", time.Nanosecond, 90 * time.Minute},
+		{"00:00:01.25", time.Nanosecond, 1250 * time.Millisecond},
+		{"-01:30:00", time.Nanosecond, -90 * time.Minute},
+		{"3 days", time.Nanosecond, 72 * time.Hour},
+		{"1 day 02:03:04", time.Nanosecond, 26*time.Hour + 3*time.Minute + 4*time.Second},
+		{"-1 days +02:00:00", time.Nanosecond, -22 * time.Hour},
+		{"1 mon", time.Nanosecond, 30 * 24 * time.Hour},
+		{"1 year", time.Nanosecond, 8766 * time.Hour},
+	}
+	for _, tc := range tests {
+		got, err := parseDuration(tc.in, tc.unit)
+		if err != nil || got != tc.want {
+			t.Fatalf("parseDuration(%q) = %v, %v; want %v", tc.in, got, err, tc.want)
+		}
+	}
+	for _, bad := range []string{"", "soon", "3 fortnights", "1:2:3:4", "00:61:00", "5 days x"} {
+		if _, err := parseDuration(bad, time.Nanosecond); err == nil {
+			t.Fatalf("parseDuration(%q) should fail", bad)
+		}
+	}
+}
+
+func TestScan_Duration(t *testing.T) {
+	type Job struct {
+		Timeout time.Duration  `db:"timeout"`
+		TTL     time.Duration  `db:"ttl,seconds"`
+		Delay   *time.Duration `db:"delay,millis"`
+		Runtime time.Duration  `db:"runtime"`
+	}
+	db := newTestDB(t, func(q string, _ []driver.NamedValue) ([]string, [][]driver.Value, error) {
+		if q == "one" {
+			return []string{"d"}, [][]driver.Value{{int64(time.Second)}}, nil
+		}
+		return []string{"timeout", "ttl", "delay", "runtime"}, [][]driver.Value{
+			{int64(5e9), int64(60), int64(250), "01:02:03"},
+			{nil, []byte("3600"), nil, []byte("2 days")},
+		}, nil
+	})
+	defer func() { _ = db.Close() }()
+	ctx := context.Background()
+
+	got, err := Query[Job](ctx, db, "q")
+	if err != nil {
+		t.Fatal(err)
+	}
+	j := got[0]
+	if j.Timeout != 5*time.Second || j.TTL != time.Minute || j.Delay == nil || *j.Delay != 250*time.Millisecond ||
+		j.Runtime != time.Hour+2*time.Minute+3*time.Second {
+		t.Fatalf("row 0: %+v", j)
+	}
+	j = got[1]
+	if j.Timeout != 0 || j.TTL != time.Hour || j.Delay != nil || j.Runtime != 48*time.Hour {
+		t.Fatalf("row 1: %+v", j)
+	}
+
+	if d, err := Get[time.Duration](ctx, db, "one"); err != nil || d != time.Second {
+		t.Fatalf("Get[time.Duration] = %v, %v", d, err)
+	}
+}
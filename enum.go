@@ -0,0 +1,88 @@
+package xsql
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+type integer interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 | ~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64
+}
+
+// RegisterEnum maps the database values of an enum column to the integer
+// constants of T, process-wide. Scanning a field of type T or *T (or T itself
+// with Query and Get) looks the column text up in values and fails on anything
+// else, naming the unknown value and the accepted ones; an integer column must
+// hold one of the constants. Arguments of type T and *T, including struct
+// fields bound by the named functions, are sent as their database value.
+//
+// Names are matched exactly. It panics if two names map to the same constant,
+// since the value to bind would be ambiguous.
+//
+// Example:
+//
+//	type Status int
+//
+//	const (
+//	    Active Status = iota + 1
+//	    Suspended
+//	)
+//
+//	xsql.RegisterEnum(map[string]Status{"active": Active, "suspended": Suspended})
+func RegisterEnum[T integer](values map[string]T) {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	byName := make(map[string]T, len(values))
+	names := make(map[T]string, len(values))
+	for name, v := range values {
+		if prev, dup := names[v]; dup {
+			panic(fmt.Sprintf("xsql: RegisterEnum: %q and %q both map to %s(%d)", prev, name, t, v))
+		}
+		byName[name], names[v] = v, name
+	}
+	valid := make([]string, 0, len(values))
+	for name := range values {
+		valid = append(valid, strconv.Quote(name))
+	}
+	slices.Sort(valid)
+	want := strings.Join(valid, ", ")
+
+	RegisterConverter(func(src any) (T, error) {
+		var text string
+		switch v := src.(type) {
+		case nil:
+			return 0, fmt.Errorf("converting NULL to %s is unsupported", t)
+		case string:
+			text = v
+		case []byte:
+			text = string(v)
+		case int64:
+			if _, ok := names[T(v)]; ok && int64(T(v)) == v {
+				return T(v), nil
+			}
+			return 0, fmt.Errorf("unknown %s value %d", t, v)
+		default:
+			return 0, fmt.Errorf("cannot convert %T to %s", src, t)
+		}
+		if e, ok := byName[text]; ok {
+			return e, nil
+		}
+		return 0, fmt.Errorf("unknown %s value %q (want one of %s)", t, text, want)
+	})
+	value := func(e T) (driver.Value, error) {
+		if name, ok := names[e]; ok {
+			return name, nil
+		}
+		return nil, fmt.Errorf("%s(%d) has no registered database value", t, e)
+	}
+	RegisterValuer(value)
+	RegisterValuer(func(e *T) (driver.Value, error) {
+		if e == nil {
+			return nil, nil
+		}
+		return value(*e)
+	})
+}
@@ -0,0 +1,73 @@
+package xsql
+
+import (
+	"context"
+	"database/sql/driver"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type enumTestStatus int
+
+const (
+	enumTestActive enumTestStatus = iota + 1
+	enumTestSuspended
+)
+
+func init() {
+	RegisterEnum(map[string]enumTestStatus{"active": enumTestActive, "suspended": enumTestSuspended})
+}
+
+func TestRegisterEnum(t *testing.T) {
+	type Account struct {
+		Status enumTestStatus  `db:"status"`
+		Prev   *enumTestStatus `db:"prev"`
+	}
+	var args []driver.NamedValue
+	db := newTestDB(t, func(q string, a []driver.NamedValue) ([]string, [][]driver.Value, error) {
+		args = a
+		if q == "bad" {
+			return []string{"status", "prev"}, [][]driver.Value{{"archived", nil}}, nil
+		}
+		return []string{"status", "prev"}, [][]driver.Value{
+			{"active", nil},
+			{[]byte("suspended"), int64(1)},
+		}, nil
+	})
+	defer func() { _ = db.Close() }()
+	ctx := context.Background()
+
+	got, err := Query[Account](ctx, db, "q")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got[0].Status != enumTestActive || got[0].Prev != nil ||
+		got[1].Status != enumTestSuspended || got[1].Prev == nil || *got[1].Prev != enumTestActive {
+		t.Fatalf("unexpected rows: %+v", got)
+	}
+	_, err = Query[Account](ctx, db, "bad")
+	if err == nil || !strings.Contains(err.Error(), `unknown xsql.enumTestStatus value "archived" (want one of "active", "suspended")`) {
+		t.Fatalf("expected unknown value error, got %v", err)
+	}
+
+	prev := enumTestSuspended
+	if _, err := NamedExec(ctx, db, PlaceholderDollar, `UPDATE t SET status = :status, prev = :prev`,
+		Account{Status: enumTestActive, Prev: &prev}); err != nil {
+		t.Fatal(err)
+	}
+	if got := []any{args[0].Value, args[1].Value}; !reflect.DeepEqual(got, []any{"active", "suspended"}) {
+		t.Fatalf("bound args = %#v", got)
+	}
+	if _, err := Exec(ctx, db, `UPDATE t SET status = $1`, enumTestStatus(9)); err == nil ||
+		!strings.Contains(err.Error(), "has no registered database value") {
+		t.Fatalf("expected unregistered value error, got %v", err)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("expected panic for duplicate constants")
+		}
+	}()
+	RegisterEnum(map[string]enumTestStatus{"a": 1, "b": 1})
+}
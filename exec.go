@@ -3,8 +3,14 @@ package xsql
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"fmt"
 )
 
+// ErrNotOneRow is returned by ExecExpectOne when a statement affects a number
+// of rows other than one. The returned error wraps it with the actual count.
+var ErrNotOneRow = errors.New("xsql: expected exactly one affected row")
+
 // Exec executes a statement that does not return rows (INSERT, UPDATE, DELETE, DDL).
 //
 // It forwards to the underlying [Execer]. On success it returns the driver's
@@ -12,7 +18,9 @@ import (
 // the database/driver.
 //
 // Exec does not attempt SQL rendering or placeholder rewriting; write your SQL
-// exactly as your driver expects.
+// exactly as your driver expects. [sql.Named] args are forwarded as is for
+// drivers with native named parameters; mixing them with positional args
+// returns [ErrMixedArgs]. Of the [QueryOpt] values, only [WithTimeout] applies.
 //
 // Example:
 //
@@ -29,5 +37,107 @@ import (
 //   - Use a transaction (BeginTx) around multiple Exec/Query calls when you need atomicity.
 //   - Not all drivers support LastInsertId; prefer RETURNING with Query/Get where available.
 func Exec(ctx context.Context, e Execer, query string, args ...any) (sql.Result, error) {
+	args, cfg, err := callArgs(args)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := cfg.context(ctx)
+	defer cancel()
 	return e.ExecContext(ctx, query, args...)
 }
+
+// ExecReturning executes a data-modifying statement with a RETURNING (PostgreSQL,
+// SQLite, MariaDB) or OUTPUT (SQL Server) clause and scans the first returned
+// row into T using the same mapping rules as [Get].
+//
+// Unlike Get, ExecReturning reads the result set to the end before returning,
+// so statements that affect several rows run to completion even though only
+// the first returned row is scanned. It returns [sql.ErrNoRows] if the
+// statement returned no rows (e.g., an UPDATE that matched nothing).
+//
+// Example:
+//
+//	id, err := xsql.ExecReturning[int64](ctx, db,
+//	    `INSERT INTO users (email) VALUES ($1) RETURNING id`, "a@example.com")
+func ExecReturning[T any](ctx context.Context, q Querier, query string, args ...any) (out T, err error) {
+	args, cfg, err := callArgs(args)
+	if err != nil {
+		return out, err
+	}
+	ctx, cancel := cfg.context(ctx)
+	defer cancel()
+
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return out, err
+	}
+	defer func() {
+		if cerr := rows.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}()
+
+	if !rows.Next() {
+		if ne := rows.Err(); ne != nil {
+			return out, ne
+		}
+		return out, sql.ErrNoRows
+	}
+	m, strict := cfg.scanMapper()
+	v, scanErr := scanRow[T](m, strict, rows)
+	if scanErr != nil {
+		return out, atRow(scanErr, 0)
+	}
+	for rows.Next() {
+	}
+	if ne := rows.Err(); ne != nil {
+		return out, ne
+	}
+	return v, nil
+}
+
+// ExecReturningAll is the slice variant of [ExecReturning]: it executes a
+// data-modifying statement with a RETURNING or OUTPUT clause and scans every
+// returned row into a slice of T. A statement that returns no rows yields an
+// empty slice and a nil error.
+//
+// Example:
+//
+//	type Archived struct {
+//	    ID    int64  `db:"id"`
+//	    Email string `db:"email"`
+//	}
+//	rows, err := xsql.ExecReturningAll[Archived](ctx, db,
+//	    `UPDATE users SET archived = true WHERE last_login < $1 RETURNING id, email`, cutoff)
+func ExecReturningAll[T any](ctx context.Context, q Querier, query string, args ...any) ([]T, error) {
+	return Query[T](ctx, q, query, args...)
+}
+
+// ExecExpectOne executes a statement that must affect exactly one row, such as
+// an UPDATE or DELETE by primary key. If RowsAffected reports any other count,
+// it returns an error wrapping [ErrNotOneRow]; errors from the driver, including
+// drivers that do not support RowsAffected, are returned as is.
+//
+// ExecExpectOne does not roll anything back: run it inside a transaction when a
+// wrong row count must undo the change.
+//
+// Example:
+//
+//	err := xsql.ExecExpectOne(ctx, db, `UPDATE users SET email = $1 WHERE id = $2`, email, id)
+//	if errors.Is(err, xsql.ErrNotOneRow) {
+//	    // no such user (or, worse, more than one)
+//	}
+func ExecExpectOne(ctx context.Context, e Execer, query string, args ...any) error {
+	res, err := Exec(ctx, e, query, args...)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n != 1 {
+		return fmt.Errorf("%w: got %d", ErrNotOneRow, n)
+	}
+	return nil
+}
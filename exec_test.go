@@ -27,7 +27,20 @@ type execConn struct{ h execHandler }
 
 func (c *execConn) Prepare(string) (driver.Stmt, error) { return nil, driver.ErrSkip }
 func (c *execConn) Close() error                        { return nil }
-func (c *execConn) Begin() (driver.Tx, error)           { return nil, driver.ErrSkip }
+func (c *execConn) Begin() (driver.Tx, error)           { return execTx{c}, nil }
+
+// execTx reports COMMIT and ROLLBACK to the handler so tests can observe them.
+type execTx struct{ c *execConn }
+
+func (tx execTx) Commit() error {
+	_, err := tx.c.h("COMMIT", nil)
+	return err
+}
+
+func (tx execTx) Rollback() error {
+	_, err := tx.c.h("ROLLBACK", nil)
+	return err
+}
 
 // Support ExecContext so *sql.DB.ExecContext hits this path.
 func (c *execConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
@@ -121,3 +134,108 @@ func TestExec_Error(t *testing.T) {
 		t.Fatalf("want %v, got %v", sentinel, err)
 	}
 }
+
+func TestExecReturning_ScansFirstRowAndDrains(t *testing.T) {
+	type Row struct {
+		ID    int64  `db:"id"`
+		Email string `db:"email"`
+	}
+	db := newTestDB(t, func(query string, args []driver.NamedValue) ([]string, [][]driver.Value, error) {
+		if len(args) != 1 || args[0].Value != "a@x" {
+			t.Fatalf("unexpected args: %#v", args)
+		}
+		return []string{"id", "email"}, [][]driver.Value{
+			{int64(5), []byte("a@x")},
+			{int64(6), []byte("b@x")},
+		}, nil
+	})
+	defer func() { _ = db.Close() }()
+
+	got, err := ExecReturning[Row](context.Background(), db,
+		`UPDATE users SET email = ? RETURNING id, email`, "a@x")
+	if err != nil {
+		t.Fatalf("ExecReturning: %v", err)
+	}
+	if got.ID != 5 || got.Email != "a@x" {
+		t.Fatalf("unexpected row: %+v", got)
+	}
+}
+
+func TestExecReturning_NoRows(t *testing.T) {
+	db := newTestDB(t, func(query string, args []driver.NamedValue) ([]string, [][]driver.Value, error) {
+		return []string{"id"}, nil, nil
+	})
+	defer func() { _ = db.Close() }()
+
+	_, err := ExecReturning[int64](context.Background(), db, `DELETE FROM users WHERE id = ? RETURNING id`, 1)
+	if !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("want sql.ErrNoRows, got %v", err)
+	}
+}
+
+func TestExecReturning_QueryError(t *testing.T) {
+	sentinel := errors.New("boom")
+	db := newTestDB(t, func(query string, args []driver.NamedValue) ([]string, [][]driver.Value, error) {
+		return nil, nil, sentinel
+	})
+	defer func() { _ = db.Close() }()
+
+	if _, err := ExecReturning[int64](context.Background(), db, `INSERT ... RETURNING id`); !errors.Is(err, sentinel) {
+		t.Fatalf("want %v, got %v", sentinel, err)
+	}
+}
+
+func TestExecReturningAll(t *testing.T) {
+	db := newTestDB(t, func(query string, args []driver.NamedValue) ([]string, [][]driver.Value, error) {
+		return []string{"id"}, [][]driver.Value{{int64(1)}, {int64(2)}}, nil
+	})
+	defer func() { _ = db.Close() }()
+
+	got, err := ExecReturningAll[int64](context.Background(), db, `DELETE FROM users RETURNING id`)
+	if err != nil {
+		t.Fatalf("ExecReturningAll: %v", err)
+	}
+	if len(got) != 2 || got[0] != 1 || got[1] != 2 {
+		t.Fatalf("unexpected: %v", got)
+	}
+}
+
+func TestExecExpectOne(t *testing.T) {
+	var affected int64
+	raErr := error(nil)
+	db := newExecDB(t, func(query string, args []driver.NamedValue) (driver.Result, error) {
+		return testResult{rows: affected, raErr: raErr}, nil
+	})
+	defer func() { _ = db.Close() }()
+
+	ctx := context.Background()
+	affected = 1
+	if err := ExecExpectOne(ctx, db, `UPDATE users SET email = ? WHERE id = ?`, "x", 1); err != nil {
+		t.Fatalf("want nil for 1 row, got %v", err)
+	}
+
+	for _, n := range []int64{0, 2} {
+		affected = n
+		err := ExecExpectOne(ctx, db, `UPDATE users SET email = ? WHERE id = ?`, "x", 1)
+		if !errors.Is(err, ErrNotOneRow) {
+			t.Fatalf("rows=%d: want ErrNotOneRow, got %v", n, err)
+		}
+	}
+
+	raErr = errors.New("not supported")
+	if err := ExecExpectOne(ctx, db, `UPDATE users SET email = ?`, "x"); !errors.Is(err, raErr) {
+		t.Fatalf("want RowsAffected error, got %v", err)
+	}
+}
+
+func TestExecExpectOne_ExecError(t *testing.T) {
+	sentinel := errors.New("boom")
+	db := newExecDB(t, func(query string, args []driver.NamedValue) (driver.Result, error) {
+		return nil, sentinel
+	})
+	defer func() { _ = db.Close() }()
+
+	if err := ExecExpectOne(context.Background(), db, `DELETE FROM users WHERE id = ?`, 1); !errors.Is(err, sentinel) {
+		t.Fatalf("want %v, got %v", sentinel, err)
+	}
+}
@@ -0,0 +1,195 @@
+package xsql
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+)
+
+// RegisterFields registers fn, which returns the address of the field of *v
+// at struct index i (nil for fields it does not cover), so that mappers scan
+// into T without reflection, process-wide. names lists the field names fn
+// covers by struct index, "" for the others; RegisterFields panics if they no
+// longer match T, which means the struct changed after fn was written.
+//
+// Every mapper keeps its own rules: it still decides which column fills which
+// field and how values are converted, and only takes the addresses of
+// top-level fields through fn. Rows whose columns all scan straight into such
+// fields skip reflection entirely; fields that need a conversion (a
+// registered converter, an enum, a TextUnmarshaler, …) go through the usual
+// reflective steps. Accessors registered on a mapper with
+// [Mapper.RegisterFields] take precedence. Plans built before the call are
+// not reused.
+//
+// The xsqlgen command (github.com/go-mizu/xsql/cmd/xsqlgen) generates fn and
+// names from the struct declaration, and an init func that calls
+// RegisterFields.
+//
+// Example:
+//
+//	//go:generate go run github.com/go-mizu/xsql/cmd/xsqlgen -type User
+//
+//	// In the generated user_fields.go:
+//	func init() {
+//	    xsql.RegisterFields(xsqlUserField, xsqlUserFields...)
+//	}
+func RegisterFields[T any](fn func(v *T, i int) any, names ...string) {
+	globalConverters.setFields(reflect.TypeOf((*T)(nil)).Elem(), func(v any, i int) any { return fn(v.(*T), i) }, names)
+}
+
+// RegisterFields is the per-mapper form of the package-level
+// [RegisterFields]: fn returns the address of the field of v, a pointer to a
+// struct of type rt, at struct index i, or nil. It applies to this mapper
+// only, taking precedence over global registrations; a nil fn makes the
+// mapper scan rt with reflection even if accessors are registered globally.
+//
+// It panics if rt is not a struct type or names does not match its fields.
+//
+// Example:
+//
+//	// Generated with: xsqlgen -type User -init=false
+//	m := xsql.NewMapper(xsql.WithNameMapper(xsql.SnakeCase))
+//	m.RegisterFields(reflect.TypeOf(User{}), func(v any, i int) any {
+//	    return xsqlUserField(v.(*User), i)
+//	}, xsqlUserFields...)
+func (m *Mapper) RegisterFields(rt reflect.Type, fn func(v any, i int) any, names ...string) {
+	m.convs.setFields(rt, fn, names)
+}
+
+func (r *converterRegistry) setFields(rt reflect.Type, fn func(v any, i int) any, names []string) {
+	if rt.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("xsql: RegisterFields: %s is not a struct type", rt))
+	}
+	if fn != nil {
+		if len(names) != rt.NumField() {
+			panic(fmt.Sprintf("xsql: RegisterFields: %s has %d fields, accessors cover %d; regenerate them", rt, rt.NumField(), len(names)))
+		}
+		for i, name := range names {
+			if name != "" && rt.Field(i).Name != name {
+				panic(fmt.Sprintf("xsql: RegisterFields: field %d of %s is %s, not %s; regenerate the accessors", i, rt, rt.Field(i).Name, name))
+			}
+		}
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.fields == nil {
+		r.fields = make(map[reflect.Type]func(any, int) any)
+	}
+	r.fields[rt] = fn
+	r.gen.Add(1)
+}
+
+func (r *converterRegistry) fieldAccessor(rt reflect.Type) (func(any, int) any, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	fn, ok := r.fields[rt]
+	return fn, ok
+}
+
+// fieldFunc returns the accessor for struct type rt, preferring m's own
+// registry, or nil.
+func (m *Mapper) fieldFunc(rt reflect.Type) func(v any, i int) any {
+	if fn, ok := m.convs.fieldAccessor(rt); ok {
+		return fn
+	}
+	fn, _ := globalConverters.fieldAccessor(rt)
+	return fn
+}
+
+// canScanFast reports whether every row of p can be scanned by scanFast:
+// each column is dropped, or fills a top-level field that p's accessor covers
+// either directly or through a builtin conversion, and no hooks run.
+func (p *plan) canScanFast() bool {
+	if p.field == nil || len(p.hooks) > 0 || p.before != nil {
+		return false
+	}
+	probe := reflect.New(p.rt).Interface()
+	for _, st := range p.steps {
+		switch {
+		case st.kind == stepDrop:
+		case st.kind == stepDirect || (st.kind == stepIndirect && st.builtin):
+			if len(st.fpath) != 1 || p.field(probe, st.fpath[0]) == nil {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// scanFast scans the *current row* into a new T through p's field accessor,
+// without reflection. p must satisfy canScanFast and must not be a pointer
+// plan.
+func scanFast[T any](p *plan, rows *sql.Rows) (T, error) {
+	var v T
+	target := any(&v)
+	f := p.frames.Get().(*scanFrame)
+	defer f.release(p)
+	for i, st := range p.steps {
+		if st.kind == stepDirect {
+			f.dests[i] = p.field(target, st.fpath[0])
+		}
+	}
+	if err := rows.Scan(f.dests...); err != nil {
+		var zero T
+		return zero, scanError(rows, -1, err, colSpan{p: p})
+	}
+	for i, st := range p.steps {
+		if st.kind == stepIndirect {
+			assignBuiltin(p.field(target, st.fpath[0]), f.dests[i])
+		}
+	}
+	return v, nil
+}
+
+// isBuiltinScalar reports whether t is the predeclared string type or a
+// predeclared number type, whose pickIndirect conversion assignBuiltin
+// reproduces.
+func isBuiltinScalar(t reflect.Type) bool {
+	if t.PkgPath() != "" || t.Name() == "" {
+		return false
+	}
+	switch t.Kind() {
+	case reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// assignBuiltin stores src, the scan temporary pickIndirect chose for a field
+// of builtin type, in dst, a pointer to the field, converting it like
+// pickIndirect's post function does.
+func assignBuiltin(dst, src any) {
+	switch d := dst.(type) {
+	case *string:
+		*d = string(*src.(*[]byte))
+	case *int:
+		*d = int(*src.(*int64))
+	case *int8:
+		*d = int8(*src.(*int64))
+	case *int16:
+		*d = int16(*src.(*int64))
+	case *int32:
+		*d = int32(*src.(*int64))
+	case *int64:
+		*d = *src.(*int64)
+	case *uint:
+		*d = uint(*src.(*uint64))
+	case *uint8:
+		*d = uint8(*src.(*uint64))
+	case *uint16:
+		*d = uint16(*src.(*uint64))
+	case *uint32:
+		*d = uint32(*src.(*uint64))
+	case *uint64:
+		*d = *src.(*uint64)
+	case *float32:
+		*d = float32(*src.(*float64))
+	case *float64:
+		*d = *src.(*float64)
+	}
+}
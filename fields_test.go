@@ -0,0 +1,133 @@
+package xsql
+
+import (
+	"context"
+	"database/sql/driver"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type fieldsRow struct {
+	ID     int64
+	Name   string `db:"full_name"`
+	Tags   []string
+	hidden int
+	Score  *int64
+}
+
+// fieldsRowField is what xsqlgen generates for fieldsRow.
+func fieldsRowField(v *fieldsRow, i int) any {
+	switch i {
+	case 0:
+		return &v.ID
+	case 1:
+		return &v.Name
+	case 2:
+		return &v.Tags
+	case 4:
+		return &v.Score
+	}
+	return nil
+}
+
+func TestRegisterFields(t *testing.T) {
+	RegisterFields(fieldsRowField, "ID", "Name", "Tags", "", "Score")
+	db := newTestDB(t, func(q string, _ []driver.NamedValue) ([]string, [][]driver.Value, error) {
+		return []string{"id", "full_name", "score", "extra"}, [][]driver.Value{
+			{int64(1), "ann", int64(5), "x"},
+			{int64(2), "bob", nil, "y"},
+		}, nil
+	})
+	defer func() { _ = db.Close() }()
+	ctx := context.Background()
+
+	m := NewMapper()
+	got, err := QueryWith[fieldsRow](ctx, m, db, "q")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0].ID != 1 || got[0].Name != "ann" || *got[0].Score != 5 || got[1].Score != nil || got[1].Name != "bob" {
+		t.Fatalf("unexpected rows: %+v", got)
+	}
+	rt := reflect.TypeOf(fieldsRow{})
+	if pl, _ := m.planFor(rt, []string{"id", "full_name", "extra"}); !pl.fast {
+		t.Fatal("plan with only direct top-level fields does not scan through the accessor")
+	}
+	if pl, _ := m.planFor(rt, []string{"id", "score"}); pl.fast || pl.field == nil {
+		t.Fatal("plan with a converted field skips its conversion step")
+	}
+
+	// The mapper's rules still apply: strict mode, and conversions, which
+	// leave the row to the reflective steps.
+	m.Strict = true
+	if _, err := QueryWith[fieldsRow](ctx, m, db, "q"); err == nil || !strings.Contains(err.Error(), "extra") {
+		t.Fatalf("strict mapper accepted an unmapped column: %v", err)
+	}
+	ptrs, err := QueryWith[*fieldsRow](ctx, NewMapper(), db, "q")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ptrs) != 2 || ptrs[1].ID != 2 || ptrs[1].Name != "bob" || *ptrs[0].Score != 5 {
+		t.Fatalf("unexpected rows: %+v", ptrs)
+	}
+
+	defer func() {
+		if r := recover(); r == nil || !strings.Contains(r.(string), "regenerate") {
+			t.Fatalf("stale accessors registered without a panic: %v", r)
+		}
+	}()
+	RegisterFields(fieldsRowField, "ID", "Title", "Tags", "", "Score")
+}
+
+func TestMapper_RegisterFields(t *testing.T) {
+	type item struct {
+		SKU   string
+		Price float64
+	}
+	rt := reflect.TypeOf(item{})
+	calls := 0
+	m := NewMapper()
+	m.RegisterFields(rt, func(v any, i int) any {
+		calls++
+		it := v.(*item)
+		switch i {
+		case 0:
+			return &it.SKU
+		case 1:
+			return &it.Price
+		}
+		return nil
+	}, "SKU", "Price")
+	db := newTestDB(t, func(q string, _ []driver.NamedValue) ([]string, [][]driver.Value, error) {
+		return []string{"sku", "price"}, [][]driver.Value{{"a-1", 9.5}}, nil
+	})
+	defer func() { _ = db.Close() }()
+	ctx := context.Background()
+
+	got, err := QueryWith[item](ctx, m, db, "q")
+	if err != nil || len(got) != 1 || got[0] != (item{"a-1", 9.5}) || calls == 0 {
+		t.Fatalf("mapper accessors: %+v %v (%d calls)", got, err, calls)
+	}
+	if pl, _ := NewMapper().planFor(rt, []string{"sku", "price"}); pl.field != nil {
+		t.Fatal("accessors registered on one mapper leaked into another")
+	}
+
+	// A nil accessor opts the mapper out of global registrations.
+	RegisterFields(fieldsRowField, "ID", "Name", "Tags", "", "Score")
+	out := NewMapper()
+	out.RegisterFields(reflect.TypeOf(fieldsRow{}), nil)
+	if pl, _ := out.planFor(reflect.TypeOf(fieldsRow{}), []string{"id"}); pl.field != nil {
+		t.Fatal("opted-out mapper still uses the global accessors")
+	}
+	if pl, _ := NewMapper().planFor(reflect.TypeOf(fieldsRow{}), []string{"id"}); pl.field == nil {
+		t.Fatal("global accessors not used")
+	}
+
+	defer func() {
+		if r := recover(); r == nil || !strings.Contains(r.(string), "not a struct") {
+			t.Fatalf("expected panic for a non-struct type: %v", r)
+		}
+	}()
+	m.RegisterFields(reflect.TypeOf(0), func(any, int) any { return nil })
+}
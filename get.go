@@ -14,7 +14,9 @@ import (
 //
 // T may be a struct (supports `db` tags and ,inline), a primitive, or any type
 // implementing [sql.Scanner]. Column mapping prefers `db:"name"` tags;
-// otherwise it matches case-insensitive field names.
+// otherwise it matches case-insensitive field names. With a pointer to a
+// struct, Get scans into a fresh allocation and returns it, or nil together
+// with [sql.ErrNoRows].
 //
 // Extra columns are ignored and missing columns set zero values unless strict
 // mode is enabled internally. Safe for concurrent use, Get internally uses a
@@ -40,6 +42,13 @@ import (
 //	}
 //	// use u
 func Get[T any](ctx context.Context, q Querier, query string, args ...any) (out T, err error) {
+	args, cfg, err := callArgs(args)
+	if err != nil {
+		return out, err
+	}
+	ctx, cancel := cfg.context(ctx)
+	defer cancel()
+
 	rows, err := q.QueryContext(ctx, query, args...)
 	if err != nil {
 		return out, err
@@ -58,10 +67,20 @@ func Get[T any](ctx context.Context, q Querier, query string, args ...any) (out
 		return out, sql.ErrNoRows
 	}
 
-	m := getMapper() // lazy, thread-safe
-	v, scanErr := scanWithMapper[T](m, rows)
+	m, strict := cfg.scanMapper() // lazy, thread-safe
+	v, scanErr := scanRow[T](m, strict, rows)
 	if scanErr != nil {
-		return out, scanErr
+		return out, atRow(scanErr, 0)
 	}
 	return v, nil
 }
+
+// GetWith is [Get] using mapper m instead of the package-level mapper. It is
+// equivalent to passing [WithMapper](m) among the args.
+//
+// Example:
+//
+//	u, err := xsql.GetWith[User](ctx, strict, db, `SELECT id, email FROM users WHERE id = $1`, 42)
+func GetWith[T any](ctx context.Context, m *Mapper, q Querier, query string, args ...any) (T, error) {
+	return Get[T](ctx, q, query, append(args[:len(args):len(args)], WithMapper(m))...)
+}
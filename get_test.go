@@ -62,6 +62,61 @@ func TestGet_SuccessStruct(t *testing.T) {
 	}
 }
 
+func TestGet_PointerToStruct(t *testing.T) {
+	type Row struct {
+		ID   int64  `db:"id"`
+		Name string `db:"name"`
+	}
+	db := newTestDB(t, func(q string, _ []driver.NamedValue) ([]string, [][]driver.Value, error) {
+		if q == "none" {
+			return []string{"id", "name"}, nil, nil
+		}
+		return []string{"id", "name", "extra"}, [][]driver.Value{{int64(7), "alice", int64(1)}}, nil
+	})
+	defer func() { _ = db.Close() }()
+	ctx := context.Background()
+
+	got, err := Get[*Row](ctx, db, "ok")
+	if err != nil {
+		t.Fatalf("Get error: %v", err)
+	}
+	if got == nil || got.ID != 7 || got.Name != "alice" {
+		t.Fatalf("unexpected row: %+v", got)
+	}
+
+	got, err = Get[*Row](ctx, db, "none")
+	if !errors.Is(err, sql.ErrNoRows) || got != nil {
+		t.Fatalf("want nil, sql.ErrNoRows; got %v, %v", got, err)
+	}
+
+	if _, err := Get[*Row](ctx, db, "ok", WithStrict()); !errors.Is(err, ErrUnmappedColumn) {
+		t.Fatalf("want ErrUnmappedColumn, got %v", err)
+	}
+
+	// *Row reuses the steps of Row's plan.
+	m := NewMapper()
+	pv, err := m.planFor(reflect.TypeOf(Row{}), []string{"id", "name"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pp, err := m.planFor(reflect.TypeOf(&Row{}), []string{"id", "name"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !pp.ptr || pv.ptr || pp.rt != pv.rt || &pp.steps[0] != &pv.steps[0] {
+		t.Fatalf("pointer plan should wrap the value plan: %+v vs %+v", pp, pv)
+	}
+
+	stmt, err := Prepare[*Row](ctx, db, "ok")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = stmt.Close() }()
+	if r, err := stmt.Get(ctx); err != nil || r == nil || r.Name != "alice" {
+		t.Fatalf("Stmt.Get = %+v, %v", r, err)
+	}
+}
+
 func TestGet_QueryError(t *testing.T) {
 	wantErr := errors.New("boom")
 	db := newTestDB(t, func(q string, _ []driver.NamedValue) ([]string, [][]driver.Value, error) {
@@ -130,3 +185,33 @@ func TestGet_UsesLazyMapperSingleton(t *testing.T) {
 		t.Fatal("lazy mapper singleton not stable across Get")
 	}
 }
+
+func TestGetWith_QueryWith_UseGivenMapper(t *testing.T) {
+	type Row struct {
+		ID int64 `db:"id"`
+	}
+	db := newTestDB(t, func(q string, args []driver.NamedValue) ([]string, [][]driver.Value, error) {
+		if len(args) != 1 || args[0].Value != int64(5) {
+			t.Errorf("unexpected driver args: %+v", args)
+		}
+		return []string{"id", "other"}, [][]driver.Value{{int64(1), int64(2)}}, nil
+	})
+	defer func() { _ = db.Close() }()
+	ctx := context.Background()
+
+	m := NewMapper()
+	if r, err := GetWith[Row](ctx, m, db, "q", 5); err != nil || r.ID != 1 {
+		t.Fatalf("GetWith: %+v, %v", r, err)
+	}
+	if _, ok := m.structIndexCache.Load(reflect.TypeOf(Row{})); !ok {
+		t.Fatalf("GetWith should populate the given mapper's caches")
+	}
+
+	m.Strict = true
+	if _, err := QueryWith[Row](ctx, m, db, "q", 5); !errors.Is(err, ErrUnmappedColumn) {
+		t.Fatalf("QueryWith strict mapper: want ErrUnmappedColumn, got %v", err)
+	}
+	if _, err := Query[Row](ctx, db, "q", 5); err != nil {
+		t.Fatalf("package mapper should stay lenient: %v", err)
+	}
+}
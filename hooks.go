@@ -0,0 +1,159 @@
+package xsql
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+)
+
+// AfterScanner is implemented by destinations that want to run code once a row
+// has been scanned into them: computing derived fields, validating, or
+// normalizing values.
+//
+// AfterScan is called for T itself and for every nested struct field that
+// received columns (inline, dotted, or decoded by a converter), innermost
+// first, after all columns of the row have been assigned. An error aborts the
+// query and is returned wrapped. Implement it on the pointer receiver.
+//
+// Example:
+//
+//	type User struct {
+//	    First    string `db:"first_name"`
+//	    Last     string `db:"last_name"`
+//	    FullName string `db:"-"`
+//	}
+//
+//	func (u *User) AfterScan() error {
+//	    u.FullName = u.First + " " + u.Last
+//	    return nil
+//	}
+type AfterScanner interface {
+	AfterScan() error
+}
+
+// BeforeScanner is implemented by destinations that want to see the result
+// columns before a row is scanned into them, for example to adapt to partial
+// selects or to record which columns were loaded.
+//
+// BeforeScan is called on T (not on nested fields) for every row, on the fresh
+// value, before any column is assigned. cols holds the columns mapped to T,
+// normalized as for matching (unquoted and, unless the mapper is case
+// sensitive, lower-cased); it is shared between rows and must not be modified.
+// An error aborts the query and is returned wrapped.
+//
+// Example:
+//
+//	type Profile struct {
+//	    ID     int64  `db:"id"`
+//	    Bio    string `db:"bio"`
+//	    loaded map[string]bool
+//	}
+//
+//	func (p *Profile) BeforeScan(cols []string) error {
+//	    p.loaded = make(map[string]bool, len(cols))
+//	    for _, c := range cols {
+//	        p.loaded[c] = true
+//	    }
+//	    return nil
+//	}
+type BeforeScanner interface {
+	BeforeScan(cols []string) error
+}
+
+var (
+	afterScannerType  = reflect.TypeOf((*AfterScanner)(nil)).Elem()
+	beforeScannerType = reflect.TypeOf((*BeforeScanner)(nil)).Elem()
+)
+
+// beforeScanCols returns the columns passed to BeforeScan for a plan over
+// cols, or nil if rt does not implement BeforeScanner. Masked ("") columns
+// belong to another destination and are left out.
+func beforeScanCols(rt reflect.Type, cols []string) []string {
+	if rt.Kind() == reflect.Interface || !reflect.PointerTo(rt).Implements(beforeScannerType) {
+		return nil
+	}
+	out := make([]string, 0, len(cols))
+	for _, c := range cols {
+		if c != "" {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// beforeScan calls BeforeScan on root, the fresh row value.
+func (p *plan) beforeScan(root reflect.Value) error {
+	if err := root.Addr().Interface().(BeforeScanner).BeforeScan(p.before); err != nil {
+		return fmt.Errorf("xsql: BeforeScan %s: %w", root.Type(), err)
+	}
+	return nil
+}
+
+func implementsAfterScan(t reflect.Type) bool {
+	t = derefPtr(t)
+	return t.Kind() != reflect.Interface && reflect.PointerTo(t).Implements(afterScannerType)
+}
+
+// afterScanHooks returns the field paths of p whose values implement
+// AfterScanner, deepest first; an empty path stands for the row value itself.
+func afterScanHooks(rt reflect.Type, steps []step, isStruct bool) [][]int {
+	var hooks [][]int
+	if isStruct {
+		seen := make(map[string]bool)
+		for _, st := range steps {
+			if st.kind == stepDrop {
+				continue
+			}
+			for n := 1; n <= len(st.fpath); n++ {
+				path := st.fpath[:n]
+				key := fmt.Sprint(path)
+				if seen[key] || !implementsAfterScan(fieldTypeByPath(rt, path)) {
+					continue
+				}
+				seen[key] = true
+				hooks = append(hooks, path)
+			}
+		}
+		slices.SortStableFunc(hooks, func(a, b []int) int { return len(b) - len(a) })
+	}
+	if implementsAfterScan(rt) {
+		hooks = append(hooks, []int{})
+	}
+	return hooks
+}
+
+// afterScan runs the hooks of p on root, the scanned row value. Values behind
+// nil pointers were not populated and are skipped.
+func (p *plan) afterScan(root reflect.Value) error {
+	for _, path := range p.hooks {
+		v, ok := fieldByPathNoAlloc(root, path)
+		if !ok {
+			continue
+		}
+		if err := v.Addr().Interface().(AfterScanner).AfterScan(); err != nil {
+			return fmt.Errorf("xsql: AfterScan %s: %w", v.Type(), err)
+		}
+	}
+	return nil
+}
+
+// fieldByPathNoAlloc walks fpath from v, reporting false at the first nil
+// pointer. The result has pointers removed.
+func fieldByPathNoAlloc(v reflect.Value, fpath []int) (reflect.Value, bool) {
+	for _, i := range fpath {
+		for v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return v, false
+			}
+			v = v.Elem()
+		}
+		v = v.Field(i)
+	}
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return v, false
+		}
+		v = v.Elem()
+	}
+	return v, true
+}
@@ -0,0 +1,113 @@
+package xsql
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"testing"
+)
+
+type hookAddr struct {
+	City string `db:"city"`
+	Norm string `db:"-"`
+}
+
+func (a *hookAddr) AfterScan() error {
+	a.Norm = strings.ToUpper(a.City)
+	return nil
+}
+
+type hookUser struct {
+	First    string    `db:"first"`
+	Last     string    `db:"last"`
+	Home     hookAddr  `db:"home_,inline"`
+	Work     *hookAddr `db:"work"`
+	FullName string    `db:"-"`
+	order    []string
+}
+
+var errHookRejected = errors.New("rejected")
+
+func (u *hookUser) AfterScan() error {
+	if u.First == "" {
+		return errHookRejected
+	}
+	u.FullName = u.First + " " + u.Last
+	// Nested hooks have run already.
+	u.order = append(u.order, u.Home.Norm)
+	return nil
+}
+
+func TestAfterScan(t *testing.T) {
+	db := newTestDB(t, func(q string, _ []driver.NamedValue) ([]string, [][]driver.Value, error) {
+		if q == "bad" {
+			return []string{"first"}, [][]driver.Value{{""}}, nil
+		}
+		return []string{"first", "last", "home_city", "work.city"}, [][]driver.Value{
+			{"ada", "lovelace", "london", "paris"},
+			{"alan", "turing", "wilmslow", nil},
+		}, nil
+	})
+	defer func() { _ = db.Close() }()
+	ctx := context.Background()
+
+	got, err := Query[*hookUser](ctx, db, "q")
+	if err != nil {
+		t.Fatal(err)
+	}
+	u := got[0]
+	if u.FullName != "ada lovelace" || u.Home.Norm != "LONDON" || u.Work == nil || u.Work.Norm != "PARIS" ||
+		len(u.order) != 1 || u.order[0] != "LONDON" {
+		t.Fatalf("row 0: %+v", u)
+	}
+	if got[1].Work == nil || got[1].Work.Norm != "" {
+		t.Fatalf("row 1 work: %+v", got[1].Work)
+	}
+
+	if _, err := Get[hookUser](ctx, db, "bad"); !errors.Is(err, errHookRejected) {
+		t.Fatalf("want hook error, got %v", err)
+	}
+}
+
+type hookProfile struct {
+	ID     int64  `db:"id"`
+	Bio    string `db:"bio"`
+	loaded []string
+}
+
+func (p *hookProfile) BeforeScan(cols []string) error {
+	if p.ID != 0 {
+		return errors.New("BeforeScan should see a fresh value")
+	}
+	if len(cols) == 0 {
+		return errHookRejected
+	}
+	p.loaded = append([]string(nil), cols...)
+	return nil
+}
+
+func TestBeforeScan(t *testing.T) {
+	db := newTestDB(t, func(q string, _ []driver.NamedValue) ([]string, [][]driver.Value, error) {
+		return []string{`"ID"`, "n"}, [][]driver.Value{{int64(1), int64(5)}, {int64(2), int64(6)}}, nil
+	})
+	defer func() { _ = db.Close() }()
+	ctx := context.Background()
+
+	got, err := Query[hookProfile](ctx, db, "q")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got[1].ID != 2 || strings.Join(got[1].loaded, ",") != "id,n" {
+		t.Fatalf("unexpected profile: %+v", got[1])
+	}
+
+	// With a join, each side sees only its own columns.
+	pairs, err := Query2Split[*hookProfile, int64](ctx, db, 1, "q")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(pairs[0].First.loaded, ",") != "id" || pairs[0].Second != 5 {
+		t.Fatalf("unexpected pair: %+v", pairs[0])
+	}
+}
@@ -0,0 +1,132 @@
+package xsql
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// scanTypes holds the concrete types the `as=` tag option can name, keyed by
+// their Go type string ("*geo.Point").
+var scanTypes sync.Map // string -> reflect.Type
+
+// RegisterImplementation makes fields of interface type I, and I itself as
+// the type parameter of Query or Get, scan as concrete type C, process-wide.
+// C is scanned with the usual rules (sql.Scanner, unmarshalers, converters, …)
+// and stored in the interface; a NULL scanned into a pointer C leaves the
+// interface nil. C also becomes available to the `as=` tag option.
+//
+// It panics if I is not an interface type or C does not implement I.
+//
+// Example:
+//
+//	xsql.RegisterImplementation[geo.Geometry, *geo.Point]()
+//
+//	type Place struct {
+//	    Name  string       `db:"name"`
+//	    Shape geo.Geometry `db:"shape"` // scanned through (*geo.Point).Scan
+//	}
+func RegisterImplementation[I, C any]() {
+	globalConverters.setImpl(reflect.TypeOf((*I)(nil)).Elem(), reflect.TypeOf((*C)(nil)).Elem())
+}
+
+// RegisterType makes T available to the `as=` tag option under its Go type
+// string, as printed by reflect (for example "*geo.Point" or "wkb.Polygon").
+// The option selects the concrete type for an interface field, overriding any
+// implementation registered for the interface:
+//
+//	xsql.RegisterType[*geo.Point]()
+//
+//	type Place struct {
+//	    Shape geo.Geometry `db:"shape,as=*geo.Point"`
+//	}
+func RegisterType[T any]() {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	scanTypes.Store(t.String(), t)
+}
+
+// RegisterImplementation is the per-mapper form of the package-level
+// [RegisterImplementation]: fields of interface type iface scan as concrete
+// for this mapper only, taking precedence over global registrations.
+//
+// It panics if iface is not an interface type or concrete does not implement it.
+//
+// Example:
+//
+//	m := xsql.NewMapper()
+//	m.RegisterImplementation(reflect.TypeOf((*geo.Geometry)(nil)).Elem(), reflect.TypeOf(&geo.Point{}))
+func (m *Mapper) RegisterImplementation(iface, concrete reflect.Type) {
+	m.convs.setImpl(iface, concrete)
+}
+
+func (r *converterRegistry) setImpl(iface, concrete reflect.Type) {
+	if iface.Kind() != reflect.Interface {
+		panic(fmt.Sprintf("xsql: RegisterImplementation: %s is not an interface type", iface))
+	}
+	if concrete.Kind() == reflect.Interface || !concrete.Implements(iface) {
+		panic(fmt.Sprintf("xsql: RegisterImplementation: %s does not implement %s", concrete, iface))
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.impls == nil {
+		r.impls = make(map[reflect.Type]reflect.Type)
+	}
+	r.impls[iface] = concrete
+	r.gen.Add(1)
+	scanTypes.Store(concrete.String(), concrete)
+}
+
+func (r *converterRegistry) impl(iface reflect.Type) (reflect.Type, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	t, ok := r.impls[iface]
+	return t, ok
+}
+
+// concreteFor returns the type an interface field of type iface scans as: the
+// type named by an `as=` tag option, else the implementation registered on m,
+// else the global one.
+func (m *Mapper) concreteFor(iface reflect.Type, tag string) (reflect.Type, bool, error) {
+	if name, ok := tagValue(tag, "as"); ok {
+		v, ok := scanTypes.Load(name)
+		if !ok {
+			return nil, false, fmt.Errorf("xsql: as=%s: type not registered (see RegisterType)", name)
+		}
+		t := v.(reflect.Type)
+		if t.Kind() == reflect.Interface || !t.Implements(iface) {
+			return nil, false, fmt.Errorf("xsql: as=%s: %s does not implement %s", name, t, iface)
+		}
+		return t, true, nil
+	}
+	if t, ok := m.convs.impl(iface); ok {
+		return t, true, nil
+	}
+	t, ok := globalConverters.impl(iface)
+	return t, ok, nil
+}
+
+// asInterface adapts st, a step for concrete type ct, to a destination of
+// interface type: the value is built as ct and then stored in the interface.
+func asInterface(st step, ct reflect.Type) step {
+	store := func(dst, v reflect.Value) {
+		if v.Kind() == reflect.Ptr && v.IsNil() {
+			dst.SetZero()
+			return
+		}
+		dst.Set(v)
+	}
+	if st.kind == stepIndirect {
+		return step{kind: stepIndirect, fpath: st.fpath, convTo: st.convTo, post: func(dst, src reflect.Value) error {
+			v := reflect.New(ct).Elem()
+			if err := st.post(v, src); err != nil {
+				return err
+			}
+			store(dst, v)
+			return nil
+		}}
+	}
+	return step{kind: stepIndirect, fpath: st.fpath, convTo: ct, post: func(dst, src reflect.Value) error {
+		store(dst, src)
+		return nil
+	}}
+}
@@ -0,0 +1,99 @@
+package xsql
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+type testShape interface{ Area() float64 }
+
+// testCircle scans itself from a radius.
+type testCircle struct{ R float64 }
+
+func (c *testCircle) Area() float64 { return 3 * c.R * c.R }
+func (c *testCircle) Scan(src any) error {
+	switch v := src.(type) {
+	case float64:
+		c.R = v
+	case int64:
+		c.R = float64(v)
+	default:
+		return fmt.Errorf("testCircle: unsupported %T", src)
+	}
+	return nil
+}
+
+// testSquare decodes itself from text such as "side=2".
+type testSquare struct{ Side float64 }
+
+func (s testSquare) Area() float64 { return s.Side * s.Side }
+func (s *testSquare) UnmarshalText(b []byte) error {
+	v, ok := strings.CutPrefix(string(b), "side=")
+	if !ok {
+		return fmt.Errorf("testSquare: bad text %q", b)
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	s.Side = f
+	return err
+}
+
+func TestInterfaceFields(t *testing.T) {
+	RegisterType[testSquare]()
+	shapeType := reflect.TypeOf((*testShape)(nil)).Elem()
+	m := NewMapper()
+	m.RegisterImplementation(shapeType, reflect.TypeOf(&testCircle{}))
+
+	type Row struct {
+		Main  testShape `db:"main"`
+		Extra testShape `db:"extra,as=xsql.testSquare"`
+	}
+	db := newTestDB(t, func(q string, _ []driver.NamedValue) ([]string, [][]driver.Value, error) {
+		if q == "one" {
+			return []string{"r"}, [][]driver.Value{{int64(1)}}, nil
+		}
+		return []string{"main", "extra"}, [][]driver.Value{
+			{2.0, "side=3"},
+			{nil, []byte("side=1")},
+		}, nil
+	})
+	defer func() { _ = db.Close() }()
+	ctx := context.Background()
+
+	got, err := QueryWith[Row](ctx, m, db, "q")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c, ok := got[0].Main.(*testCircle); !ok || c.R != 2 {
+		t.Fatalf("main: %#v", got[0].Main)
+	}
+	if s, ok := got[0].Extra.(testSquare); !ok || s.Area() != 9 {
+		t.Fatalf("extra: %#v", got[0].Extra)
+	}
+	if got[1].Main != nil {
+		t.Fatalf("NULL should leave the interface nil, got %#v", got[1].Main)
+	}
+
+	if s, err := GetWith[testShape](ctx, m, db, "one"); err != nil || s.Area() != 3 {
+		t.Fatalf("GetWith[testShape] = %#v, %v", s, err)
+	}
+
+	// The default mapper has no implementation registered for Main, and
+	// unknown as= names are rejected.
+	type Unknown struct {
+		S testShape `db:"main,as=geo.Nowhere"`
+	}
+	if _, err := Query[Unknown](ctx, db, "q"); err == nil || !strings.Contains(err.Error(), "as=geo.Nowhere") {
+		t.Fatalf("expected unregistered type error, got %v", err)
+	}
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("registering a non-implementation should panic")
+		}
+	}()
+	m.RegisterImplementation(shapeType, reflect.TypeOf(0))
+}
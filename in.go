@@ -0,0 +1,73 @@
+package xsql
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// In expands slice and array arguments of a query written with ? placeholders
+// into one placeholder per element, for IN lists without named parameters.
+// Other arguments are kept as they are, and the query keeps ? placeholders;
+// pass the result through [Rebind] for other placeholder styles.
+//
+// Expansion follows the named-parameter rules: []byte and driver.Valuer
+// slices are single values, element Valuers are converted, and an empty
+// slice becomes NULL. The number of ? placeholders (outside quotes and
+// comments, not counting "??" escapes and operators such as "?|") must match
+// the number of args.
+//
+// Example:
+//
+//	q, args, err := xsql.In(`SELECT * FROM users WHERE status = ? AND id IN (?)`, "active", []int64{1, 2, 3})
+//	// q    => SELECT * FROM users WHERE status = ? AND id IN (?,?,?)
+//	// args => [active 1 2 3]
+//	q, args, err = xsql.Rebind(q, xsql.PlaceholderDollar, args...)
+func In(query string, args ...any) (string, []any, error) {
+	toks, err := findQuestionMarks(query)
+	if err != nil {
+		return "", nil, err
+	}
+	var qs []int
+	for _, t := range toks {
+		if !t.esc {
+			qs = append(qs, t.start)
+		}
+	}
+	if len(qs) != len(args) {
+		return "", nil, fmt.Errorf("xsql: In: query has %d placeholders but %d args", len(qs), len(args))
+	}
+
+	var b strings.Builder
+	b.Grow(len(query))
+	out := make([]any, 0, len(args))
+	last := 0
+	for i, a := range args {
+		b.WriteString(query[last:qs[i]])
+		last = qs[i] + 1
+		rv := reflect.ValueOf(a)
+		if !isSliceOrArray(rv) {
+			b.WriteByte('?')
+			out = append(out, a)
+			continue
+		}
+		n := rv.Len()
+		if n == 0 {
+			b.WriteString("NULL")
+			continue
+		}
+		for j := 0; j < n; j++ {
+			if j > 0 {
+				b.WriteByte(',')
+			}
+			b.WriteByte('?')
+			v, err := argValue(rv.Index(j).Interface())
+			if err != nil {
+				return "", nil, fmt.Errorf("xsql: In: arg %d: %w", i+1, err)
+			}
+			out = append(out, v)
+		}
+	}
+	b.WriteString(query[last:])
+	return b.String(), out, nil
+}
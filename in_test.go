@@ -0,0 +1,34 @@
+package xsql
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestIn(t *testing.T) {
+	q, args, err := In(`SELECT * FROM t WHERE s = ? AND id IN (?) AND c IN (?) AND x NOT IN (?) AND note = '?' AND b = ? -- ?`,
+		"on", []int64{1, 2, 3}, []testCode{"a"}, []string{}, []byte("raw"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `SELECT * FROM t WHERE s = ? AND id IN (?,?,?) AND c IN (?) AND x NOT IN (NULL) AND note = '?' AND b = ? -- ?`; q != want {
+		t.Fatalf("sql mismatch:\n got=%q\nwant=%q", q, want)
+	}
+	eqSlice(t, args, []any{"on", int64(1), int64(2), int64(3), "code-a", []byte("raw")}, "args")
+
+	// Composes with Rebind for other placeholder styles.
+	q, args, err = Rebind(q, PlaceholderDollar, args...)
+	if err != nil || q != `SELECT * FROM t WHERE s = $1 AND id IN ($2,$3,$4) AND c IN ($5) AND x NOT IN (NULL) AND note = '?' AND b = $6 -- ?` || len(args) != 6 {
+		t.Fatalf("Rebind: %q %v %v", q, args, err)
+	}
+
+	if q, args, err := In(`SELECT ?`, testTags{"x"}); err != nil || q != `SELECT ?` || !reflect.DeepEqual(args, []any{testTags{"x"}}) {
+		t.Fatalf("Valuer slice: %q %v %v", q, args, err)
+	}
+	if _, _, err := In(`SELECT ?, ?`, 1); err == nil {
+		t.Fatal("expected placeholder count error")
+	}
+	if _, _, err := In(`SELECT ?`, []testCode{""}); err == nil {
+		t.Fatal("expected Valuer error")
+	}
+}
@@ -0,0 +1,300 @@
+package xsql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Pair holds the two values scanned from a single row by [Query2].
+type Pair[A, B any] struct {
+	First  A
+	Second B
+}
+
+// Query2 executes the SQL query and scans each row into a [Pair] of A and B,
+// splitting the result columns between the two destinations. It is meant for
+// JOIN queries that select the columns of A followed by the columns of B:
+//
+//	SELECT u.id, u.email, o.id, o.total FROM users u JOIN orders o ON o.user_id = u.id
+//
+// The split point is the number of columns A maps (the number of distinct
+// column names a struct exposes, or 1 for primitives). Query2 fails if the
+// columns before it are not each a distinct column of A, as happens when the
+// query selects only some of A's columns or the fields of a nested struct.
+// Columns before the split are mapped into A and the rest into B using the
+// usual mapping rules, so A and B may share column names such as "id".
+// Use [Query2Split] when the split point cannot be derived from A.
+// A and B are always scanned by the mapper, even if they implement
+// [RowScanner], since each receives only part of the row.
+//
+// Example:
+//
+//	rows, err := xsql.Query2[User, Order](ctx, db,
+//	    `SELECT u.id, u.email, o.id, o.total FROM users u JOIN orders o ON o.user_id = u.id`)
+//	for _, r := range rows {
+//	    fmt.Println(r.First.Email, r.Second.Total)
+//	}
+func Query2[A, B any](ctx context.Context, q Querier, query string, args ...any) ([]Pair[A, B], error) {
+	return query2[A, B](ctx, q, -1, query, args)
+}
+
+// Query2Split is like [Query2] but takes an explicit split index: columns
+// [0, split) are mapped into A and columns [split, n) into B.
+func Query2Split[A, B any](ctx context.Context, q Querier, split int, query string, args ...any) ([]Pair[A, B], error) {
+	if split < 1 {
+		return nil, fmt.Errorf("xsql: invalid column split %d", split)
+	}
+	return query2[A, B](ctx, q, split, query, args)
+}
+
+func query2[A, B any](ctx context.Context, q Querier, split int, query string, args []any) (out []Pair[A, B], err error) {
+	args, cfg, err := callArgs(args)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := cfg.context(ctx)
+	defer cancel()
+
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if cerr := rows.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}()
+
+	cols, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	m, strict := cfg.scanMapper()
+	rtA := reflect.TypeOf((*A)(nil)).Elem()
+	rtB := reflect.TypeOf((*B)(nil)).Elem()
+	derived := split < 0
+	if derived {
+		split = m.mappedColumnCount(rtA)
+	}
+	if split < 1 || split >= len(cols) {
+		return nil, fmt.Errorf("xsql: cannot split %d columns at %d between %s and %s", len(cols), split, rtA, rtB)
+	}
+	if derived {
+		if err := m.checkSplit(rtA, cols[:split]); err != nil {
+			return nil, fmt.Errorf("xsql: cannot split %d columns at %d between %s and %s: %w; use Query2Split", len(cols), split, rtA, rtB, err)
+		}
+	}
+
+	plA, err := m.planFor(rtA, cols[:split])
+	if err != nil {
+		return nil, err
+	}
+	plB, err := m.planFor(rtB, cols[split:])
+	if err != nil {
+		return nil, err
+	}
+	if strict {
+		if err := errors.Join(plA.checkStrict(), plB.checkStrict()); err != nil {
+			return nil, err
+		}
+	}
+
+	spans := []colSpan{{p: plA}, {p: plB, off: len(plA.steps)}}
+	for rows.Next() {
+		rvA, rvB := reflect.New(plA.rt), reflect.New(plB.rt)
+		destsA, cleanupA, err := plA.destPtrs(rvA)
+		if err != nil {
+			return nil, err
+		}
+		destsB, cleanupB, err := plB.destPtrs(rvB)
+		if err != nil {
+			return nil, err
+		}
+		if err := rows.Scan(append(destsA, destsB...)...); err != nil {
+			return nil, scanError(rows, len(out), err, spans...)
+		}
+		if err := cleanupA(); err != nil {
+			return nil, scanError(rows, len(out), err, spans...)
+		}
+		if err := cleanupB(); err != nil {
+			return nil, scanError(rows, len(out), err, spans...)
+		}
+		out = append(out, Pair[A, B]{First: plA.result(rvA).(A), Second: plB.result(rvB).(B)})
+	}
+	if ne := rows.Err(); ne != nil {
+		return nil, ne
+	}
+	return out, nil
+}
+
+// checkSplit reports whether cols, the columns before a split derived from
+// the column count of rt, are each a distinct top-level column of rt. A
+// column rt has no field for, a field selected twice, or a column of a nested
+// struct (whose fields rt counts as one column) means the query did not
+// select rt's columns as counted, so the split would hand columns of one
+// destination to the other.
+func (m *Mapper) checkSplit(rt reflect.Type, cols []string) error {
+	if !m.isRowStruct(rt) {
+		return nil
+	}
+	idx := m.structIndex(rt)
+	seen := make(map[string]bool, len(cols))
+	for _, c := range cols {
+		n := m.normalizeCol(c)
+		fp, ok := idx.byName[n]
+		if !ok || strings.Contains(n, ".") {
+			return fmt.Errorf("column %q is not a column of %s", c, rt)
+		}
+		key := fmt.Sprint(fp)
+		if seen[key] {
+			return fmt.Errorf("column %q maps into %s twice", c, rt)
+		}
+		seen[key] = true
+	}
+	return nil
+}
+
+// Prefix declares the column prefix of one destination in [QueryJoined].
+// Prefixes are matched case-insensitively against result column names.
+type Prefix string
+
+// QueryJoined executes the SQL query and scans each row into a [Pair] of A and
+// B, routing columns by prefix. The first [Prefix] among args belongs to A and
+// the second to B; all other args are passed to the driver. A column is mapped
+// into the destination whose prefix it starts with (the longer prefix wins if
+// both match), with the prefix stripped before the usual name lookup. Columns
+// matching neither prefix are ignored. A and B must be struct types, and are
+// scanned by the mapper even if they implement [RowScanner].
+//
+// Example:
+//
+//	rows, err := xsql.QueryJoined[User, Order](ctx, db,
+//	    `SELECT u.id AS u_id, u.email AS u_email, o.id AS o_id, o.total AS o_total
+//	     FROM users u JOIN orders o ON o.user_id = u.id WHERE u.id = $1`,
+//	    xsql.Prefix("u_"), xsql.Prefix("o_"), 42)
+func QueryJoined[A, B any](ctx context.Context, q Querier, query string, args ...any) (out []Pair[A, B], err error) {
+	var prefixes []string
+	rest := make([]any, 0, len(args))
+	for _, a := range args {
+		if p, ok := a.(Prefix); ok {
+			prefixes = append(prefixes, string(p))
+			continue
+		}
+		rest = append(rest, a)
+	}
+	if len(prefixes) != 2 {
+		return nil, fmt.Errorf("xsql: QueryJoined requires exactly 2 prefixes; got %d", len(prefixes))
+	}
+
+	rest, cfg, err := callArgs(rest)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := cfg.context(ctx)
+	defer cancel()
+
+	m, strict := cfg.scanMapper()
+	for i := range prefixes {
+		prefixes[i] = m.foldName(prefixes[i])
+	}
+	rtA := reflect.TypeOf((*A)(nil)).Elem()
+	rtB := reflect.TypeOf((*B)(nil)).Elem()
+	if !isStruct(rtA) || !isStruct(rtB) {
+		return nil, fmt.Errorf("xsql: QueryJoined requires struct types; got %s and %s", rtA, rtB)
+	}
+
+	rows, err := q.QueryContext(ctx, query, rest...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if cerr := rows.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}()
+
+	cols, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	colsA, colsB := m.splitByPrefix(cols, prefixes[0], prefixes[1])
+	plA, err := m.planFor(rtA, colsA)
+	if err != nil {
+		return nil, err
+	}
+	plB, err := m.planFor(rtB, colsB)
+	if err != nil {
+		return nil, err
+	}
+	if strict {
+		if err := errors.Join(plA.checkStrict(), plB.checkStrict()); err != nil {
+			return nil, err
+		}
+	}
+
+	dests := make([]any, len(cols))
+	spans := []colSpan{{p: plA}, {p: plB}} // both plans span every column, masked
+	for rows.Next() {
+		rvA, rvB := reflect.New(plA.rt), reflect.New(plB.rt)
+		destsA, cleanupA, err := plA.destPtrs(rvA)
+		if err != nil {
+			return nil, err
+		}
+		destsB, cleanupB, err := plB.destPtrs(rvB)
+		if err != nil {
+			return nil, err
+		}
+		for i := range dests {
+			if colsA[i] != "" {
+				dests[i] = destsA[i]
+			} else {
+				dests[i] = destsB[i]
+			}
+		}
+		if err := rows.Scan(dests...); err != nil {
+			return nil, scanError(rows, len(out), err, spans...)
+		}
+		if err := cleanupA(); err != nil {
+			return nil, scanError(rows, len(out), err, spans...)
+		}
+		if err := cleanupB(); err != nil {
+			return nil, scanError(rows, len(out), err, spans...)
+		}
+		out = append(out, Pair[A, B]{First: plA.result(rvA).(A), Second: plB.result(rvB).(B)})
+	}
+	if ne := rows.Err(); ne != nil {
+		return nil, ne
+	}
+	return out, nil
+}
+
+// splitByPrefix assigns each column to the destination whose prefix it
+// carries, returning per-destination column lists with the prefix stripped.
+// Columns not assigned to a destination are left empty in its list, which the
+// planner treats as unmapped.
+func (m *Mapper) splitByPrefix(cols []string, pa, pb string) (colsA, colsB []string) {
+	colsA = make([]string, len(cols))
+	colsB = make([]string, len(cols))
+	for i, c := range cols {
+		c = m.normalizeCol(c)
+		inA := hasPrefix(c, pa) && len(c) > len(pa)
+		inB := hasPrefix(c, pb) && len(c) > len(pb)
+		switch {
+		case inA && inB:
+			if len(pa) >= len(pb) {
+				colsA[i] = c[len(pa):]
+			} else {
+				colsB[i] = c[len(pb):]
+			}
+		case inA:
+			colsA[i] = c[len(pa):]
+		case inB:
+			colsB[i] = c[len(pb):]
+		}
+	}
+	return colsA, colsB
+}
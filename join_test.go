@@ -0,0 +1,198 @@
+package xsql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+type joinUser struct {
+	ID    int64  `db:"id"`
+	Email string `db:"email"`
+}
+
+type joinOrder struct {
+	ID    int64   `db:"id"`
+	Total float64 `db:"total"`
+}
+
+func TestQuery2_SplitByMappedColumnCount(t *testing.T) {
+	db := newTestDB(t, func(q string, _ []driver.NamedValue) ([]string, [][]driver.Value, error) {
+		cols := []string{"id", "email", "id", "total"}
+		rows := [][]driver.Value{
+			{int64(1), []byte("a@x"), int64(10), 9.5},
+			{int64(1), []byte("a@x"), int64(11), 1.25},
+		}
+		return cols, rows, nil
+	})
+	defer func() { _ = db.Close() }()
+
+	got, err := Query2[joinUser, joinOrder](context.Background(), db, "join")
+	if err != nil {
+		t.Fatalf("Query2 error: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("want 2 rows, got %d", len(got))
+	}
+	if got[0].First.ID != 1 || got[0].First.Email != "a@x" || got[0].Second.ID != 10 || got[0].Second.Total != 9.5 {
+		t.Fatalf("unexpected first pair: %+v", got[0])
+	}
+	if got[1].Second.ID != 11 || got[1].Second.Total != 1.25 {
+		t.Fatalf("unexpected second pair: %+v", got[1])
+	}
+}
+
+func TestQuery2Split_ExplicitIndexAndPrimitive(t *testing.T) {
+	db := newTestDB(t, func(q string, _ []driver.NamedValue) ([]string, [][]driver.Value, error) {
+		return []string{"email", "n"}, [][]driver.Value{{[]byte("b@x"), int64(3)}}, nil
+	})
+	defer func() { _ = db.Close() }()
+
+	got, err := Query2Split[joinUser, int64](context.Background(), db, 1, "q")
+	if err != nil {
+		t.Fatalf("Query2Split error: %v", err)
+	}
+	if len(got) != 1 || got[0].First.Email != "b@x" || got[0].First.ID != 0 || got[0].Second != 3 {
+		t.Fatalf("unexpected: %+v", got)
+	}
+}
+
+func TestQuery2_InvalidSplit(t *testing.T) {
+	db := newTestDB(t, func(q string, _ []driver.NamedValue) ([]string, [][]driver.Value, error) {
+		return []string{"id", "email"}, [][]driver.Value{{int64(1), "x"}}, nil
+	})
+	defer func() { _ = db.Close() }()
+
+	// joinUser maps both columns, leaving nothing for joinOrder.
+	if _, err := Query2[joinUser, joinOrder](context.Background(), db, "q"); err == nil {
+		t.Fatal("expected split error")
+	}
+	if _, err := Query2Split[joinUser, joinOrder](context.Background(), db, 0, "q"); err == nil {
+		t.Fatal("expected error for split 0")
+	}
+}
+
+type joinOrg struct {
+	Name string `db:"name"`
+	Plan string `db:"plan"`
+}
+
+type joinAccount struct {
+	ID  int64   `db:"id"`
+	Org joinOrg `db:"org"`
+}
+
+func TestQuery2_DerivedSplitMustMatchA(t *testing.T) {
+	var cols []string
+	db := newTestDB(t, func(q string, _ []driver.NamedValue) ([]string, [][]driver.Value, error) {
+		row := make([]driver.Value, len(cols))
+		for i := range row {
+			row[i] = int64(i)
+		}
+		return cols, [][]driver.Value{row}, nil
+	})
+	defer func() { _ = db.Close() }()
+	ctx := context.Background()
+
+	// joinAccount counts as 2 columns (id, org), but the query selects the
+	// fields of Org, so a split at 2 would hand org.plan to joinOrder.
+	cols = []string{"id", "org.name", "org.plan", "id", "total"}
+	if _, err := Query2[joinAccount, joinOrder](ctx, db, "q"); err == nil || !strings.Contains(err.Error(), `"org.name"`) {
+		t.Fatalf("nested struct: %v", err)
+	}
+	if got, err := Query2Split[joinAccount, joinOrder](ctx, db, 3, "q"); err != nil || got[0].First.Org.Plan != "2" || got[0].Second.ID != 3 {
+		t.Fatalf("explicit split: %+v %v", got, err)
+	}
+
+	// Only one of joinUser's two columns is selected: the split would give
+	// joinOrder's id to joinUser.
+	cols = []string{"id", "id", "total"}
+	if _, err := Query2[joinUser, joinOrder](ctx, db, "q"); err == nil || !strings.Contains(err.Error(), "twice") {
+		t.Fatalf("short A: %v", err)
+	}
+}
+
+func TestQueryJoined_RoutesColumnsByPrefix(t *testing.T) {
+	var gotArgs []driver.NamedValue
+	db := newTestDB(t, func(q string, args []driver.NamedValue) ([]string, [][]driver.Value, error) {
+		gotArgs = args
+		cols := []string{"o_id", "U_ID", "u_email", "o_total", "extra"}
+		rows := [][]driver.Value{{int64(10), int64(1), []byte("a@x"), 2.5, "ignored"}}
+		return cols, rows, nil
+	})
+	defer func() { _ = db.Close() }()
+
+	got, err := QueryJoined[joinUser, joinOrder](context.Background(), db, "q",
+		Prefix("u_"), Prefix("o_"), int64(7))
+	if err != nil {
+		t.Fatalf("QueryJoined error: %v", err)
+	}
+	if len(gotArgs) != 1 || gotArgs[0].Value != int64(7) {
+		t.Fatalf("prefixes should not reach the driver: %#v", gotArgs)
+	}
+	if len(got) != 1 {
+		t.Fatalf("want 1 row, got %d", len(got))
+	}
+	p := got[0]
+	if p.First.ID != 1 || p.First.Email != "a@x" || p.Second.ID != 10 || p.Second.Total != 2.5 {
+		t.Fatalf("unexpected pair: %+v", p)
+	}
+}
+
+func TestQueryJoined_Errors(t *testing.T) {
+	db := newTestDB(t, func(q string, _ []driver.NamedValue) ([]string, [][]driver.Value, error) {
+		return []string{"u_id"}, [][]driver.Value{{int64(1)}}, nil
+	})
+	defer func() { _ = db.Close() }()
+
+	ctx := context.Background()
+	if _, err := QueryJoined[joinUser, joinOrder](ctx, db, "q", Prefix("u_")); err == nil {
+		t.Fatal("expected error for missing prefix")
+	}
+	if _, err := QueryJoined[joinUser, int64](ctx, db, "q", Prefix("u_"), Prefix("o_")); err == nil {
+		t.Fatal("expected error for non-struct destination")
+	}
+}
+
+func TestSplitByPrefix_LongestPrefixWins(t *testing.T) {
+	a, b := NewMapper().splitByPrefix([]string{"u_id", "u_x_id", "id"}, "u_", "u_x_")
+	if a[0] != "id" || b[0] != "" {
+		t.Fatalf("u_id: a=%q b=%q", a[0], b[0])
+	}
+	if a[1] != "" || b[1] != "id" {
+		t.Fatalf("u_x_id: a=%q b=%q", a[1], b[1])
+	}
+	if a[2] != "" || b[2] != "" {
+		t.Fatalf("id should be unassigned: a=%q b=%q", a[2], b[2])
+	}
+}
+
+func TestQuery2_QueryOpts(t *testing.T) {
+	db := newTestDB(t, func(q string, args []driver.NamedValue) ([]string, [][]driver.Value, error) {
+		if len(args) != 1 {
+			t.Fatalf("options reached the driver: %v", args)
+		}
+		return []string{"id", "email", "id", "total", "u_extra"}, [][]driver.Value{{int64(1), "a@x", int64(2), 3.5, nil}}, nil
+	})
+	defer func() { _ = db.Close() }()
+	ctx := context.Background()
+
+	if got, err := Query2Split[joinUser, joinOrder](ctx, db, 2, "q", 1, WithTimeout(time.Minute)); err != nil || len(got) != 1 || got[0].Second.Total != 3.5 {
+		t.Fatalf("Query2Split: %+v %v", got, err)
+	}
+	if _, err := Query2Split[joinUser, joinOrder](ctx, db, 2, "q", 1, WithStrict()); !errors.Is(err, ErrUnmappedColumn) {
+		t.Fatalf("Query2Split strict: %v", err)
+	}
+	m := NewMapper()
+	m.Strict = true
+	if _, err := QueryJoined[joinUser, joinOrder](ctx, db, "q", Prefix("u_"), Prefix("o_"), 1, WithMapper(m)); !errors.Is(err, ErrUnmappedColumn) {
+		t.Fatalf("QueryJoined WithMapper: %v", err)
+	}
+	if _, err := QueryJoined[joinUser, joinOrder](ctx, db, "q", Prefix("u_"), Prefix("o_"), 1, sql.Named("x", 1)); !errors.Is(err, ErrMixedArgs) {
+		t.Fatalf("QueryJoined mixed args: %v", err)
+	}
+}
@@ -2,10 +2,15 @@ package xsql
 
 import (
 	"database/sql"
+	"encoding"
+	"errors"
 	"fmt"
 	"hash/fnv"
 	"reflect"
+	"slices"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -13,78 +18,235 @@ import (
 type Mapper struct {
 	planCache        sync.Map // key: planKey -> *plan   (per (T, column-set))
 	structIndexCache sync.Map // key: reflect.Type -> *fieldIndex (per T)
-	Strict           bool     // reserved: future strict mode (not enforced here)
+	Strict           bool     // fail scans whose result columns do not all map to a field
+
+	nameMapper     func(string) string // column name for untagged fields; nil = field name
+	caseSensitive  bool                // match names byte-for-byte instead of ASCII case-folding
+	unicodeNames   bool                // fold case with Unicode rules instead of ASCII only
+	normalizeName  func(string) string // applied to field and column names before folding; may be nil
+	duplicates     DuplicatePolicy     // how repeated result column names are assigned
+	convs          converterRegistry   // per-mapper converters, consulted before the global ones
+	timeLayouts    []string            // parse textual time columns; nil = scan time.Time directly
+	timeLoc        *time.Location      // convert scanned times to this location; nil = as delivered
+	checkAmbiguous bool                // fail plans for structs with same-depth column collisions
+	nullToZero     bool                // scan NULL into non-nullable destinations as the zero value
+	stats          mapperCounters      // see Stats
+	valueAppliers  sync.Map            // valueKey -> conversion of single values, for Option.Scan
 }
 
-func NewMapper() *Mapper { return &Mapper{} }
+// ErrUnmappedColumn is returned in strict mode when a result column has no
+// destination field. The returned error wraps it with the column name.
+var ErrUnmappedColumn = errors.New("xsql: unmapped column")
+
+// ErrMissingColumn is returned when the result lacks the column of a field
+// tagged `db:"col,required"`, whatever the strictness of the mapper. The
+// returned error wraps it with the column and field names.
+var ErrMissingColumn = errors.New("xsql: missing required column")
+
+// ErrAmbiguousColumn is returned by mappers created with [WithAmbiguityCheck]
+// when two fields of a struct map to the same column, and by every mapper when
+// an alternative column name (`db:"a|b"`) is also claimed by another field.
+// The returned error wraps it with the column and both field paths.
+var ErrAmbiguousColumn = errors.New("xsql: ambiguous column")
+
+// ErrDuplicateColumn is returned by mappers created with
+// WithDuplicateColumns(DuplicateError) when a result column name occurs more
+// than once. The returned error wraps it with the name and both positions.
+var ErrDuplicateColumn = errors.New("xsql: duplicate result column")
+
+// ErrNullColumn is matched by scan errors caused by a NULL column whose
+// destination cannot hold NULL, such as an int64 field. The returned error
+// names the column, the destination field, and the zero-based row index when
+// known, and also wraps the underlying database/sql or conversion error.
+var ErrNullColumn = errors.New("xsql: cannot scan NULL")
+
+// NewMapper returns a Mapper configured by opts.
+func NewMapper(opts ...MapperOption) *Mapper {
+	m := &Mapper{}
+	for _, o := range opts {
+		o(m)
+	}
+	return m
+}
 
 // --- package-level lazy global mapper (used by Query/Get) ---
 
-var (
-	mapper     *Mapper
-	mapperOnce sync.Once
-)
+var defaultMapper atomic.Pointer[Mapper]
 
 func getMapper() *Mapper {
-	mapperOnce.Do(func() { mapper = NewMapper() })
-	return mapper
+	if m := defaultMapper.Load(); m != nil {
+		return m
+	}
+	defaultMapper.CompareAndSwap(nil, NewMapper())
+	return defaultMapper.Load()
+}
+
+// SetDefaultMapper replaces the package-level mapper used by Query, Get, and
+// every other function that does not take a Mapper explicitly. Configure the
+// mapper fully before installing it; it is typically called once at startup.
+//
+// The swap is atomic, so it is safe while queries are running: calls that
+// already picked up the previous mapper finish with it. Plans cached by the
+// previous mapper are not carried over. A nil m restores a fresh default
+// mapper.
+//
+// Example:
+//
+//	m := xsql.NewMapper()
+//	m.Strict = true
+//	xsql.SetDefaultMapper(m)
+func SetDefaultMapper(m *Mapper) {
+	if m == nil {
+		m = NewMapper()
+	}
+	defaultMapper.Store(m)
+}
+
+// DefaultMapper returns the package-level mapper used by Query, Get, and every
+// other function that does not take a Mapper explicitly: the one installed by
+// [SetDefaultMapper], or a mapper with default settings.
+//
+// Example:
+//
+//	s := xsql.DefaultMapper().Stats()
+func DefaultMapper() *Mapper {
+	return getMapper()
 }
 
 // scanWithMapper is the hot path used by Query/Get. It scans the *current row* into T using m's caches.
 func scanWithMapper[T any](m *Mapper, rows *sql.Rows) (T, error) {
-	var zero T
+	return scanRow[T](m, m.Strict, rows)
+}
+
+// scanRow is scanWithMapper with strict mode chosen by the caller.
+func scanRow[T any](m *Mapper, strict bool, rows *sql.Rows) (T, error) {
+	s := rowScanner[T]{m: m, strict: strict}
+	return s.scan(rows)
+}
+
+// rowScanner scans the rows of one result set into T. The columns cannot
+// change between rows of a result set, so the plan is resolved for the first
+// row that needs one and reused for the others.
+type rowScanner[T any] struct {
+	m      *Mapper
+	strict bool
+	pl     *plan
+}
 
+// scan scans the *current row* of rows into T.
+func (s *rowScanner[T]) scan(rows *sql.Rows) (T, error) {
+	if v, ok, err := scanOwn[T](rows); ok {
+		return v, err
+	}
+	if s.pl == nil {
+		pl, err := planRows[T](s.m, s.strict, rows)
+		if err != nil {
+			var zero T
+			return zero, err
+		}
+		s.pl = pl
+	}
+	return scanPlan[T](s.pl, rows)
+}
+
+// planRows returns m's plan for scanning the result set of rows into T.
+func planRows[T any](m *Mapper, strict bool, rows *sql.Rows) (*plan, error) {
 	cols, err := rows.Columns()
 	if err != nil {
-		return zero, err
-	}
-	if len(cols) == 0 {
-		return zero, fmt.Errorf("xsql: query returned zero columns")
+		return nil, err
 	}
+	return planCols[T](m, strict, cols)
+}
 
-	// Normalize & hash columns
-	h := fnv.New64a()
-	for i := range cols {
-		cols[i] = normalizeColAscii(cols[i])
-		_, _ = h.Write([]byte(cols[i]))
-		_, _ = h.Write([]byte{0})
+// planCols returns m's plan for scanning rows with columns cols into T. It
+// normalizes cols in place.
+func planCols[T any](m *Mapper, strict bool, cols []string) (*plan, error) {
+	if len(cols) == 0 {
+		return nil, fmt.Errorf("xsql: query returned zero columns")
 	}
-	colHash := h.Sum64()
 
 	rt := reflect.TypeOf((*T)(nil)).Elem()
-	pl, err := m.getPlan(rt, cols, colHash)
+	pl, err := m.planFor(rt, cols)
 	if err != nil {
-		return zero, err
+		return nil, err
+	}
+	if strict {
+		if err := pl.checkStrict(); err != nil {
+			return nil, err
+		}
 	}
+	return pl, nil
+}
+
+// scanPlan scans the *current row* into a new T using an already resolved plan.
+func scanPlan[T any](pl *plan, rows *sql.Rows) (T, error) {
+	if pl.fast && !pl.ptr {
+		return scanFast[T](pl, rows)
+	}
+	var zero T
 
 	// Allocate destination & scan
-	rv := reflect.New(rt) // *T
+	rv := reflect.New(pl.rt) // *T, or T itself in pointer mode
 	dests, cleanup, err := pl.destPtrs(rv)
 	if err != nil {
 		return zero, err
 	}
 	if err := rows.Scan(dests...); err != nil {
-		return zero, err
+		return zero, scanError(rows, -1, err, colSpan{p: pl})
 	}
 	if err := cleanup(); err != nil {
-		return zero, err
+		return zero, scanError(rows, -1, err, colSpan{p: pl})
 	}
-	return rv.Elem().Interface().(T), nil
+	return pl.result(rv).(T), nil
 }
 
 // ---------------- Planning & caches ----------------
 
+// planKey identifies a cached plan. The column set enters it as a hash, so
+// two column sets may share a key; the plan keeps its columns to tell them
+// apart (see plan.matches).
 type planKey struct {
 	rt    reflect.Type
 	hash  uint64 // FNV-1a of normalized columns
 	ncols int
+	gen   uint64 // global converter registry generation the plan was built against
+	mgen  uint64 // the mapper's own converter generation
 }
 
 type plan struct {
 	rt       reflect.Type
-	steps    []step // one per column
+	cols     []string // the normalized columns the plan was built for
+	steps    []step   // one per column
 	isStruct bool
-	isScan   bool // T implements sql.Scanner
+	isScan   bool                   // T implements sql.Scanner
+	ptr      bool                   // T is *rt: rows are scanned into fresh pointers and returned as is
+	unmapped []string               // struct plans: normalized columns with no field
+	hooks    [][]int                // AfterScanner values to call, deepest first (see afterScanHooks)
+	before   []string               // columns for BeforeScan; nil unless rt implements BeforeScanner
+	frames   *sync.Pool             // struct plans: *scanFrame values for scanDests
+	field    func(v any, i int) any // struct plans: accessor from RegisterFields, or nil
+	fast     bool                   // rows scan through field alone (see canScanFast)
+}
+
+// result returns the value a scan into rv (a *rt) produced, as T.
+func (p *plan) result(rv reflect.Value) any {
+	if p.ptr {
+		return rv.Interface()
+	}
+	return rv.Elem().Interface()
+}
+
+// matches reports whether p was built for the normalized columns cols.
+func (p *plan) matches(cols []string) bool {
+	return slices.Equal(p.cols, cols)
+}
+
+// checkStrict reports the first column p discards, wrapped in ErrUnmappedColumn.
+func (p *plan) checkStrict() error {
+	if len(p.unmapped) > 0 {
+		return fmt.Errorf("%w %q for %s", ErrUnmappedColumn, p.unmapped[0], p.rt)
+	}
+	return nil
 }
 
 type stepKind uint8
@@ -97,41 +259,80 @@ const (
 )
 
 type step struct {
-	kind   stepKind
-	fpath  []int        // for struct fields
-	convTo reflect.Type // for indirect
-	post   func(dst, src reflect.Value) error
+	kind    stepKind
+	fpath   []int        // for struct fields
+	convTo  reflect.Type // for indirect
+	post    func(dst, src reflect.Value) error
+	builtin bool // post is pickIndirect's plain conversion to a builtin string or number (see assignBuiltin)
+}
+
+// planFor normalizes cols in place, hashes them, and returns the cached plan
+// for rt over that column set.
+func (m *Mapper) planFor(rt reflect.Type, cols []string) (*plan, error) {
+	h := fnv.New64a()
+	for i := range cols {
+		cols[i] = m.normalizeCol(cols[i])
+		_, _ = h.Write([]byte(cols[i]))
+		_, _ = h.Write([]byte{0})
+	}
+	return m.getPlan(rt, cols, h.Sum64())
 }
 
 func (m *Mapper) getPlan(rt reflect.Type, cols []string, colHash uint64) (*plan, error) {
-	key := planKey{rt: rt, hash: colHash, ncols: len(cols)}
-	if v, ok := m.planCache.Load(key); ok {
+	key := planKey{rt: rt, hash: colHash, ncols: len(cols), gen: globalConverters.gen.Load(), mgen: m.convs.gen.Load()}
+	if v, ok := m.planCache.Load(key); ok && v.(*plan).matches(cols) {
+		m.stats.hits.Add(1)
 		return v.(*plan), nil
 	}
+	m.stats.misses.Add(1)
+
+	// *S for a row struct S reuses the plan for S; scanning allocates one S
+	// per row and hands out its address instead of copying the value.
+	if rt.Kind() == reflect.Ptr && rt.Elem().Kind() == reflect.Struct && m.isRowStruct(rt.Elem()) {
+		ep, err := m.getPlan(rt.Elem(), cols, colHash)
+		if err != nil {
+			return nil, err
+		}
+		p := *ep
+		p.ptr = true
+		return m.storePlan(key, &p), nil
+	}
 
 	p := &plan{
 		rt:       rt,
-		isStruct: isStruct(rt),
+		cols:     slices.Clone(cols),
+		isStruct: m.isRowStruct(rt),
 		isScan:   implementsScanner(rt),
 	}
 
 	if p.isStruct {
 		indexer := m.structIndex(rt)
-		p.steps = make([]step, len(cols))
-		for i, c := range cols {
-			if fp, ok := indexer.byName[c]; ok {
-				st, err := makeFieldStep(rt, fp)
-				if err != nil {
-					return nil, err
-				}
-				p.steps[i] = st
-			} else {
-				p.steps[i] = step{kind: stepDrop}
-			}
+		if err := checkRequired(rt, indexer, cols); err != nil {
+			return nil, err
+		}
+		if len(indexer.aliasConflicts) > 0 {
+			a := indexer.aliasConflicts[0]
+			return nil, fmt.Errorf("%w %q in %s: alternative name claimed by both %s and %s", ErrAmbiguousColumn, a.col, rt,
+				fieldNameByPath(rt, a.kept), fieldNameByPath(rt, a.other))
+		}
+		if m.checkAmbiguous && len(indexer.ambiguous) > 0 {
+			a := indexer.ambiguous[0]
+			return nil, fmt.Errorf("%w %q in %s: %s and %s", ErrAmbiguousColumn, a.col, rt,
+				fieldNameByPath(rt, a.kept), fieldNameByPath(rt, a.other))
+		}
+		var err error
+		if len(indexer.blocks) > 0 {
+			p.steps, p.unmapped, err = m.blockSteps(rt, indexer, cols)
+		} else {
+			p.steps, p.unmapped, err = m.nameSteps(rt, indexer, cols)
+		}
+		if err != nil {
+			return nil, err
 		}
 	} else {
 		// Non-struct T
-		if p.isScan {
+		_, isNull := nullValueType(rt)
+		if p.isScan && !m.hasConverter(rt) && !isNull {
 			if len(cols) != 1 {
 				return nil, fmt.Errorf("xsql: scanning %s requires exactly 1 column; got %d", rt, len(cols))
 			}
@@ -140,34 +341,170 @@ func (m *Mapper) getPlan(rt reflect.Type, cols []string, colHash uint64) (*plan,
 			if len(cols) != 1 {
 				return nil, fmt.Errorf("xsql: cannot map %d columns into %s; use a struct", len(cols), rt)
 			}
-			st, err := makeWholeStep(rt)
+			st, err := m.makeWholeStep(rt)
 			if err != nil {
 				return nil, err
 			}
+			if m.nullToZero && !m.acceptsNull(rt) {
+				st = withZeroNull(st)
+			}
 			p.steps = []step{st}
 		}
 	}
+	p.hooks = afterScanHooks(rt, p.steps, p.isStruct)
+	p.before = beforeScanCols(rt, cols)
+	if p.isStruct {
+		p.frames = &sync.Pool{New: func() any { return p.newFrame() }}
+		p.field = m.fieldFunc(rt)
+		p.fast = p.canScanFast()
+	}
+
+	return m.storePlan(key, p), nil
+}
+
+// nameSteps maps each column of cols to the field of rt with that name,
+// applying m's duplicate policy to repeated names. It also returns the
+// columns no field claims.
+func (m *Mapper) nameSteps(rt reflect.Type, idx *fieldIndex, cols []string) ([]step, []string, error) {
+	steps := make([]step, len(cols))
+	var unmapped []string
+	var seen map[string]int // occurrences of each column so far; nil when the last one wins
+	if m.duplicates != DuplicateLastWins {
+		seen = make(map[string]int, len(cols))
+	}
+	for i, c := range cols {
+		fp, ok := idx.byName[c]
+		if seen != nil && c != "" {
+			n := seen[c]
+			seen[c] = n + 1
+			if n > 0 {
+				if m.duplicates == DuplicateError {
+					return nil, nil, fmt.Errorf("%w %q at positions %d and %d", ErrDuplicateColumn, c, slices.Index(cols, c)+1, i+1)
+				}
+				if fp, ok = m.repeatPath(idx, c, n); !ok {
+					steps[i] = step{kind: stepDrop} // a repeat, not an unmapped column
+					continue
+				}
+			}
+		}
+		if !ok {
+			steps[i] = step{kind: stepDrop}
+			if c != "" { // empty names are columns a caller masked out
+				unmapped = append(unmapped, c)
+			}
+			continue
+		}
+		st, err := m.makeFieldStep(rt, fp)
+		if err != nil {
+			return nil, nil, err
+		}
+		steps[i] = st
+	}
+	return steps, unmapped, nil
+}
+
+// repeatPath returns the field for occurrence n+1 (n >= 1) of column c under
+// m's duplicate policy, or false if the repeat is to be dropped.
+func (m *Mapper) repeatPath(idx *fieldIndex, c string, n int) ([]int, bool) {
+	if m.duplicates == DuplicatePositional && n <= len(idx.extra[c]) {
+		return idx.extra[c][n-1], true
+	}
+	return nil, false
+}
 
-	m.planCache.Store(key, p)
-	return p, nil
+// checkRequired reports the first required column of idx missing from cols,
+// under none of its names.
+func checkRequired(rt reflect.Type, idx *fieldIndex, cols []string) error {
+	for _, rc := range idx.required {
+		if !slices.Contains(cols, rc) && !slices.ContainsFunc(idx.aliases[rc], func(a string) bool { return slices.Contains(cols, a) }) {
+			return fmt.Errorf("%w %q for %s.%s", ErrMissingColumn, rc, rt, fieldNameByPath(rt, idx.byName[rc]))
+		}
+	}
+	return nil
 }
 
 type fieldIndex struct {
 	byName map[string][]int // lower-case column name -> index path
+	dotted int              // entries in byName that are dotted aliases of nested structs
+	// columns lists the column names (not folded) of the fields in
+	// declaration order, without nested structs and their dotted aliases.
+	columns []string
+	// required lists the columns of fields tagged ,required, in field order.
+	required []string
+	// aliases maps the column of a field tagged `db:"a|b|c"` to its
+	// alternative names, folded ("a" -> ["b", "c"]).
+	aliases map[string][]string
+	// ambiguous lists columns claimed by two fields at the same depth: the
+	// field that won and the first one that lost.
+	ambiguous []ambiguity
+	// aliasConflicts lists alternative names (`db:"a|b"`) that another
+	// field's name or alternative also claims, at any depth: the field that
+	// holds the column and the other one. Field names win over alternatives.
+	aliasConflicts []ambiguity
+	// extra lists, per column, the fields that lost it to the one in byName,
+	// in declaration order; DuplicatePositional assigns repeats to them.
+	extra map[string][][]int
+	// blocks lists the top-level fields tagged ,block, in declaration order.
+	// They are left out of byName and filled positionally (see blockSteps).
+	blocks []int
+}
+
+type ambiguity struct {
+	col         string
+	kept, other []int
 }
 
 func (m *Mapper) structIndex(rt reflect.Type) *fieldIndex {
 	if v, ok := m.structIndexCache.Load(rt); ok {
 		return v.(*fieldIndex)
 	}
-	fi := buildStructIndex(rt)
-	m.structIndexCache.Store(rt, &fi)
+	fi := m.buildStructIndex(rt)
+	if v, loaded := m.structIndexCache.LoadOrStore(rt, &fi); loaded {
+		return v.(*fieldIndex)
+	}
+	m.stats.indexes.Add(1)
 	return &fi
 }
 
+// mappedColumnCount reports how many result columns rt consumes: the number of
+// distinct column names for structs, not counting alternative names, or 1 for
+// other types.
+func (m *Mapper) mappedColumnCount(rt reflect.Type) int {
+	if !m.isRowStruct(rt) {
+		return 1
+	}
+	idx := m.structIndex(rt)
+	n := len(idx.byName) - idx.dotted
+	for _, alts := range idx.aliases {
+		n -= len(alts)
+	}
+	return n
+}
+
 // --------------- Dest allocation per scan ---------------
 
+// destPtrs returns scan destinations for the columns of p into rv (a *rt)
+// and a cleanup func that completes the row: it runs indirect conversions and
+// then any AfterScan hooks. BeforeScan, if implemented, runs first.
 func (p *plan) destPtrs(rv reflect.Value) ([]any, func() error, error) {
+	if p.before != nil {
+		if err := p.beforeScan(rv.Elem()); err != nil {
+			return nil, nil, err
+		}
+	}
+	dests, cleanup, err := p.scanDests(rv)
+	if err != nil || len(p.hooks) == 0 {
+		return dests, cleanup, err
+	}
+	return dests, func() error {
+		if err := cleanup(); err != nil {
+			return err
+		}
+		return p.afterScan(rv.Elem())
+	}, nil
+}
+
+func (p *plan) scanDests(rv reflect.Value) ([]any, func() error, error) {
 	// Whole-type Scanner case
 	if !p.isStruct && p.steps[0].kind == stepWhole {
 		return []any{rv.Interface()}, func() error { return nil }, nil
@@ -182,7 +519,10 @@ func (p *plan) destPtrs(rv reflect.Value) ([]any, func() error, error) {
 		case stepIndirect:
 			tmp := reflect.New(st.convTo).Elem()
 			return []any{tmp.Addr().Interface()}, func() error {
-				return st.post(rv.Elem(), tmp)
+				if err := st.post(rv.Elem(), tmp); err != nil {
+					return &columnError{p: p, idx: 0, err: err}
+				}
+				return nil
 			}, nil
 		default:
 			var sink sql.RawBytes
@@ -192,56 +532,96 @@ func (p *plan) destPtrs(rv reflect.Value) ([]any, func() error, error) {
 
 	// Struct mapping
 	root := rv.Elem()
-	steps := p.steps
-	dests := make([]any, len(steps))
-	finals := make([]func() error, 0, 4)
-
-	var sink sql.RawBytes // reused for all unmapped columns
-	for i := 0; i < len(steps); i++ {
-		st := steps[i]
+	var target any // rv as an interface, for the field accessor
+	if p.field != nil {
+		target = rv.Interface()
+	}
+	f := p.frames.Get().(*scanFrame)
+	for i, st := range p.steps {
 		switch st.kind {
-		case stepDrop:
-			dests[i] = &sink
 		case stepDirect:
-			fv := fieldByPathAlloc(root, st.fpath)
-			dests[i] = fv.Addr().Interface()
+			if target != nil && len(st.fpath) == 1 {
+				if d := p.field(target, st.fpath[0]); d != nil {
+					f.dests[i] = d
+					continue
+				}
+			}
+			f.dests[i] = fieldByPathAlloc(root, st.fpath).Addr().Interface()
 		case stepIndirect:
-			tmp := reflect.New(st.convTo).Elem()
-			fp := append([]int(nil), st.fpath...) // small copy
-			post := st.post
-			dests[i] = tmp.Addr().Interface()
-			finals = append(finals, func() error {
-				dst := fieldByPathAlloc(root, fp)
-				return post(dst, tmp)
-			})
-		default:
-			dests[i] = &sink
+			f.temps[i].SetZero()
 		}
 	}
-
-	cleanup := func() error {
-		for _, f := range finals {
-			if err := f(); err != nil {
-				return err
+	return f.dests, func() error {
+		defer f.release(p)
+		for i, st := range p.steps {
+			if st.kind != stepIndirect {
+				continue
+			}
+			if err := st.post(fieldByPathAlloc(root, st.fpath), f.temps[i]); err != nil {
+				return &columnError{p: p, idx: i, err: err}
 			}
 		}
 		return nil
+	}, nil
+}
+
+// scanFrame holds the scan destinations of one row of a struct plan. Its
+// shape depends only on the plan, so frames are pooled per plan (plan.frames)
+// and only the field addresses are filled in for each row.
+type scanFrame struct {
+	dests []any           // one per column; temps and the sink are set once
+	temps []reflect.Value // per column: the temporary of an indirect step
+	sink  sql.RawBytes    // shared by all unmapped columns
+}
+
+// newFrame allocates a frame for p, which must be a struct plan.
+func (p *plan) newFrame() *scanFrame {
+	f := &scanFrame{dests: make([]any, len(p.steps)), temps: make([]reflect.Value, len(p.steps))}
+	for i, st := range p.steps {
+		switch st.kind {
+		case stepIndirect:
+			f.temps[i] = reflect.New(st.convTo).Elem()
+			f.dests[i] = f.temps[i].Addr().Interface()
+		case stepDirect: // the field's address, set per row
+		default:
+			f.dests[i] = &f.sink
+		}
+	}
+	return f
+}
+
+// release returns f to p's pool, dropping its references to the row.
+func (f *scanFrame) release(p *plan) {
+	for i, st := range p.steps {
+		if st.kind == stepDirect {
+			f.dests[i] = nil
+		}
 	}
-	return dests, cleanup, nil
+	f.sink = nil
+	p.frames.Put(f)
 }
 
 // ---------------- Struct indexing & tags ----------------
 
-func buildStructIndex(rt reflect.Type) fieldIndex {
+func (m *Mapper) buildStructIndex(rt reflect.Type) fieldIndex {
 	idx := fieldIndex{byName: make(map[string][]int)}
 	seen := make(map[string]struct{})
+	aliasOwner := make(map[string]string) // alternative name -> column of its field
 
-	var walk func(t reflect.Type, base []int, forceInline bool)
-	walk = func(t reflect.Type, base []int, forceInline bool) {
+	// prefix is the concatenation of the names on the ,inline tags walked so
+	// far (e.g. `db:"billing_,inline"`); it is prepended to every column name.
+	// Nested, non-inline struct fields are also walked with their name and a
+	// dot as prefix, so "org.name" reaches Outer.Org.Name; dotted is set below
+	// such a field, and active guards against recursive types.
+	active := make(map[reflect.Type]bool)
+	var walk func(t reflect.Type, base []int, forceInline bool, prefix string, dotted bool)
+	walk = func(t reflect.Type, base []int, forceInline bool, prefix string, dotted bool) {
 		t = derefPtr(t)
-		if t.Kind() != reflect.Struct {
+		if t.Kind() != reflect.Struct || active[t] {
 			return
 		}
+		active[t] = true
+		defer delete(active, t)
 		n := t.NumField()
 		for i := 0; i < n; i++ {
 			sf := t.Field(i)
@@ -250,35 +630,138 @@ func buildStructIndex(rt reflect.Type) fieldIndex {
 			}
 			tag := sf.Tag.Get("db")
 			name, inline, omit := parseTag(tag)
-			if omit {
+			if omit || hasTagOption(tag, "writeonly") { // writeonly: never scanned
 				continue
 			}
+			name, alts := splitAliases(name)
 			ft := sf.Type
+			if len(base) == 0 && hasTagOption(tag, "block") {
+				idx.blocks = append(idx.blocks, i)
+				continue
+			}
 			path := make([]int, len(base)+1)
 			copy(path, base)
 			path[len(base)] = i
 
 			if inline || (sf.Anonymous && (forceInline || tag == "")) {
 				if isStruct(ft) || (ft.Kind() == reflect.Ptr && isStruct(ft.Elem())) {
-					walk(ft, path, inline)
+					sub := prefix
+					if inline {
+						sub += name
+					}
+					walk(ft, path, inline, sub, dotted)
 					continue
 				}
 			}
+			// Embedded non-struct types (type ID int64) map like named
+			// fields, under their tag or type name, unless unexported.
+			if sf.PkgPath != "" {
+				continue
+			}
 			if name == "" {
 				name = sf.Name
+				if m.nameMapper != nil {
+					name = m.nameMapper(name)
+				}
 			}
-			lc := toLowerAscii(name)
-			if _, ok := seen[lc]; !ok {
-				idx.byName[lc] = path
-				seen[lc] = struct{}{}
+			lc := m.foldName(prefix + name)
+			nested := m.isRowStruct(ft) && !implementsScanner(ft)
+			for k, alias := range append([]string{name}, alts...) {
+				ac := m.foldName(prefix + alias)
+				if _, ok := seen[ac]; ok {
+					kept := idx.byName[ac]
+					if slices.Equal(kept, path) {
+						continue // the same field under two spellings
+					}
+					if owner, isAlias := aliasOwner[ac]; isAlias || k > 0 {
+						// An alternative name colliding with another field is
+						// always an error (see aliasConflicts). A field's own
+						// name beats another field's alternative one.
+						idx.aliasConflicts = append(idx.aliasConflicts, ambiguity{col: ac, kept: kept, other: path})
+						if k > 0 || dotted {
+							continue
+						}
+						delete(aliasOwner, ac)
+						idx.aliases[owner] = slices.DeleteFunc(idx.aliases[owner], func(a string) bool { return a == ac })
+						delete(seen, ac)
+					}
+				}
+				if _, ok := seen[ac]; ok {
+					kept := idx.byName[ac]
+					if !dotted && len(kept) == len(path) {
+						idx.ambiguous = append(idx.ambiguous, ambiguity{col: ac, kept: kept, other: path})
+					}
+					if !dotted {
+						if idx.extra == nil {
+							idx.extra = make(map[string][][]int)
+						}
+						idx.extra[ac] = append(idx.extra[ac], path)
+					}
+					continue
+				}
+				idx.byName[ac] = path
+				seen[ac] = struct{}{}
+				switch {
+				case dotted:
+					idx.dotted++
+				case k > 0:
+					if idx.aliases == nil {
+						idx.aliases = make(map[string][]string)
+					}
+					idx.aliases[lc] = append(idx.aliases[lc], ac)
+					aliasOwner[ac] = lc
+				default:
+					if !nested {
+						idx.columns = append(idx.columns, prefix+name)
+					}
+					if hasTagOption(tag, "required") {
+						idx.required = append(idx.required, lc)
+					}
+				}
+			}
+			if nested {
+				walk(ft, path, false, prefix+name+".", true)
 			}
 		}
 	}
-	walk(rt, nil, false)
+	walk(rt, nil, false, "", false)
 	return idx
 }
 
+// tagOptions are the flags recognized after the column name in a db tag.
+var tagOptions = map[string]bool{
+	"inline": true, "seconds": true, "millis": true, "required": true, "zeronull": true, "block": true,
+	"readonly": true, "writeonly": true, "out": true, "inout": true,
+}
+
+// hasTagOption reports whether the comma-separated db tag contains opt.
+func hasTagOption(tag, opt string) bool {
+	for tag != "" {
+		var part string
+		part, tag, _ = strings.Cut(tag, ",")
+		if part == opt {
+			return true
+		}
+	}
+	return false
+}
+
+// tagValue returns the value of a key=value option in a db tag, such as
+// "proto" for `db:"payload,codec=proto"`.
+func tagValue(tag, key string) (string, bool) {
+	for tag != "" {
+		var part string
+		part, tag, _ = strings.Cut(tag, ",")
+		if k, v, ok := strings.Cut(part, "="); ok && k == key {
+			return v, true
+		}
+	}
+	return "", false
+}
+
 // parseTag supports: "-", "col", ",inline", "col,inline", "inline,col".
+// On an inline field, the name is a prefix for the nested struct's columns.
+// Other options (see tagOptions, and key=value pairs) are never taken for the name.
 func parseTag(tag string) (name string, inline bool, omit bool) {
 	if tag == "-" {
 		return "", false, true
@@ -292,7 +775,7 @@ func parseTag(tag string) (name string, inline bool, omit bool) {
 			part := tag[start:i]
 			if part == "inline" {
 				inline = true
-			} else if part != "" && name == "" {
+			} else if part != "" && name == "" && !tagOptions[part] && !strings.Contains(part, "=") {
 				name = part
 			}
 			start = i + 1
@@ -301,37 +784,179 @@ func parseTag(tag string) (name string, inline bool, omit bool) {
 	return name, inline, false
 }
 
+// splitAliases splits a tag name of the form "primary|alt1|alt2" into the
+// primary column name and its alternatives. An empty primary stands for the
+// field's default name.
+func splitAliases(name string) (string, []string) {
+	if !strings.Contains(name, "|") {
+		return name, nil
+	}
+	parts := strings.Split(name, "|")
+	return parts[0], slices.DeleteFunc(parts[1:], func(s string) bool { return s == "" })
+}
+
 // ---------------- Step construction ----------------
 
-func makeFieldStep(rootType reflect.Type, fpath []int) (step, error) {
+func (m *Mapper) makeFieldStep(rootType reflect.Type, fpath []int) (step, error) {
 	ft := fieldTypeByPath(rootType, fpath)
+	tag := fieldTagByPath(rootType, fpath)
+	st, err := m.fieldStep(ft, tag, fpath)
+	if err != nil {
+		return step{}, err
+	}
+	if def, ok := tagValue(tag, "default"); ok {
+		return withDefault(st, ft, def)
+	}
+	if hasTagOption(tag, "zeronull") || (m.nullToZero && !m.acceptsNull(ft)) {
+		return withZeroNull(st), nil
+	}
+	return st, nil
+}
 
+// fieldStep picks the scan step for a field of type ft with db tag tag.
+func (m *Mapper) fieldStep(ft reflect.Type, tag string, fpath []int) (step, error) {
+	// An explicit codec tag takes precedence over everything else.
+	if name, ok := tagValue(tag, "codec"); ok {
+		convTo, post, err := pickCodec(ft, name)
+		if err != nil {
+			return step{}, err
+		}
+		return step{kind: stepIndirect, fpath: fpath, convTo: convTo, post: post}, nil
+	}
+	// 0) A registered converter overrides every built-in rule.
+	if convTo, post, ok := m.pickConverter(ft); ok {
+		return step{kind: stepIndirect, fpath: fpath, convTo: convTo, post: post}, nil
+	}
+	// 0a) Interface fields scan as their registered or tagged concrete type.
+	if ft.Kind() == reflect.Interface {
+		ct, ok, err := m.concreteFor(ft, tag)
+		if err != nil {
+			return step{}, err
+		}
+		if ok {
+			st, err := m.fieldStep(ct, tag, fpath)
+			if err != nil {
+				return step{}, err
+			}
+			return asInterface(st, ct), nil
+		}
+	}
+	// 0b) Textual timestamps, when the mapper has time layouts.
+	if convTo, post, ok := m.pickTime(ft); ok {
+		return step{kind: stepIndirect, fpath: fpath, convTo: convTo, post: post}, nil
+	}
+	// 0c) Durations, counted in the unit named by the tag.
+	if convTo, post, ok := pickDuration(ft, durationUnit(tag)); ok {
+		return step{kind: stepIndirect, fpath: fpath, convTo: convTo, post: post}, nil
+	}
+	// 0d) sql.Null[T] and Option[T] convert their value with the rules for T.
+	if vt, ok := nullValueType(ft); ok {
+		inner, err := m.fieldStep(vt, tag, fpath)
+		if err != nil {
+			return step{}, err
+		}
+		return pickNull(ft, inner), nil
+	}
 	// 1) Field provides its own Scanner.
 	if implementsScanner(ft) {
 		return step{kind: stepDirect, fpath: fpath}, nil
 	}
+	// 1a) Named scalars that decode (and validate) themselves from text.
+	if isTextScalar(ft) {
+		if convTo, post, ok := pickUnmarshaler(ft); ok {
+			return step{kind: stepIndirect, fpath: fpath, convTo: convTo, post: post}, nil
+		}
+	}
+	// 1b) Booleans from integers, bits, and words.
+	if convTo, post, ok := pickBool(ft); ok {
+		return step{kind: stepIndirect, fpath: fpath, convTo: convTo, post: post}, nil
+	}
 	// 2) Prefer known safe indirects (e.g., []byte->string, int64->int32, custom underlying types).
 	if convTo, post, ok := pickIndirect(ft); ok {
-		return step{kind: stepIndirect, fpath: fpath, convTo: convTo, post: post}, nil
+		return nilPointerOnNull(step{kind: stepIndirect, fpath: fpath, convTo: convTo, post: post, builtin: isBuiltinScalar(ft)}, ft), nil
 	}
 	// 3) Otherwise, let database/sql scan directly.
 	if isDirectlyScannable(ft) {
 		return step{kind: stepDirect, fpath: fpath}, nil
 	}
-	// 4) Fallback direct (database/sql may still convert).
+	// 4) Types that decode themselves from text or bytes (uuid.UUID, netip.Addr, …).
+	if convTo, post, ok := pickUnmarshaler(ft); ok {
+		return step{kind: stepIndirect, fpath: fpath, convTo: convTo, post: post}, nil
+	}
+	// 4b) Plain [16]byte UUIDs, binary or text.
+	if convTo, post, ok := pickUUID(ft); ok {
+		return step{kind: stepIndirect, fpath: fpath, convTo: convTo, post: post}, nil
+	}
+	// 4c) Slices from PostgreSQL array text.
+	if convTo, post, ok := pickArray(ft); ok {
+		return step{kind: stepIndirect, fpath: fpath, convTo: convTo, post: post}, nil
+	}
+	// 5) Fallback direct (database/sql may still convert).
 	return step{kind: stepDirect, fpath: fpath}, nil
 }
 
-func makeWholeStep(t reflect.Type) (step, error) {
+func (m *Mapper) makeWholeStep(t reflect.Type) (step, error) {
+	// 0) A registered converter overrides every built-in rule.
+	if convTo, post, ok := m.pickConverter(t); ok {
+		return step{kind: stepIndirect, convTo: convTo, post: post}, nil
+	}
+	// 0a) Interfaces scan as their registered concrete type.
+	if t.Kind() == reflect.Interface {
+		if ct, ok, _ := m.concreteFor(t, ""); ok {
+			st, err := m.makeWholeStep(ct)
+			if err != nil {
+				return step{}, err
+			}
+			return asInterface(st, ct), nil
+		}
+	}
+	// 0b) Textual timestamps, when the mapper has time layouts.
+	if convTo, post, ok := m.pickTime(t); ok {
+		return step{kind: stepIndirect, convTo: convTo, post: post}, nil
+	}
+	// 0c) Durations, in nanoseconds.
+	if convTo, post, ok := pickDuration(t, time.Nanosecond); ok {
+		return step{kind: stepIndirect, convTo: convTo, post: post}, nil
+	}
+	// 0d) Named scalars that decode (and validate) themselves from text.
+	if isTextScalar(t) {
+		if convTo, post, ok := pickUnmarshaler(t); ok {
+			return step{kind: stepIndirect, convTo: convTo, post: post}, nil
+		}
+	}
+	// 0e) Booleans from integers, bits, and words.
+	if convTo, post, ok := pickBool(t); ok {
+		return step{kind: stepIndirect, convTo: convTo, post: post}, nil
+	}
+	// 0f) sql.Null[T] and Option[T] convert their value with the rules for T.
+	if vt, ok := nullValueType(t); ok {
+		inner, err := m.makeWholeStep(vt)
+		if err != nil {
+			return step{}, err
+		}
+		return pickNull(t, inner), nil
+	}
 	// 1) Prefer known safe indirects for primitives and custom underlying types.
 	if convTo, post, ok := pickIndirect(t); ok {
-		return step{kind: stepIndirect, convTo: convTo, post: post}, nil
+		return nilPointerOnNull(step{kind: stepIndirect, convTo: convTo, post: post}, t), nil
 	}
 	// 2) Otherwise, direct.
 	if isDirectlyScannable(t) {
 		return step{kind: stepDirect}, nil
 	}
-	// 3) Fallback direct.
+	// 3) Types that decode themselves from text or bytes.
+	if convTo, post, ok := pickUnmarshaler(t); ok {
+		return step{kind: stepIndirect, convTo: convTo, post: post}, nil
+	}
+	// 3b) Plain [16]byte UUIDs, binary or text.
+	if convTo, post, ok := pickUUID(t); ok {
+		return step{kind: stepIndirect, convTo: convTo, post: post}, nil
+	}
+	// 3c) Slices from PostgreSQL array text.
+	if convTo, post, ok := pickArray(t); ok {
+		return step{kind: stepIndirect, convTo: convTo, post: post}, nil
+	}
+	// 4) Fallback direct.
 	return step{kind: stepDirect}, nil
 }
 
@@ -339,6 +964,49 @@ func makeWholeStep(t reflect.Type) (step, error) {
 
 func isStruct(t reflect.Type) bool { return derefPtr(t).Kind() == reflect.Struct }
 
+// isRowStruct reports whether t is a struct mapped column-by-column, as
+// opposed to a struct value type such as time.Time, sql.NullString, or
+// netip.Addr that fills from a single column natively, via sql.Scanner,
+// encoding.TextUnmarshaler, encoding.BinaryUnmarshaler, or a registered
+// converter.
+func (m *Mapper) isRowStruct(t reflect.Type) bool {
+	if !isStruct(t) || m.hasConverter(t) || derefPtr(t) == reflect.TypeOf(time.Time{}) || implementsScanner(derefPtr(t)) {
+		return false
+	}
+	return !isUnmarshalValue(derefPtr(t))
+}
+
+// isUnmarshalValue reports whether t decodes itself from text or bytes.
+// time.Time is excluded because drivers deliver it natively.
+func isUnmarshalValue(t reflect.Type) bool {
+	if t == reflect.TypeOf(time.Time{}) {
+		return false
+	}
+	pt := reflect.PointerTo(t)
+	return pt.Implements(textUnmarshalerType) || pt.Implements(binaryUnmarshalerType)
+}
+
+// isTextScalar reports whether t, or the type t points to, is a named bool,
+// number, or string type that implements encoding.TextUnmarshaler but not
+// sql.Scanner. Such types (validated enums and the like) must be decoded with
+// UnmarshalText rather than the conversions for their underlying kind.
+func isTextScalar(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.PkgPath() == "" || implementsScanner(t) || !reflect.PointerTo(t).Implements(textUnmarshalerType) {
+		return false
+	}
+	switch t.Kind() {
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
 func derefPtr(t reflect.Type) reflect.Type {
 	for t.Kind() == reflect.Ptr {
 		t = t.Elem()
@@ -463,6 +1131,166 @@ func pickIndirect(dstType reflect.Type) (reflect.Type, func(dst, src reflect.Val
 	return nil, nil, false
 }
 
+// pickBool handles destinations whose underlying type is bool, behind any
+// number of pointers. The column is scanned into an interface value and
+// coerced with parseBool, so MySQL TINYINT(1) and BIT(1), text casts, and
+// native booleans all work. NULL leaves a pointer nil and fails for a value.
+func pickBool(dstType reflect.Type) (reflect.Type, func(dst, src reflect.Value) error, bool) {
+	under, ptrCount := dstType, 0
+	for under.Kind() == reflect.Ptr {
+		under = under.Elem()
+		ptrCount++
+	}
+	if under.Kind() != reflect.Bool {
+		return nil, nil, false
+	}
+	return reflect.TypeOf((*any)(nil)).Elem(), func(dst, src reflect.Value) error {
+		v := src.Interface()
+		if v == nil {
+			if ptrCount == 0 {
+				return fmt.Errorf("xsql: converting NULL to %s is unsupported", dstType)
+			}
+			dst.SetZero()
+			return nil
+		}
+		b, err := parseBool(v)
+		if err != nil {
+			return err
+		}
+		val := reflect.New(under).Elem()
+		val.SetBool(b)
+		return assignWithPointers(dst, val, dstType, ptrCount)
+	}, true
+}
+
+// parseBool accepts bool, integers (zero is false), single 0x00/0x01 bytes,
+// and the words t/f, true/false, y/n, yes/no, on/off, 1/0 in any case.
+func parseBool(v any) (bool, error) {
+	switch x := v.(type) {
+	case bool:
+		return x, nil
+	case int64:
+		return x != 0, nil
+	case []byte:
+		if len(x) == 1 && x[0] <= 1 {
+			return x[0] == 1, nil
+		}
+		return parseBool(string(x))
+	case string:
+		switch strings.ToLower(strings.TrimSpace(x)) {
+		case "t", "true", "y", "yes", "on", "1":
+			return true, nil
+		case "f", "false", "n", "no", "off", "0":
+			return false, nil
+		}
+		return false, fmt.Errorf("xsql: cannot parse %q as bool", x)
+	}
+	return false, fmt.Errorf("xsql: cannot scan %T into bool", v)
+}
+
+var (
+	textUnmarshalerType   = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	binaryUnmarshalerType = reflect.TypeOf((*encoding.BinaryUnmarshaler)(nil)).Elem()
+)
+
+// pickUnmarshaler handles destinations (or pointers to them) implementing
+// encoding.TextUnmarshaler or encoding.BinaryUnmarshaler: the column is
+// scanned as []byte and decoded with UnmarshalText or UnmarshalBinary. When a
+// type implements both, text is tried first and binary is the fallback, so a
+// UUID column works whether it is stored as text or as 16 raw bytes. NULL
+// leaves a value destination zero and a pointer nil.
+func pickUnmarshaler(dstType reflect.Type) (reflect.Type, func(dst, src reflect.Value) error, bool) {
+	base, isPtr := dstType, false
+	if base.Kind() == reflect.Ptr {
+		base, isPtr = base.Elem(), true
+	}
+	if !isUnmarshalValue(base) {
+		return nil, nil, false
+	}
+	pt := reflect.PointerTo(base)
+	text, binary := pt.Implements(textUnmarshalerType), pt.Implements(binaryUnmarshalerType)
+	return reflect.TypeOf([]byte(nil)), func(dst, src reflect.Value) error {
+		if src.IsNil() {
+			dst.SetZero()
+			return nil
+		}
+		v := reflect.New(base)
+		var err error
+		if text {
+			err = v.Interface().(encoding.TextUnmarshaler).UnmarshalText(src.Bytes())
+		}
+		if binary && (!text || err != nil) {
+			v = reflect.New(base)
+			err = v.Interface().(encoding.BinaryUnmarshaler).UnmarshalBinary(src.Bytes())
+		}
+		if err != nil {
+			return fmt.Errorf("xsql: unmarshal %s: %w", base, err)
+		}
+		if isPtr {
+			dst.Set(v)
+		} else {
+			dst.Set(v.Elem())
+		}
+		return nil
+	}, true
+}
+
+// pickTime handles time.Time and *time.Time destinations for mappers
+// configured with time layouts or a time location: the column is scanned into
+// an interface value, and strings or bytes are parsed with the first matching
+// layout. Native time.Time values pass through; NULL leaves the zero time or a
+// nil pointer. With a location, every non-NULL result is converted to it.
+func (m *Mapper) pickTime(dstType reflect.Type) (reflect.Type, func(dst, src reflect.Value) error, bool) {
+	timeType := reflect.TypeOf(time.Time{})
+	if (m.timeLayouts == nil && m.timeLoc == nil) || (dstType != timeType && dstType != reflect.PointerTo(timeType)) {
+		return nil, nil, false
+	}
+	isPtr := dstType.Kind() == reflect.Ptr
+	layouts, loc := m.timeLayouts, m.timeLoc
+	return reflect.TypeOf((*any)(nil)).Elem(), func(dst, src reflect.Value) error {
+		var t time.Time
+		switch v := src.Interface().(type) {
+		case nil:
+			dst.SetZero()
+			return nil
+		case time.Time:
+			t = v
+		case string:
+			pt, err := parseTime(v, layouts)
+			if err != nil {
+				return err
+			}
+			t = pt
+		case []byte:
+			pt, err := parseTime(string(v), layouts)
+			if err != nil {
+				return err
+			}
+			t = pt
+		default:
+			return fmt.Errorf("xsql: cannot scan %T into time.Time", v)
+		}
+		if loc != nil {
+			t = t.In(loc)
+		}
+		if isPtr {
+			dst.Set(reflect.ValueOf(&t))
+		} else {
+			dst.Set(reflect.ValueOf(t))
+		}
+		return nil
+	}, true
+}
+
+func parseTime(s string, layouts []string) (time.Time, error) {
+	for _, l := range layouts {
+		if t, err := time.Parse(l, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("xsql: parse time %q: no layout matched", s)
+}
+
 // assignWithPointers converts 'val' to the destination type 'dt',
 // re-applying 'ptrCount' pointer layers if needed before Convert.
 //
@@ -494,6 +1322,29 @@ func fieldTypeByPath(root reflect.Type, fpath []int) reflect.Type {
 	return t
 }
 
+// fieldNameByPath returns the dotted Go field names along fpath, e.g. "Org.Name".
+func fieldNameByPath(root reflect.Type, fpath []int) string {
+	t := root
+	names := make([]string, len(fpath))
+	for i, fi := range fpath {
+		sf := derefPtr(t).Field(fi)
+		names[i] = sf.Name
+		t = sf.Type
+	}
+	return strings.Join(names, ".")
+}
+
+// fieldTagByPath returns the db tag of the field at fpath.
+func fieldTagByPath(root reflect.Type, fpath []int) string {
+	t := root
+	var sf reflect.StructField
+	for _, i := range fpath {
+		sf = derefPtr(t).Field(i)
+		t = sf.Type
+	}
+	return sf.Tag.Get("db")
+}
+
 // fieldByPathAlloc walks fpath, allocating nil pointers so the final field is addressable.
 func fieldByPathAlloc(root reflect.Value, fpath []int) reflect.Value {
 	v := root
@@ -515,6 +1366,33 @@ func fieldByPathAlloc(root reflect.Value, fpath []int) reflect.Value {
 // ---------------- Column normalization (ASCII fast-path) ----------------
 
 func normalizeColAscii(s string) string {
+	return toLowerAscii(unquoteCol(s))
+}
+
+// normalizeCol normalizes a result column name for matching against m's
+// struct indexes: quotes are stripped, and case is folded unless m is
+// case-sensitive.
+func (m *Mapper) normalizeCol(s string) string {
+	return m.foldName(unquoteCol(s))
+}
+
+// foldName applies m's normalization and case folding to a field or column
+// name.
+func (m *Mapper) foldName(s string) string {
+	if m.normalizeName != nil {
+		s = m.normalizeName(s)
+	}
+	switch {
+	case m.caseSensitive:
+		return s
+	case m.unicodeNames:
+		return strings.ToLower(s)
+	}
+	return toLowerAscii(s)
+}
+
+// unquoteCol strips one level of "…", `…`, or […] quoting from a column name.
+func unquoteCol(s string) string {
 	if l := len(s); l >= 2 {
 		switch s[0] {
 		case '"':
@@ -531,7 +1409,7 @@ func normalizeColAscii(s string) string {
 			}
 		}
 	}
-	return toLowerAscii(s)
+	return s
 }
 
 func toLowerAscii(s string) string {
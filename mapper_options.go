@@ -0,0 +1,199 @@
+package xsql
+
+import "time"
+
+// MapperOption configures a Mapper created with [NewMapper]. Options are fixed
+// for the life of the Mapper because they shape its cached struct indexes.
+type MapperOption func(*Mapper)
+
+// WithNameMapper derives the column name of untagged struct fields from the
+// Go field name with fn, instead of using the field name itself. Fields with a
+// `db` tag keep their tagged name. Matching stays case-insensitive.
+//
+// Example:
+//
+//	m := xsql.NewMapper(xsql.WithNameMapper(xsql.SnakeCase))
+//	// CreatedAt now matches created_at without a tag.
+func WithNameMapper(fn func(string) string) MapperOption {
+	return func(m *Mapper) { m.nameMapper = fn }
+}
+
+// WithCaseSensitive disables ASCII case folding: `db` tags and field names must
+// match result column names byte-for-byte after surrounding quotes are
+// stripped. Use it for schemas with case-distinct quoted identifiers, where
+// folding would map the wrong column.
+//
+// Example:
+//
+//	type Row struct {
+//	    Lower int `db:"value"`
+//	    Upper int `db:"VALUE"`
+//	}
+//	m := xsql.NewMapper(xsql.WithCaseSensitive())
+//	rows, err := xsql.QueryWith[Row](ctx, m, db, `SELECT "value", "VALUE" FROM t`)
+func WithCaseSensitive() MapperOption {
+	return func(m *Mapper) { m.caseSensitive = true }
+}
+
+// WithUnicodeNames matches field and column names with Unicode case folding
+// (strings.ToLower) instead of the default ASCII-only folding, for schemas
+// with non-ASCII identifiers such as "Größe" or "ИМЯ". normalize, if not nil,
+// is applied to both sides first; pass norm.NFC.String from
+// golang.org/x/text/unicode/norm when the driver may return decomposed (NFD)
+// names. Combined with WithCaseSensitive, only normalize applies.
+//
+// Example:
+//
+//	m := xsql.NewMapper(xsql.WithUnicodeNames(norm.NFC.String))
+//	rows, err := xsql.QueryWith[Artikel](ctx, m, db, `SELECT "Größe" FROM artikel`)
+func WithUnicodeNames(normalize func(string) string) MapperOption {
+	return func(m *Mapper) {
+		m.unicodeNames = true
+		m.normalizeName = normalize
+	}
+}
+
+// WithAmbiguityCheck makes planning fail with [ErrAmbiguousColumn] when two
+// fields at the same embedding depth map to the same column, typically the
+// same field name in two embedded structs. By default the first field in
+// declaration order silently wins. A field shadowing one from a more deeply
+// embedded struct, as Go itself allows, is not reported.
+//
+// Example:
+//
+//	type Row struct {
+//	    Audit        // has UpdatedAt `db:"updated_at"`
+//	    Sync         // has UpdatedAt `db:"updated_at"` too
+//	}
+//	m := xsql.NewMapper(xsql.WithAmbiguityCheck())
+//	_, err := xsql.QueryWith[Row](ctx, m, db, `SELECT * FROM t`)
+//	// err: xsql: ambiguous column "updated_at" in main.Row: Audit.UpdatedAt and Sync.UpdatedAt
+func WithAmbiguityCheck() MapperOption {
+	return func(m *Mapper) { m.checkAmbiguous = true }
+}
+
+// WithNullToZero scans NULL into the zero value of any destination that
+// cannot hold NULL itself, instead of failing the row with "converting NULL to
+// int64 is unsupported". Pointers, slices, maps, interfaces, sql.Scanner
+// implementations (sql.NullString, Option, …), and types with a registered
+// converter still see the NULL. It applies to
+// struct fields and to non-struct T alike; a `default=` tag still wins.
+//
+// Example:
+//
+//	m := xsql.NewMapper(xsql.WithNullToZero())
+//	users, err := xsql.QueryWith[User](ctx, m, db, `SELECT id, nickname FROM users`)
+//	// a NULL nickname leaves User.Nickname == ""
+func WithNullToZero() MapperOption {
+	return func(m *Mapper) { m.nullToZero = true }
+}
+
+// WithTimeLocation converts every scanned time.Time to loc, typically
+// time.UTC, so values compare and format the same whatever location the
+// driver attached. It applies to time.Time and *time.Time fields, to
+// sql.Null[time.Time] and Option[time.Time], and to time.Time as T; the
+// instant is unchanged and NULL still yields the zero time or a nil pointer.
+// Values from WithTimeLayouts are parsed first and then converted.
+//
+// Example:
+//
+//	m := xsql.NewMapper(xsql.WithTimeLocation(time.UTC))
+//	events, err := xsql.QueryWith[Event](ctx, m, db, `SELECT id, created_at FROM events`)
+func WithTimeLocation(loc *time.Location) MapperOption {
+	return func(m *Mapper) { m.timeLoc = loc }
+}
+
+// DuplicatePolicy selects how a struct plan assigns a column name that occurs
+// more than once in a result, as `id` does in `SELECT u.*, o.* FROM users u
+// JOIN orgs o …`. See [WithDuplicateColumns].
+type DuplicatePolicy int
+
+const (
+	// DuplicateLastWins scans every occurrence into the same field, so the
+	// last one wins. It is the default.
+	DuplicateLastWins DuplicatePolicy = iota
+	// DuplicateFirstWins scans the first occurrence and drops the others.
+	DuplicateFirstWins
+	// DuplicateError fails planning with ErrDuplicateColumn.
+	DuplicateError
+	// DuplicatePositional assigns the nth occurrence to the nth field that
+	// claims the name, in declaration order (typically the same column of two
+	// embedded structs); occurrences beyond the last such field are dropped.
+	DuplicatePositional
+)
+
+// WithDuplicateColumns sets how repeated result column names are mapped onto
+// struct fields. Plans are built once per column set, so an error is reported
+// when the query is first planned, before any row is scanned.
+//
+// Example:
+//
+//	type Row struct {
+//	    User // ID `db:"id"`, Name `db:"name"`
+//	    Org  // ID `db:"id"`, Plan `db:"plan"`
+//	}
+//	m := xsql.NewMapper(xsql.WithDuplicateColumns(xsql.DuplicatePositional))
+//	rows, err := xsql.QueryWith[Row](ctx, m, db,
+//	    `SELECT u.id, u.name, o.id, o.plan FROM users u JOIN orgs o ON o.id = u.org_id`)
+func WithDuplicateColumns(policy DuplicatePolicy) MapperOption {
+	return func(m *Mapper) { m.duplicates = policy }
+}
+
+// DefaultTimeLayouts are the layouts [WithTimeLayouts] uses when called
+// without arguments. They cover RFC 3339 and the formats SQLite and MySQL use
+// for DATETIME and TIMESTAMP text, with or without fractional seconds and zone.
+var DefaultTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999-07",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// WithTimeLayouts lets time.Time and *time.Time fields scan from string and
+// []byte columns, as SQLite and some MySQL configurations deliver timestamps.
+// Text is parsed with the first layout that matches (see [time.Parse]);
+// without arguments, [DefaultTimeLayouts] are used. Native time.Time values
+// are still accepted as is.
+//
+// Example:
+//
+//	m := xsql.NewMapper(xsql.WithTimeLayouts())
+//	events, err := xsql.QueryWith[Event](ctx, m, sqliteDB, `SELECT id, created_at FROM events`)
+func WithTimeLayouts(layouts ...string) MapperOption {
+	if len(layouts) == 0 {
+		layouts = DefaultTimeLayouts
+	}
+	layouts = append([]string(nil), layouts...)
+	return func(m *Mapper) { m.timeLayouts = layouts }
+}
+
+// SnakeCase converts a Go identifier to snake_case, keeping initialisms
+// together: "CreatedAt" → "created_at", "UserID" → "user_id",
+// "HTTPServer" → "http_server". Digits stay attached to the preceding word
+// ("Address2" → "address2"). It is meant for use with [WithNameMapper].
+func SnakeCase(s string) string {
+	b := make([]byte, 0, len(s)+4)
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if isUpperAscii(c) {
+			if i > 0 && s[i-1] != '_' {
+				prev := s[i-1]
+				nextLower := i+1 < len(s) && isLowerAscii(s[i+1])
+				if isLowerAscii(prev) || isDigitAscii(prev) || (isUpperAscii(prev) && nextLower) {
+					b = append(b, '_')
+				}
+			}
+			c += 'a' - 'A'
+		}
+		b = append(b, c)
+	}
+	return string(b)
+}
+
+func isUpperAscii(c byte) bool { return 'A' <= c && c <= 'Z' }
+func isLowerAscii(c byte) bool { return 'a' <= c && c <= 'z' }
+func isDigitAscii(c byte) bool { return '0' <= c && c <= '9' }
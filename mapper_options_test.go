@@ -0,0 +1,360 @@
+package xsql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSnakeCase(t *testing.T) {
+	cases := map[string]string{
+		"ID":         "id",
+		"Name":       "name",
+		"CreatedAt":  "created_at",
+		"UserID":     "user_id",
+		"HTTPServer": "http_server",
+		"Address2":   "address2",
+		"Line2Text":  "line2_text",
+		"already_ok": "already_ok",
+		"Snake_Case": "snake_case",
+		"OAuthToken": "o_auth_token",
+	}
+	for in, want := range cases {
+		if got := SnakeCase(in); got != want {
+			t.Errorf("SnakeCase(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestWithNameMapper_MatchesUntaggedFields(t *testing.T) {
+	type Row struct {
+		UserID    int64
+		CreatedAt time.Time
+		Email     string `db:"mail"`
+	}
+	at := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	db := newTestDB(t, func(string, []driver.NamedValue) ([]string, [][]driver.Value, error) {
+		return []string{"user_id", "CREATED_AT", "mail"}, [][]driver.Value{{int64(7), at, "a@x"}}, nil
+	})
+	defer func() { _ = db.Close() }()
+
+	m := NewMapper(WithNameMapper(SnakeCase))
+	got, err := GetWith[Row](context.Background(), m, db, "q")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.UserID != 7 || !got.CreatedAt.Equal(at) || got.Email != "a@x" {
+		t.Fatalf("unexpected row: %+v", got)
+	}
+
+	// The default mapper still matches by field name only.
+	got, err = Get[Row](context.Background(), db, "q")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.UserID != 0 || !got.CreatedAt.IsZero() || got.Email != "a@x" {
+		t.Fatalf("default mapper should not snake_case: %+v", got)
+	}
+}
+
+func TestWithCaseSensitive(t *testing.T) {
+	type Row struct {
+		Lower int64 `db:"value"`
+		Upper int64 `db:"VALUE"`
+		Name  string
+	}
+	db := newTestDB(t, func(string, []driver.NamedValue) ([]string, [][]driver.Value, error) {
+		return []string{`"value"`, `"VALUE"`, "name"}, [][]driver.Value{{int64(1), int64(2), "n"}}, nil
+	})
+	defer func() { _ = db.Close() }()
+
+	m := NewMapper(WithCaseSensitive())
+	got, err := GetWith[Row](context.Background(), m, db, "q")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Lower != 1 || got.Upper != 2 || got.Name != "" {
+		t.Fatalf("case-sensitive mapping: %+v", got)
+	}
+
+	// Default folding maps both columns onto the first field named "value".
+	got, err = Get[Row](context.Background(), db, "q")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Lower != 2 || got.Upper != 0 || got.Name != "n" {
+		t.Fatalf("case-folding mapping: %+v", got)
+	}
+}
+
+func TestWithTimeLayouts(t *testing.T) {
+	type Row struct {
+		At   time.Time  `db:"at"`
+		Seen *time.Time `db:"seen"`
+	}
+	native := time.Date(2024, 3, 4, 5, 6, 7, 0, time.UTC)
+	db := newTestDB(t, func(q string, _ []driver.NamedValue) ([]string, [][]driver.Value, error) {
+		switch q {
+		case "bad":
+			return []string{"at"}, [][]driver.Value{{"yesterday"}}, nil
+		case "custom":
+			return []string{"at"}, [][]driver.Value{{"04/03/2024"}}, nil
+		}
+		return []string{"at", "seen"}, [][]driver.Value{
+			{"2024-03-04 05:06:07", nil},
+			{[]byte("2024-03-04T05:06:07.5+02:00"), "2024-03-04"},
+			{native, native},
+		}, nil
+	})
+	defer func() { _ = db.Close() }()
+	ctx := context.Background()
+
+	if _, err := Query[Row](ctx, db, "q"); err == nil {
+		t.Fatalf("default mapper should not parse text timestamps")
+	}
+
+	m := NewMapper(WithTimeLayouts())
+	got, err := QueryWith[Row](ctx, m, db, "q")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got[0].At.Equal(native) || got[0].Seen != nil {
+		t.Fatalf("row 0: %+v", got[0])
+	}
+	want1 := time.Date(2024, 3, 4, 5, 6, 7, 5e8, time.FixedZone("", 2*3600))
+	if !got[1].At.Equal(want1) || got[1].Seen == nil || !got[1].Seen.Equal(time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("row 1: %+v", got[1])
+	}
+	if !got[2].At.Equal(native) || !got[2].Seen.Equal(native) {
+		t.Fatalf("row 2: %+v", got[2])
+	}
+
+	if _, err := GetWith[time.Time](ctx, m, db, "bad"); err == nil {
+		t.Fatalf("expected parse error")
+	}
+	custom := NewMapper(WithTimeLayouts("02/01/2006"))
+	if at, err := GetWith[time.Time](ctx, custom, db, "custom"); err != nil || !at.Equal(time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("custom layout: %v, %v", at, err)
+	}
+}
+
+func TestWithAmbiguityCheck(t *testing.T) {
+	type Audit struct {
+		UpdatedAt int64 `db:"updated_at"`
+		ID        int64 `db:"id"`
+	}
+	type Sync struct {
+		UpdatedAt int64 `db:"updated_at"`
+	}
+	type Row struct {
+		ID int64 `db:"id"` // shadows Audit.ID, as Go does
+		Audit
+		Sync
+	}
+	type Clean struct {
+		ID int64 `db:"id"`
+		Audit
+	}
+	db := newTestDB(t, func(q string, _ []driver.NamedValue) ([]string, [][]driver.Value, error) {
+		return []string{"id", "updated_at"}, [][]driver.Value{{int64(1), int64(2)}}, nil
+	})
+	defer func() { _ = db.Close() }()
+	ctx := context.Background()
+
+	// Lenient by default: the first field wins.
+	got, err := Get[Row](ctx, db, "q")
+	if err != nil || got.Audit.UpdatedAt != 2 || got.Sync.UpdatedAt != 0 {
+		t.Fatalf("Get = %+v, %v", got, err)
+	}
+
+	m := NewMapper(WithAmbiguityCheck())
+	_, err = GetWith[Row](ctx, m, db, "q")
+	if !errors.Is(err, ErrAmbiguousColumn) || !strings.Contains(err.Error(), "Audit.UpdatedAt and Sync.UpdatedAt") {
+		t.Fatalf("want ErrAmbiguousColumn naming both fields, got %v", err)
+	}
+	if c, err := GetWith[Clean](ctx, m, db, "q"); err != nil || c.ID != 1 || c.Audit.ID != 0 {
+		t.Fatalf("shadowing should be allowed: %+v, %v", c, err)
+	}
+}
+
+func TestWithNullToZero(t *testing.T) {
+	type Row struct {
+		ID    int64          `db:"id"`
+		Name  string         `db:"name"`
+		Ok    bool           `db:"ok"`
+		Nick  *string        `db:"nick"`
+		Note  sql.NullString `db:"note"`
+		Level int32          `db:"level,default=5"`
+	}
+	db := newTestDB(t, func(q string, _ []driver.NamedValue) ([]string, [][]driver.Value, error) {
+		if q == "one" {
+			return []string{"n"}, [][]driver.Value{{nil}}, nil
+		}
+		return []string{"id", "name", "ok", "nick", "note", "level"}, [][]driver.Value{{nil, nil, nil, nil, nil, nil}}, nil
+	})
+	defer func() { _ = db.Close() }()
+	ctx := context.Background()
+
+	if _, err := Get[Row](ctx, db, "q"); err == nil {
+		t.Fatalf("default mapper should reject NULL into int64")
+	}
+	m := NewMapper(WithNullToZero())
+	got, err := GetWith[Row](ctx, m, db, "q")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != (Row{Level: 5}) {
+		t.Fatalf("got %+v", got)
+	}
+	n, err := GetWith[int](ctx, m, db, "one")
+	if err != nil || n != 0 {
+		t.Fatalf("GetWith[int] = %d, %v", n, err)
+	}
+	// Pointers stay nil, with or without the option.
+	if p, err := Get[*int64](ctx, db, "one"); err != nil || p != nil {
+		t.Fatalf("Get[*int64] = %v, %v", p, err)
+	}
+}
+
+func TestWithTimeLocation(t *testing.T) {
+	type Row struct {
+		At   time.Time           `db:"at"`
+		Seen *time.Time          `db:"seen"`
+		Done Option[time.Time]   `db:"done"`
+		Due  sql.Null[time.Time] `db:"due"`
+	}
+	est := time.FixedZone("EST", -5*3600)
+	local := time.Date(2024, 3, 4, 5, 6, 7, 0, est)
+	db := newTestDB(t, func(q string, _ []driver.NamedValue) ([]string, [][]driver.Value, error) {
+		if q == "one" {
+			return []string{"at"}, [][]driver.Value{{local}}, nil
+		}
+		return []string{"at", "seen", "done", "due"}, [][]driver.Value{
+			{local, local, local, local},
+			{local, nil, nil, nil},
+		}, nil
+	})
+	defer func() { _ = db.Close() }()
+	ctx := context.Background()
+
+	m := NewMapper(WithTimeLocation(time.UTC))
+	got, err := QueryWith[Row](ctx, m, db, "q")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := got[0]
+	done, _ := r.Done.Get()
+	for _, v := range []time.Time{r.At, *r.Seen, done, r.Due.V} {
+		if v.Location() != time.UTC || !v.Equal(local) {
+			t.Fatalf("not converted to UTC: %v", v)
+		}
+	}
+	if got[1].Seen != nil || got[1].Done.IsSome() || got[1].Due.Valid {
+		t.Fatalf("NULLs should stay empty: %+v", got[1])
+	}
+	if v, err := GetWith[time.Time](ctx, m, db, "one"); err != nil || v.Location() != time.UTC {
+		t.Fatalf("GetWith[time.Time] = %v, %v", v, err)
+	}
+
+	tokyo := time.FixedZone("JST", 9*3600)
+	layouts := NewMapper(WithTimeLayouts(), WithTimeLocation(tokyo))
+	if v, err := GetWith[time.Time](ctx, layouts, newTestDB(t, func(string, []driver.NamedValue) ([]string, [][]driver.Value, error) {
+		return []string{"at"}, [][]driver.Value{{"2024-03-04 05:06:07"}}, nil
+	}), "q"); err != nil || v.Location() != tokyo || v.Hour() != 14 {
+		t.Fatalf("parsed time = %v, %v", v, err)
+	}
+}
+
+func TestWithUnicodeNames(t *testing.T) {
+	type Row struct {
+		Change string `db:"änderung"`
+		Name   string `db:"имя"`
+	}
+	cols := []string{"ÄNDERUNG", `"ИМЯ"`}
+	db := newTestDB(t, func(q string, _ []driver.NamedValue) ([]string, [][]driver.Value, error) {
+		return cols, [][]driver.Value{{"x", "y"}}, nil
+	})
+	defer func() { _ = db.Close() }()
+	ctx := context.Background()
+
+	strict := NewMapper()
+	strict.Strict = true
+	if _, err := GetWith[Row](ctx, strict, db, "q"); !errors.Is(err, ErrUnmappedColumn) {
+		t.Fatalf("ASCII folding should not match, got %v", err)
+	}
+	m := NewMapper(WithUnicodeNames(nil))
+	m.Strict = true
+	if got, err := GetWith[Row](ctx, m, db, "q"); err != nil || got != (Row{"x", "y"}) {
+		t.Fatalf("GetWith = %+v, %v", got, err)
+	}
+
+	// Decomposed names (A + combining diaeresis) match once normalized.
+	cols = []string{"A\u0308nderung", "имя"}
+	nfc := strings.NewReplacer("A\u0308", "Ä", "a\u0308", "ä").Replace
+	if _, err := GetWith[Row](ctx, m, db, "q"); !errors.Is(err, ErrUnmappedColumn) {
+		t.Fatalf("NFD name should not match without normalization, got %v", err)
+	}
+	m = NewMapper(WithUnicodeNames(nfc))
+	m.Strict = true
+	if got, err := GetWith[Row](ctx, m, db, "q"); err != nil || got != (Row{"x", "y"}) {
+		t.Fatalf("normalized GetWith = %+v, %v", got, err)
+	}
+}
+
+func TestWithDuplicateColumns(t *testing.T) {
+	type User struct {
+		ID   int64  `db:"id"`
+		Name string `db:"name"`
+	}
+	type Org struct {
+		ID   int64  `db:"id"`
+		Plan string `db:"plan"`
+	}
+	type Row struct {
+		User
+		Org
+	}
+	db := newTestDB(t, func(q string, _ []driver.NamedValue) ([]string, [][]driver.Value, error) {
+		if q == "three" {
+			return []string{"id", "id", "id"}, [][]driver.Value{{int64(1), int64(2), int64(3)}}, nil
+		}
+		return []string{"id", "name", "id", "plan"}, [][]driver.Value{{int64(1), "ann", int64(2), "pro"}}, nil
+	})
+	defer func() { _ = db.Close() }()
+	ctx := context.Background()
+
+	cases := []struct {
+		policy        DuplicatePolicy
+		userID, orgID int64
+	}{
+		{DuplicateLastWins, 2, 0},
+		{DuplicateFirstWins, 1, 0},
+		{DuplicatePositional, 1, 2},
+	}
+	for _, c := range cases {
+		m := NewMapper(WithDuplicateColumns(c.policy))
+		m.Strict = true
+		got, err := GetWith[Row](ctx, m, db, "q")
+		if err != nil {
+			t.Fatalf("policy %d: %v", c.policy, err)
+		}
+		if got.User.ID != c.userID || got.Org.ID != c.orgID || got.Name != "ann" || got.Plan != "pro" {
+			t.Fatalf("policy %d: %+v", c.policy, got)
+		}
+	}
+
+	m := NewMapper(WithDuplicateColumns(DuplicatePositional))
+	if got, err := GetWith[Row](ctx, m, db, "three"); err != nil || got.User.ID != 1 || got.Org.ID != 2 {
+		t.Fatalf("extra occurrence: %+v, %v", got, err)
+	}
+
+	m = NewMapper(WithDuplicateColumns(DuplicateError))
+	_, err := GetWith[Row](ctx, m, db, "q")
+	if !errors.Is(err, ErrDuplicateColumn) || !strings.Contains(err.Error(), `"id" at positions 1 and 3`) {
+		t.Fatalf("expected duplicate column error, got %v", err)
+	}
+}
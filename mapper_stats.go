@@ -0,0 +1,90 @@
+package xsql
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// MapperStats is a snapshot of the caches of a [Mapper], as returned by
+// [Mapper.Stats]. The counters are cumulative since the mapper was created;
+// the sizes are current.
+type MapperStats struct {
+	PlanHits      uint64 // plan lookups served from the cache
+	PlanMisses    uint64 // plan lookups that had to build a plan
+	PlanEvictions uint64 // plans dropped because a converter was registered since
+	Plans         int    // plans cached, one per destination type and column set
+	StructIndexes int    // struct types indexed
+}
+
+// mapperCounters backs [Mapper.Stats].
+type mapperCounters struct {
+	hits, misses, evictions atomic.Uint64
+	plans, indexes          atomic.Int64
+
+	evictMu  sync.Mutex
+	evictGen [2]uint64 // converter generations (global, mapper) last evicted for
+}
+
+// Stats returns a snapshot of m's cache counters, for export to metrics. A
+// steady stream of misses usually means queries build their column lists at
+// run time, so every call plans anew.
+//
+// Example:
+//
+//	s := xsql.DefaultMapper().Stats()
+//	planHits.Set(float64(s.PlanHits))
+//	planMisses.Set(float64(s.PlanMisses))
+func (m *Mapper) Stats() MapperStats {
+	return MapperStats{
+		PlanHits:      m.stats.hits.Load(),
+		PlanMisses:    m.stats.misses.Load(),
+		PlanEvictions: m.stats.evictions.Load(),
+		Plans:         int(m.stats.plans.Load()),
+		StructIndexes: int(m.stats.indexes.Load()),
+	}
+}
+
+// storePlan caches p under key unless another goroutine got there first, and
+// returns the plan to use. If key already holds a plan for other columns
+// whose hash collides with p's, p is returned uncached.
+func (m *Mapper) storePlan(key planKey, p *plan) *plan {
+	if v, loaded := m.planCache.LoadOrStore(key, p); loaded {
+		if v.(*plan).matches(p.cols) {
+			return v.(*plan)
+		}
+		return p
+	}
+	m.stats.plans.Add(1)
+	m.evictStale(key, p)
+	return p
+}
+
+// evictStale drops the cached plans built against converter registries older
+// than the generations in key. Such plans can no longer be looked up, since
+// the generations are part of the key. If key is itself older than the
+// generations evicted for already, p, just cached under it by a lookup that
+// raced with a registration, is dropped instead.
+func (m *Mapper) evictStale(key planKey, p *plan) {
+	m.stats.evictMu.Lock()
+	defer m.stats.evictMu.Unlock()
+	last := &m.stats.evictGen
+	if key.gen < last[0] || key.mgen < last[1] {
+		if m.planCache.CompareAndDelete(key, p) {
+			m.stats.plans.Add(-1)
+			m.stats.evictions.Add(1)
+		}
+		return
+	}
+	if key.gen == last[0] && key.mgen == last[1] {
+		return
+	}
+	last[0], last[1] = key.gen, key.mgen
+	m.planCache.Range(func(k, _ any) bool {
+		if pk := k.(planKey); pk.gen < last[0] || pk.mgen < last[1] {
+			m.planCache.Delete(k)
+			m.stats.plans.Add(-1)
+			m.stats.evictions.Add(1)
+		}
+		return true
+	})
+}
@@ -0,0 +1,69 @@
+package xsql
+
+import (
+	"context"
+	"database/sql/driver"
+	"reflect"
+	"testing"
+)
+
+func TestMapperStats(t *testing.T) {
+	type Row struct {
+		ID   int64  `db:"id"`
+		Name string `db:"name"`
+	}
+	cols := []string{"id", "name"}
+	db := newTestDB(t, func(q string, _ []driver.NamedValue) ([]string, [][]driver.Value, error) {
+		return cols, [][]driver.Value{{int64(1), "a"}, {int64(2), "b"}}, nil
+	})
+	defer func() { _ = db.Close() }()
+
+	ctx := context.Background()
+	m := NewMapper()
+	for range 3 {
+		if _, err := QueryWith[Row](ctx, m, db, "q"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want := MapperStats{PlanHits: 2, PlanMisses: 1, Plans: 1, StructIndexes: 1}
+	if got := m.Stats(); got != want {
+		t.Fatalf("stats = %+v, want %+v", got, want)
+	}
+
+	cols = []string{"id"}
+	if _, err := QueryWith[*Row](ctx, m, db, "q"); err != nil {
+		t.Fatal(err)
+	}
+	// *Row misses, and so does the plan for Row it is derived from.
+	want = MapperStats{PlanHits: 2, PlanMisses: 3, Plans: 3, StructIndexes: 1}
+	if got := m.Stats(); got != want {
+		t.Fatalf("stats = %+v, want %+v", got, want)
+	}
+
+	// A converter registration makes the cached plans unreachable; the next
+	// plan built evicts them.
+	m.RegisterConverter(reflect.TypeOf(""), func(src any) (any, error) { return "x", nil })
+	got, err := QueryWith[Row](ctx, m, db, "q")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got[0].ID != 1 {
+		t.Fatalf("rows = %+v", got)
+	}
+	want = MapperStats{PlanHits: 2, PlanMisses: 4, PlanEvictions: 3, Plans: 1, StructIndexes: 1}
+	if got := m.Stats(); got != want {
+		t.Fatalf("stats = %+v, want %+v", got, want)
+	}
+
+	// A plan built against the old registry by a lookup that raced with the
+	// registration is dropped as soon as it is stored.
+	key := planKey{rt: reflect.TypeOf(Row{}), ncols: 1, gen: m.stats.evictGen[0], mgen: m.stats.evictGen[1] - 1}
+	m.storePlan(key, &plan{cols: []string{"id"}})
+	if _, ok := m.planCache.Load(key); ok {
+		t.Fatal("stale plan stayed cached")
+	}
+	want = MapperStats{PlanHits: 2, PlanMisses: 4, PlanEvictions: 4, Plans: 1, StructIndexes: 1}
+	if got := m.Stats(); got != want {
+		t.Fatalf("stats = %+v, want %+v", got, want)
+	}
+}
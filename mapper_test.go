@@ -4,10 +4,14 @@ import (
 	"context"
 	"database/sql"
 	"database/sql/driver"
+	"encoding/binary"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"hash/fnv"
+	"net/netip"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 )
@@ -67,6 +71,8 @@ func TestParseTag(t *testing.T) {
 		{",inline", "", true, false},
 		{"col,inline", "col", true, false},
 		{"inline,col", "col", true, false},
+		{"ttl,seconds", "ttl", false, false},
+		{",millis", "", false, false},
 	}
 	for _, tc := range tests {
 		name, inline, omit := parseTag(tc.tag)
@@ -95,7 +101,7 @@ func TestBuildStructIndex_InlineAndAnonymous(t *testing.T) {
 	// Touch the unexported field so linters consider it used.
 	_ = Outer{unexp: 1}
 
-	fi := buildStructIndex(reflect.TypeOf(Outer{}))
+	fi := NewMapper().buildStructIndex(reflect.TypeOf(Outer{}))
 	if _, ok := fi.byName["id"]; !ok {
 		t.Fatal("id missing")
 	}
@@ -142,6 +148,31 @@ func TestStructIndexCacheAndPlanCacheReuse(t *testing.T) {
 	}
 }
 
+func TestPlanCache_HashCollision(t *testing.T) {
+	type S struct {
+		A int `db:"a"`
+		B int `db:"b"`
+	}
+	m := NewMapper()
+	rt := reflect.TypeOf(S{})
+
+	// Pretend both column orders hash alike.
+	p1, err := m.getPlan(rt, []string{"a", "b"}, 42)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p2, err := m.getPlan(rt, []string{"b", "a"}, 42)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p1 == p2 || p2.steps[0].fpath[0] != 1 || p2.steps[1].fpath[0] != 0 {
+		t.Fatalf("colliding column set reused the cached plan: %+v", p2.steps)
+	}
+	if p3, _ := m.getPlan(rt, []string{"a", "b"}, 42); p3 != p1 {
+		t.Fatal("collision displaced the cached plan")
+	}
+}
+
 /* ---------------------------
    isStruct / deref / Scanner / direct
 ----------------------------*/
@@ -449,6 +480,94 @@ func TestScan_Struct_PointerInline_Alloc(t *testing.T) {
 	}
 }
 
+func TestScan_Struct_InlinePrefix(t *testing.T) {
+	type Address struct {
+		Street string `db:"street"`
+		City   string
+	}
+	type Geo struct {
+		Lat float64 `db:"lat"`
+	}
+	type Shipping struct {
+		Address `db:"addr_,inline"`
+		Geo     Geo `db:"geo_,inline"`
+	}
+	type Order struct {
+		ID       int64    `db:"id"`
+		Billing  Address  `db:"billing_,inline"`
+		Shipping Shipping `db:"shipping_,inline"`
+	}
+	fi := NewMapper().buildStructIndex(reflect.TypeOf(Order{}))
+	for _, c := range []string{"id", "billing_street", "billing_city", "shipping_addr_street", "shipping_addr_city", "shipping_geo_lat"} {
+		if _, ok := fi.byName[c]; !ok {
+			t.Fatalf("%s missing from index: %v", c, fi.byName)
+		}
+	}
+	if _, ok := fi.byName["street"]; ok {
+		t.Fatalf("unprefixed street should not be indexed")
+	}
+
+	cols := []string{"id", "billing_street", "Billing_City", "shipping_addr_street", "shipping_geo_lat"}
+	vals := [][]driver.Value{{int64(1), "b st", "b city", "s st", 1.5}}
+	db := newTestDB(t, func(q string, _ []driver.NamedValue) ([]string, [][]driver.Value, error) {
+		return cols, vals, nil
+	})
+	defer func() { _ = db.Close() }()
+
+	rows, _ := db.QueryContext(context.Background(), "q")
+	got := nextAndScan[Order](t, NewMapper(), rows)
+	if got.Billing.Street != "b st" || got.Billing.City != "b city" ||
+		got.Shipping.Street != "s st" || got.Shipping.Geo.Lat != 1.5 {
+		t.Fatalf("unexpected order: %+v", got)
+	}
+}
+
+func TestScan_Struct_DottedNested(t *testing.T) {
+	type Country struct {
+		Code string `db:"code"`
+	}
+	type Org struct {
+		Name    string  `db:"name"`
+		Country Country `db:"country"`
+	}
+	type Node struct {
+		ID     int64 `db:"id"`
+		Parent *Node `db:"parent"`
+	}
+	type User struct {
+		ID    int64 `db:"id"`
+		Org   Org   `db:"org"`
+		Boss  *Org
+		Where Node `db:"node"`
+	}
+	m := NewMapper()
+	fi := m.buildStructIndex(reflect.TypeOf(User{}))
+	for _, c := range []string{"org.name", "org.country.code", "boss.name", "node.id", "node.parent"} {
+		if _, ok := fi.byName[c]; !ok {
+			t.Fatalf("%s missing from index: %v", c, fi.byName)
+		}
+	}
+	if _, ok := fi.byName["node.parent.id"]; ok {
+		t.Fatalf("recursive type should not be expanded")
+	}
+	if n := m.mappedColumnCount(reflect.TypeOf(User{})); n != 4 {
+		t.Fatalf("mappedColumnCount = %d, want 4", n)
+	}
+
+	cols := []string{"id", "org.name", "ORG.COUNTRY.CODE", "boss.name"}
+	vals := [][]driver.Value{{int64(7), "acme", "NZ", "bob inc"}}
+	db := newTestDB(t, func(q string, _ []driver.NamedValue) ([]string, [][]driver.Value, error) {
+		return cols, vals, nil
+	})
+	defer func() { _ = db.Close() }()
+
+	rows, _ := db.QueryContext(context.Background(), "q")
+	got := nextAndScan[User](t, m, rows)
+	if got.ID != 7 || got.Org.Name != "acme" || got.Org.Country.Code != "NZ" || got.Boss == nil || got.Boss.Name != "bob inc" {
+		t.Fatalf("unexpected user: %+v", got)
+	}
+}
+
 func TestScan_Primitive_OneColumn(t *testing.T) {
 	cols := []string{"n"}
 	vals := [][]driver.Value{{int64(42)}}
@@ -698,11 +817,11 @@ func TestDestPtrs_NonStructPrimitive_Indirect_Path(t *testing.T) {
 }
 
 func TestPlan_Struct_StepKinds_Inspection(t *testing.T) {
-	// Ensure a struct plan contains drop/direct/indirect as expected.
+	// Ensure a struct plan contains drop/indirect steps as expected.
 	type Row struct {
 		Name string `db:"name"` // []byte -> string → stepIndirect (builtin string path is []byte->string)
 		Age  int32  `db:"age"`  // int64 -> int32 → stepIndirect
-		OK   bool   `db:"ok"`   // direct
+		OK   bool   `db:"ok"`   // any -> bool coercion → stepIndirect
 	}
 	m := NewMapper()
 	cols := []string{"name", "age", "ok", "unmapped"} // last forces stepDrop
@@ -724,10 +843,484 @@ func TestPlan_Struct_StepKinds_Inspection(t *testing.T) {
 	if pl.steps[1].kind != stepIndirect {
 		t.Fatalf("age should be stepIndirect, got %v", pl.steps[1].kind)
 	}
-	if pl.steps[2].kind != stepDirect {
-		t.Fatalf("ok should be stepDirect, got %v", pl.steps[2].kind)
+	if pl.steps[2].kind != stepIndirect {
+		t.Fatalf("ok should be stepIndirect, got %v", pl.steps[2].kind)
 	}
 	if pl.steps[3].kind != stepDrop {
 		t.Fatalf("unmapped should be stepDrop, got %v", pl.steps[3].kind)
 	}
 }
+
+func TestSetDefaultMapper(t *testing.T) {
+	prev := getMapper()
+	t.Cleanup(func() { SetDefaultMapper(prev) })
+
+	m := NewMapper()
+	SetDefaultMapper(m)
+	if getMapper() != m {
+		t.Fatalf("getMapper should return the installed mapper")
+	}
+
+	type Row struct {
+		ID int64 `db:"id"`
+	}
+	db := newTestDB(t, func(string, []driver.NamedValue) ([]string, [][]driver.Value, error) {
+		return []string{"id", "extra"}, [][]driver.Value{{int64(1), int64(2)}}, nil
+	})
+	defer func() { _ = db.Close() }()
+
+	m.Strict = true
+	if _, err := Get[Row](context.Background(), db, "q"); !errors.Is(err, ErrUnmappedColumn) {
+		t.Fatalf("Get should use the installed strict mapper, got %v", err)
+	}
+
+	SetDefaultMapper(nil)
+	if got := getMapper(); got == nil || got == m {
+		t.Fatalf("nil should install a fresh default mapper")
+	}
+}
+
+func TestScan_TextUnmarshaler(t *testing.T) {
+	type Row struct {
+		Addr  netip.Addr  `db:"addr"`
+		Peer  *netip.Addr `db:"peer"`
+		Stamp time.Time   `db:"stamp"`
+	}
+	at := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	vals := [][]driver.Value{
+		{[]byte("10.0.0.1"), "::1", at},
+		{"192.168.1.1", nil, at},
+	}
+	db := newTestDB(t, func(q string, _ []driver.NamedValue) ([]string, [][]driver.Value, error) {
+		if q == "bad" {
+			return []string{"addr"}, [][]driver.Value{{"not-an-ip"}}, nil
+		}
+		if q == "whole" {
+			return []string{"addr"}, [][]driver.Value{{"10.0.0.2"}, {nil}}, nil
+		}
+		return []string{"addr", "peer", "stamp"}, vals, nil
+	})
+	defer func() { _ = db.Close() }()
+	ctx := context.Background()
+
+	got, err := Query[Row](ctx, db, "rows")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got[0].Addr != netip.MustParseAddr("10.0.0.1") || got[0].Peer == nil || *got[0].Peer != netip.MustParseAddr("::1") {
+		t.Fatalf("row 0: %+v", got[0])
+	}
+	if got[1].Addr != netip.MustParseAddr("192.168.1.1") || got[1].Peer != nil || !got[1].Stamp.Equal(at) {
+		t.Fatalf("row 1: %+v", got[1])
+	}
+
+	addrs, err := Query[netip.Addr](ctx, db, "whole")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(addrs) != 2 || addrs[0] != netip.MustParseAddr("10.0.0.2") || addrs[1].IsValid() {
+		t.Fatalf("whole: %v", addrs)
+	}
+
+	if _, err := Get[Row](ctx, db, "bad"); err == nil || !strings.Contains(err.Error(), "netip.Addr") {
+		t.Fatalf("expected unmarshal error naming the type, got %v", err)
+	}
+}
+
+// textColor is a validated enum stored as text.
+type textColor string
+
+func (c *textColor) UnmarshalText(b []byte) error {
+	switch string(b) {
+	case "red", "green":
+		*c = textColor(b)
+		return nil
+	}
+	return fmt.Errorf("unknown color %q", b)
+}
+
+func TestScan_TextUnmarshalerScalar(t *testing.T) {
+	type Row struct {
+		Color textColor  `db:"color"`
+		Prev  *textColor `db:"prev"`
+	}
+	db := newTestDB(t, func(q string, _ []driver.NamedValue) ([]string, [][]driver.Value, error) {
+		switch q {
+		case "bad":
+			return []string{"color", "prev"}, [][]driver.Value{{"purple", nil}}, nil
+		case "whole":
+			return []string{"color"}, [][]driver.Value{{"purple"}}, nil
+		}
+		return []string{"color", "prev"}, [][]driver.Value{{"red", []byte("green")}, {"green", nil}}, nil
+	})
+	defer func() { _ = db.Close() }()
+	ctx := context.Background()
+
+	got, err := Query[Row](ctx, db, "rows")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got[0].Color != "red" || got[0].Prev == nil || *got[0].Prev != "green" || got[1].Color != "green" || got[1].Prev != nil {
+		t.Fatalf("rows: %+v", got)
+	}
+	if _, err := Query[Row](ctx, db, "bad"); err == nil || !strings.Contains(err.Error(), "unknown color") {
+		t.Fatalf("struct field: expected unmarshal error, got %v", err)
+	}
+	if _, err := Query[textColor](ctx, db, "whole"); err == nil || !strings.Contains(err.Error(), "unknown color") {
+		t.Fatalf("whole value: expected unmarshal error, got %v", err)
+	}
+}
+
+// packedPoint is stored as an 8-byte big-endian blob.
+type packedPoint struct{ X, Y int32 }
+
+func (p *packedPoint) UnmarshalBinary(b []byte) error {
+	if len(b) != 8 {
+		return fmt.Errorf("packedPoint: want 8 bytes, got %d", len(b))
+	}
+	p.X = int32(binary.BigEndian.Uint32(b[:4]))
+	p.Y = int32(binary.BigEndian.Uint32(b[4:]))
+	return nil
+}
+
+func TestScan_BinaryUnmarshaler(t *testing.T) {
+	type Row struct {
+		Pos  packedPoint  `db:"pos"`
+		Prev *packedPoint `db:"prev"`
+		IP   netip.Addr   `db:"ip"`
+	}
+	db := newTestDB(t, func(q string, _ []driver.NamedValue) ([]string, [][]driver.Value, error) {
+		if q == "short" {
+			return []string{"pos"}, [][]driver.Value{{[]byte{1}}}, nil
+		}
+		return []string{"pos", "prev", "ip"}, [][]driver.Value{
+			{[]byte{0, 0, 0, 1, 0, 0, 0, 2}, nil, []byte{10, 0, 0, 9}}, // IP as 4 raw bytes
+			{[]byte{0, 0, 0, 3, 0, 0, 0, 4}, []byte{0, 0, 0, 5, 0, 0, 0, 6}, "10.0.0.1"},
+		}, nil
+	})
+	defer func() { _ = db.Close() }()
+	ctx := context.Background()
+
+	got, err := Query[Row](ctx, db, "q")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got[0].Pos != (packedPoint{1, 2}) || got[0].Prev != nil || got[0].IP != netip.MustParseAddr("10.0.0.9") {
+		t.Fatalf("row 0: %+v", got[0])
+	}
+	if got[1].Prev == nil || *got[1].Prev != (packedPoint{5, 6}) || got[1].IP != netip.MustParseAddr("10.0.0.1") {
+		t.Fatalf("row 1: %+v", got[1])
+	}
+
+	if _, err := Get[packedPoint](ctx, db, "short"); err == nil || !strings.Contains(err.Error(), "want 8 bytes") {
+		t.Fatalf("expected UnmarshalBinary error, got %v", err)
+	}
+}
+
+func TestScan_FlexibleBool(t *testing.T) {
+	type Flag bool
+	type Row struct {
+		A bool  `db:"a"`
+		B *bool `db:"b"`
+		C Flag  `db:"c"`
+	}
+	db := newTestDB(t, func(q string, _ []driver.NamedValue) ([]string, [][]driver.Value, error) {
+		switch q {
+		case "bad":
+			return []string{"a", "b", "c"}, [][]driver.Value{{"maybe", nil, true}}, nil
+		case "null":
+			return []string{"a", "b", "c"}, [][]driver.Value{{nil, nil, true}}, nil
+		case "one":
+			return []string{"ok"}, [][]driver.Value{{[]byte("on")}}, nil
+		}
+		return []string{"a", "b", "c"}, [][]driver.Value{
+			{int64(1), "t", []byte{0x01}},
+			{int64(0), []byte("No"), "yes"},
+			{true, nil, []byte{0x00}},
+			{"FALSE", int64(2), "1"},
+		}, nil
+	})
+	defer func() { _ = db.Close() }()
+	ctx := context.Background()
+
+	got, err := Query[Row](ctx, db, "q")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []struct {
+		a     bool
+		b     bool
+		c     Flag
+		bNull bool
+	}{
+		{true, true, true, false},
+		{false, false, true, false},
+		{true, false, false, true},
+		{false, true, true, false},
+	}
+	for i, w := range want {
+		r := got[i]
+		if r.A != w.a || r.C != w.c || (r.B == nil) != w.bNull || (r.B != nil && *r.B != w.b) {
+			t.Fatalf("row %d: %+v", i, r)
+		}
+	}
+
+	if _, err := Query[Row](ctx, db, "bad"); err == nil || !strings.Contains(err.Error(), `"maybe"`) {
+		t.Fatalf("expected parse error, got %v", err)
+	}
+	if _, err := Query[Row](ctx, db, "null"); err == nil {
+		t.Fatalf("NULL into bool should fail")
+	}
+	if b, err := Get[bool](ctx, db, "one"); err != nil || !b {
+		t.Fatalf("Get[bool] = %v, %v", b, err)
+	}
+}
+
+// MySQL delivers BIT(1) as a single byte and some drivers return TINYINT(1)
+// casts as "0"/"1" text; both must fill the same struct as a Postgres boolean.
+func TestScan_MySQLBitBool(t *testing.T) {
+	type Row struct {
+		Active  bool           `db:"active"`
+		Deleted *bool          `db:"deleted"`
+		Locked  sql.Null[bool] `db:"locked"`
+		Flags   []bool         `db:"flags"`
+		Hidden  Option[bool]   `db:"hidden"`
+	}
+	db := newTestDB(t, func(q string, _ []driver.NamedValue) ([]string, [][]driver.Value, error) {
+		return []string{"active", "deleted", "locked", "flags", "hidden"}, [][]driver.Value{
+			{[]byte{0x01}, []byte{0x00}, []byte{0x01}, "{1,0}", "0"},
+			{"0", "1", nil, nil, []byte("1")},
+			{true, false, true, "{t,f}", false},
+		}, nil
+	})
+	defer func() { _ = db.Close() }()
+
+	got, err := Query[Row](context.Background(), db, "q")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, r := range got {
+		want := i != 1
+		if r.Active != want || r.Deleted == nil || *r.Deleted != !want || r.Hidden != Some(!want) {
+			t.Fatalf("row %d: %+v", i, r)
+		}
+		if r.Locked.Valid != want || r.Locked.V != want {
+			t.Fatalf("row %d: locked %+v", i, r.Locked)
+		}
+		if want && (len(r.Flags) != 2 || !r.Flags[0] || r.Flags[1]) {
+			t.Fatalf("row %d: flags %v", i, r.Flags)
+		}
+	}
+}
+
+func TestRequiredTag(t *testing.T) {
+	type Base struct {
+		TenantID int64 `db:"tenant_id,required"`
+	}
+	type Row struct {
+		Base
+		ID   int64  `db:"id,required"`
+		Name string `db:"name"`
+	}
+	db := newTestDB(t, func(q string, _ []driver.NamedValue) ([]string, [][]driver.Value, error) {
+		switch q {
+		case "no_tenant":
+			return []string{"id", "name"}, [][]driver.Value{{int64(1), "a"}}, nil
+		case "no_name":
+			return []string{"ID", "tenant_id"}, [][]driver.Value{{int64(1), int64(9)}}, nil
+		}
+		return nil, nil, nil
+	})
+	defer func() { _ = db.Close() }()
+	ctx := context.Background()
+
+	_, err := Get[Row](ctx, db, "no_tenant")
+	if !errors.Is(err, ErrMissingColumn) || !strings.Contains(err.Error(), `"tenant_id"`) || !strings.Contains(err.Error(), "Base.TenantID") {
+		t.Fatalf("want ErrMissingColumn for tenant_id, got %v", err)
+	}
+	// Optional fields may be missing.
+	got, err := Get[*Row](ctx, db, "no_name")
+	if err != nil || got.ID != 1 || got.TenantID != 9 {
+		t.Fatalf("Get = %+v, %v", got, err)
+	}
+}
+
+type EmbeddedID int64
+
+type embeddedCode string
+
+func TestEmbeddedNonStructTypes(t *testing.T) {
+	type Row struct {
+		EmbeddedID
+		*embeddedCode `db:"code"` // unexported: cannot be set, so ignored
+		Name          string      `db:"name"`
+	}
+	fi := NewMapper().buildStructIndex(reflect.TypeOf(Row{}))
+	if _, ok := fi.byName["embeddedid"]; !ok {
+		t.Fatalf("embedded EmbeddedID missing: %v", fi.byName)
+	}
+	if _, ok := fi.byName["code"]; ok {
+		t.Fatalf("unexported embedded type should be skipped")
+	}
+	if fi := NewMapper(WithNameMapper(SnakeCase)).buildStructIndex(reflect.TypeOf(Row{})); fi.byName["embedded_id"] == nil {
+		t.Fatalf("name mapper should apply to embedded type names: %v", fi.byName)
+	}
+
+	db := newTestDB(t, func(q string, _ []driver.NamedValue) ([]string, [][]driver.Value, error) {
+		return []string{"EmbeddedID", "code", "name"}, [][]driver.Value{{int64(9), "x", "n"}}, nil
+	})
+	defer func() { _ = db.Close() }()
+	got, err := Get[Row](context.Background(), db, "q")
+	if err != nil || got.EmbeddedID != 9 || got.Name != "n" {
+		t.Fatalf("Get = %+v, %v", got, err)
+	}
+
+	_, args, err := Rebind(`SELECT :embeddedid, :name`, PlaceholderQuestion, Row{EmbeddedID: 4, Name: "a"})
+	if err != nil || len(args) != 2 || args[0] != EmbeddedID(4) {
+		t.Fatalf("Rebind args = %#v, %v", args, err)
+	}
+}
+
+func TestTagAliases(t *testing.T) {
+	type User struct {
+		ID   int64  `db:"id|user_id"`
+		Name string `db:"name|full_name,required"`
+	}
+	db := newTestDB(t, func(q string, _ []driver.NamedValue) ([]string, [][]driver.Value, error) {
+		switch q {
+		case "view":
+			return []string{"user_id", "full_name"}, [][]driver.Value{{int64(2), "bob"}}, nil
+		case "missing":
+			return []string{"user_id"}, [][]driver.Value{{int64(3)}}, nil
+		case "pair":
+			return []string{"id", "name", "user_id", "full_name"}, [][]driver.Value{{int64(1), "ann", int64(2), "bob"}}, nil
+		}
+		return []string{"id", "name"}, [][]driver.Value{{int64(1), "ann"}}, nil
+	})
+	defer func() { _ = db.Close() }()
+	ctx := context.Background()
+
+	m := NewMapper()
+	m.Strict = true
+	if got, err := GetWith[User](ctx, m, db, "base"); err != nil || got != (User{1, "ann"}) {
+		t.Fatalf("base table: %+v, %v", got, err)
+	}
+	if got, err := GetWith[User](ctx, m, db, "view"); err != nil || got != (User{2, "bob"}) {
+		t.Fatalf("view: %+v, %v", got, err)
+	}
+	if _, err := Get[User](ctx, db, "missing"); !errors.Is(err, ErrMissingColumn) {
+		t.Fatalf("expected missing column error, got %v", err)
+	}
+	if got := ColumnsOf[User](); !reflect.DeepEqual(got, []string{"id", "name"}) {
+		t.Fatalf("ColumnsOf = %v", got)
+	}
+	// Aliases do not widen the column block Query2 gives to User.
+	pairs, err := Query2[User, User](ctx, db, "pair")
+	if err != nil || pairs[0].First != (User{1, "ann"}) || pairs[0].Second != (User{2, "bob"}) {
+		t.Fatalf("Query2 = %+v, %v", pairs, err)
+	}
+
+	_, args, err := Rebind(`SELECT :id, :user_id, :full_name`, PlaceholderDollar, User{ID: 7, Name: "x"})
+	if err != nil || !reflect.DeepEqual(args, []any{int64(7), int64(7), "x"}) {
+		t.Fatalf("Rebind args = %v, %v", args, err)
+	}
+
+	// An alternative name colliding with another field fails the plan, with or
+	// without the ambiguity check, and the field's own name keeps the column.
+	type Clash struct {
+		ID     int64 `db:"id|user_id"`
+		UserID int64 `db:"user_id"`
+	}
+	for _, cm := range []*Mapper{NewMapper(), NewMapper(WithAmbiguityCheck())} {
+		if _, err := QueryWith[Clash](ctx, cm, db, "view"); !errors.Is(err, ErrAmbiguousColumn) {
+			t.Fatalf("expected alias collision to be reported, got %v", err)
+		}
+		idx := cm.structIndex(reflect.TypeOf(Clash{}))
+		if !reflect.DeepEqual(idx.byName["user_id"], []int{1}) {
+			t.Fatalf("user_id maps to %v, want UserID", idx.byName["user_id"])
+		}
+		if n := cm.mappedColumnCount(reflect.TypeOf(Clash{})); n != 2 {
+			t.Fatalf("mappedColumnCount = %d, want 2", n)
+		}
+	}
+	type Base struct {
+		UserID int64 `db:"user_id"`
+	}
+	type Deep struct {
+		Base
+		ID int64 `db:"id|user_id"`
+	}
+	if _, err := Query[Deep](ctx, db, "view"); !errors.Is(err, ErrAmbiguousColumn) {
+		t.Fatalf("expected collision with an embedded field to be reported, got %v", err)
+	}
+}
+
+func TestReadonlyWriteonlyTags(t *testing.T) {
+	type Account struct {
+		ID        int64  `db:"id"`
+		Email     string `db:"email"`
+		CreatedAt string `db:"created_at,readonly"`
+		Password  string `db:"password_hash,writeonly"`
+	}
+	db := newTestDB(t, func(q string, _ []driver.NamedValue) ([]string, [][]driver.Value, error) {
+		return []string{"id", "email", "created_at", "password_hash"},
+			[][]driver.Value{{int64(1), "a@x", "2024-01-02", "secret"}}, nil
+	})
+	defer func() { _ = db.Close() }()
+
+	got, err := Get[Account](context.Background(), db, "q")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != (Account{ID: 1, Email: "a@x", CreatedAt: "2024-01-02"}) {
+		t.Fatalf("writeonly field scanned: %+v", got)
+	}
+	if cols := ColumnsOf[Account](); !reflect.DeepEqual(cols, []string{"id", "email", "created_at"}) {
+		t.Fatalf("ColumnsOf = %v", cols)
+	}
+
+	a := Account{ID: 1, Email: "a@x", CreatedAt: "now", Password: "h"}
+	_, args, err := Rebind(`UPDATE accounts SET email = :email, password_hash = :password_hash WHERE id = :id`, PlaceholderDollar, a)
+	if err != nil || !reflect.DeepEqual(args, []any{"a@x", "h", int64(1)}) {
+		t.Fatalf("Rebind = %v, %v", args, err)
+	}
+	if _, _, err := Rebind(`INSERT INTO accounts (created_at) VALUES (:created_at)`, PlaceholderDollar, a); err == nil {
+		t.Fatal("readonly field was bound")
+	}
+}
+
+func TestScan_ReusedFrames(t *testing.T) {
+	type Row struct {
+		ID    int32    `db:"id"`
+		Score *int32   `db:"score"`
+		Name  string   `db:"name"`
+		Tags  []string `db:"tags"`
+	}
+	db := newTestDB(t, func(q string, _ []driver.NamedValue) ([]string, [][]driver.Value, error) {
+		return []string{"id", "score", "name", "tags", "extra"}, [][]driver.Value{
+			{int64(1), int64(10), []byte("ann"), "{a,b}", "x"},
+			{int64(2), nil, []byte("bob"), nil, "y"},
+			{int64(3), int64(30), []byte("cy"), "{c}", nil},
+		}, nil
+	})
+	defer func() { _ = db.Close() }()
+
+	for range 2 { // the second pass reuses pooled frames
+		got, err := Query[Row](context.Background(), db, "q")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != 3 || got[0].ID != 1 || *got[0].Score != 10 || got[0].Name != "ann" || strings.Join(got[0].Tags, ",") != "a,b" {
+			t.Fatalf("row 0: %+v", got)
+		}
+		if got[1].ID != 2 || got[1].Score != nil || got[1].Name != "bob" || got[1].Tags != nil {
+			t.Fatalf("row 1 kept values of row 0: %+v", got[1])
+		}
+		if got[2].ID != 3 || *got[2].Score != 30 || got[2].Name != "cy" || strings.Join(got[2].Tags, ",") != "c" {
+			t.Fatalf("row 2: %+v", got[2])
+		}
+		if got[0].Score == got[2].Score {
+			t.Fatal("rows share a pointer")
+		}
+	}
+}
@@ -0,0 +1,287 @@
+package xsql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// QueryMaps executes the SQL query and returns every row as a map from column
+// name to value. It is meant for ad-hoc queries where no destination type
+// exists, such as admin tools and report exports.
+//
+// Keys are the result column names normalized the same way the struct mapper
+// normalizes them: surrounding quotes are stripped, and the mapper's name
+// normalizer and case folding apply (ASCII lower-casing by default; none with
+// [WithCaseSensitive]). The mapper is the package-level one, or the one given
+// with [WithMapper]. When two columns normalize to the same key, the later one
+// wins.
+//
+// Values are chosen from the driver's column type information:
+//   - NULL is always nil.
+//   - Integer, unsigned, float, and boolean columns yield int64, uint64,
+//     float64, and bool, parsing textual driver values if needed.
+//   - Time values delivered by the driver are returned as time.Time.
+//   - Binary columns (BLOB, BYTEA, VARBINARY, …) yield []byte.
+//   - Everything else that arrives as bytes (text, numeric/decimal, JSON) yields
+//     string; other driver values are returned as is.
+//
+// Example:
+//
+//	rows, err := xsql.QueryMaps(ctx, db, `SELECT id, email, created_at FROM users`)
+//	for _, r := range rows {
+//	    fmt.Println(r["id"], r["email"])
+//	}
+func QueryMaps(ctx context.Context, q Querier, query string, args ...any) ([]map[string]any, error) {
+	return queryMaps(ctx, q, query, args, false)
+}
+
+// GetMap executes the SQL query and returns the first row as a map from column
+// name to value, using the same key normalization and value types as
+// [QueryMaps]. It returns [sql.ErrNoRows] if the query yields no rows; rows
+// after the first are ignored.
+//
+// Example:
+//
+//	row, err := xsql.GetMap(ctx, db, `SELECT * FROM users WHERE id = $1`, 42)
+//	if errors.Is(err, sql.ErrNoRows) {
+//	    // not found
+//	}
+func GetMap(ctx context.Context, q Querier, query string, args ...any) (map[string]any, error) {
+	rows, err := queryMaps(ctx, q, query, args, true)
+	if err != nil {
+		return nil, err
+	}
+	return rows[0], nil
+}
+
+// queryMaps runs query and scans its rows into maps for QueryMaps and GetMap.
+// With first set it stops after one row, and fails with sql.ErrNoRows if
+// there is none.
+func queryMaps(ctx context.Context, q Querier, query string, args []any, first bool) (out []map[string]any, err error) {
+	args, cfg, err := callArgs(args)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := cfg.context(ctx)
+	defer cancel()
+
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if cerr := rows.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}()
+
+	m, _ := cfg.scanMapper()
+	ms, err := newMapScanner(m, rows)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		row, err := ms.scan(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, row)
+		if first {
+			return out, nil
+		}
+	}
+	if ne := rows.Err(); ne != nil {
+		return nil, ne
+	}
+	if first {
+		return nil, sql.ErrNoRows
+	}
+	return out, nil
+}
+
+// QueryPairs executes a two-column SQL query and returns a map from the first
+// column to the second, for lookups such as `SELECT id, name FROM ...`. Both
+// columns use the usual single-value mapping rules, so K and V may be
+// primitives, named types, or scanner types. It fails unless the result has
+// exactly two columns; for duplicate keys the last row wins. The map is never
+// nil on success. K and V are scanned by the mapper even if they implement
+// [RowScanner], since each receives only one column.
+//
+// Example:
+//
+//	names, err := xsql.QueryPairs[int64, string](ctx, db, `SELECT id, name FROM teams`)
+//	fmt.Println(names[42])
+func QueryPairs[K comparable, V any](ctx context.Context, q Querier, query string, args ...any) (out map[K]V, err error) {
+	args, cfg, err := callArgs(args)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := cfg.context(ctx)
+	defer cancel()
+
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if cerr := rows.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}()
+
+	cols, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	if len(cols) != 2 {
+		return nil, fmt.Errorf("xsql: QueryPairs requires exactly 2 columns; got %d", len(cols))
+	}
+	m, _ := cfg.scanMapper()
+	plK, err := m.planFor(reflect.TypeOf((*K)(nil)).Elem(), cols[:1])
+	if err != nil {
+		return nil, err
+	}
+	plV, err := m.planFor(reflect.TypeOf((*V)(nil)).Elem(), cols[1:])
+	if err != nil {
+		return nil, err
+	}
+
+	out = make(map[K]V)
+	dests := make([]any, 2)
+	spans := []colSpan{{p: plK}, {p: plV, off: 1}}
+	row := 0
+	for rows.Next() {
+		rk, rv := reflect.New(plK.rt), reflect.New(plV.rt)
+		dk, cleanupK, err := plK.destPtrs(rk)
+		if err != nil {
+			return nil, err
+		}
+		dv, cleanupV, err := plV.destPtrs(rv)
+		if err != nil {
+			return nil, err
+		}
+		dests[0], dests[1] = dk[0], dv[0]
+		if err := rows.Scan(dests...); err != nil {
+			return nil, scanError(rows, row, err, spans...)
+		}
+		if err := cleanupK(); err != nil {
+			return nil, scanError(rows, row, err, spans...)
+		}
+		if err := cleanupV(); err != nil {
+			return nil, scanError(rows, row, err, spans...)
+		}
+		row++
+		out[plK.result(rk).(K)] = plV.result(rv).(V)
+	}
+	if ne := rows.Err(); ne != nil {
+		return nil, ne
+	}
+	return out, nil
+}
+
+// mapScanner scans rows of one result set into maps.
+type mapScanner struct {
+	keys  []string
+	convs []func(any) (any, error)
+}
+
+// newMapScanner prepares the scanning of rows into maps keyed by the column
+// names as normalized by m.
+func newMapScanner(m *Mapper, rows *sql.Rows) (*mapScanner, error) {
+	cols, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	types, err := rows.ColumnTypes()
+	if err != nil {
+		return nil, err
+	}
+	ms := &mapScanner{keys: cols, convs: make([]func(any) (any, error), len(cols))}
+	for i := range cols {
+		ms.keys[i] = m.normalizeCol(cols[i])
+		var st reflect.Type
+		var dbType string
+		if i < len(types) && types[i] != nil {
+			st, dbType = types[i].ScanType(), types[i].DatabaseTypeName()
+		}
+		ms.convs[i] = mapValueConverter(st, dbType)
+	}
+	return ms, nil
+}
+
+func (ms *mapScanner) scan(rows *sql.Rows) (map[string]any, error) {
+	vals := make([]any, len(ms.keys))
+	ptrs := make([]any, len(vals))
+	for i := range vals {
+		ptrs[i] = &vals[i]
+	}
+	if err := rows.Scan(ptrs...); err != nil {
+		return nil, err
+	}
+	row := make(map[string]any, len(vals))
+	for i, v := range vals {
+		cv, err := ms.convs[i](v)
+		if err != nil {
+			return nil, fmt.Errorf("xsql: column %q: %w", ms.keys[i], err)
+		}
+		row[ms.keys[i]] = cv
+	}
+	return row, nil
+}
+
+// mapValueConverter picks the conversion applied to values of a column whose
+// driver scan type is st (nil if unknown) and database type name is dbType.
+func mapValueConverter(st reflect.Type, dbType string) func(any) (any, error) {
+	if st != nil && st.Kind() == reflect.Struct && st.NumField() == 2 && st.Field(1).Name == "Valid" {
+		st = st.Field(0).Type // sql.NullInt64 and friends
+	}
+	if st != nil {
+		st = derefPtr(st)
+	}
+	kind := reflect.Invalid
+	if st != nil {
+		kind = st.Kind()
+	}
+	switch {
+	case kind >= reflect.Int && kind <= reflect.Int64:
+		return textConverter(func(s string) (any, error) { return strconv.ParseInt(s, 10, 64) })
+	case kind >= reflect.Uint && kind <= reflect.Uint64:
+		return textConverter(func(s string) (any, error) { return strconv.ParseUint(s, 10, 64) })
+	case kind == reflect.Float32 || kind == reflect.Float64:
+		return textConverter(func(s string) (any, error) { return strconv.ParseFloat(s, 64) })
+	case kind == reflect.Bool:
+		return textConverter(func(s string) (any, error) { return strconv.ParseBool(s) })
+	case isBinaryDBType(dbType) || st == reflect.TypeOf([]byte(nil)):
+		return func(v any) (any, error) { return v, nil }
+	default:
+		return textConverter(func(s string) (any, error) { return s, nil })
+	}
+}
+
+// textConverter returns a converter that passes non-textual values through and
+// parses []byte/string values with parse.
+func textConverter(parse func(string) (any, error)) func(any) (any, error) {
+	return func(v any) (any, error) {
+		switch x := v.(type) {
+		case []byte:
+			return parse(string(x))
+		case string:
+			return parse(x)
+		default:
+			return v, nil
+		}
+	}
+}
+
+func isBinaryDBType(name string) bool {
+	switch strings.ToUpper(name) {
+	case "BLOB", "TINYBLOB", "MEDIUMBLOB", "LONGBLOB", "BYTEA", "BINARY", "VARBINARY",
+		"IMAGE", "RAW", "LONG RAW", "BIT":
+		return true
+	}
+	return false
+}
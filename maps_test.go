@@ -0,0 +1,193 @@
+package xsql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestQueryMaps_NormalizesKeysAndValues(t *testing.T) {
+	ts := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	db := newTestDB(t, func(q string, _ []driver.NamedValue) ([]string, [][]driver.Value, error) {
+		cols := []string{`"ID"`, "Email", "created", "note"}
+		rows := [][]driver.Value{
+			{int64(1), []byte("a@x"), ts, nil},
+			{int64(2), "b@x", ts, []byte("hi")},
+		}
+		return cols, rows, nil
+	})
+	defer func() { _ = db.Close() }()
+
+	got, err := QueryMaps(context.Background(), db, "q")
+	if err != nil {
+		t.Fatalf("QueryMaps: %v", err)
+	}
+	want := []map[string]any{
+		{"id": int64(1), "email": "a@x", "created": ts, "note": nil},
+		{"id": int64(2), "email": "b@x", "created": ts, "note": "hi"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %#v\nwant %#v", got, want)
+	}
+}
+
+func TestQueryMaps_MapperNormalization(t *testing.T) {
+	db := newTestDB(t, func(q string, _ []driver.NamedValue) ([]string, [][]driver.Value, error) {
+		return []string{`"UserID"`, "Name"}, [][]driver.Value{{int64(1), "ann"}}, nil
+	})
+	defer func() { _ = db.Close() }()
+	ctx := context.Background()
+
+	prev := getMapper()
+	t.Cleanup(func() { SetDefaultMapper(prev) })
+	SetDefaultMapper(NewMapper(WithCaseSensitive()))
+	got, err := QueryMaps(ctx, db, "q")
+	if want := []map[string]any{{"UserID": int64(1), "Name": "ann"}}; err != nil || !reflect.DeepEqual(got, want) {
+		t.Fatalf("case-sensitive default mapper: %#v %v", got, err)
+	}
+
+	row, err := GetMap(ctx, db, "q", WithMapper(NewMapper()))
+	if want := map[string]any{"userid": int64(1), "name": "ann"}; err != nil || !reflect.DeepEqual(row, want) {
+		t.Fatalf("WithMapper: %#v %v", row, err)
+	}
+}
+
+func TestQueryMaps_Errors(t *testing.T) {
+	sentinel := errors.New("boom")
+	db := newTestDB(t, func(q string, _ []driver.NamedValue) ([]string, [][]driver.Value, error) {
+		return nil, nil, sentinel
+	})
+	defer func() { _ = db.Close() }()
+	if _, err := QueryMaps(context.Background(), db, "q"); !errors.Is(err, sentinel) {
+		t.Fatalf("want %v, got %v", sentinel, err)
+	}
+
+	db2 := sql.OpenDB(&errNextConnector{})
+	defer func() { _ = db2.Close() }()
+	if _, err := QueryMaps(context.Background(), db2, "q"); err == nil || err.Error() != "driver next error" {
+		t.Fatalf("want driver next error, got %v", err)
+	}
+}
+
+func TestMapValueConverter(t *testing.T) {
+	cases := []struct {
+		st     reflect.Type
+		dbType string
+		in     any
+		want   any
+	}{
+		{reflect.TypeOf(int64(0)), "BIGINT", []byte("42"), int64(42)},
+		{reflect.TypeOf(sql.NullInt64{}), "INT", "7", int64(7)},
+		{reflect.TypeOf(uint32(0)), "INT UNSIGNED", []byte("9"), uint64(9)},
+		{reflect.TypeOf(float64(0)), "DOUBLE", []byte("1.5"), 1.5},
+		{reflect.TypeOf(sql.NullBool{}), "BOOL", []byte("true"), true},
+		{reflect.TypeOf(int64(0)), "BIGINT", int64(3), int64(3)},
+		{reflect.TypeOf(sql.RawBytes{}), "BLOB", []byte{0, 1}, []byte{0, 1}},
+		{reflect.TypeOf([]byte(nil)), "", []byte{2}, []byte{2}},
+		{reflect.TypeOf(sql.RawBytes{}), "VARCHAR", []byte("txt"), "txt"},
+		{nil, "", []byte("dec"), "dec"},
+		{nil, "", nil, nil},
+	}
+	for i, tc := range cases {
+		got, err := mapValueConverter(tc.st, tc.dbType)(tc.in)
+		if err != nil {
+			t.Fatalf("case %d: %v", i, err)
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Fatalf("case %d: got %#v want %#v", i, got, tc.want)
+		}
+	}
+	if _, err := mapValueConverter(reflect.TypeOf(int64(0)), "")([]byte("x")); err == nil {
+		t.Fatal("expected parse error")
+	}
+}
+
+func TestGetMap(t *testing.T) {
+	var rows [][]driver.Value
+	db := newTestDB(t, func(q string, _ []driver.NamedValue) ([]string, [][]driver.Value, error) {
+		return []string{"id", "name"}, rows, nil
+	})
+	defer func() { _ = db.Close() }()
+
+	ctx := context.Background()
+	if _, err := GetMap(ctx, db, "q"); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("want sql.ErrNoRows, got %v", err)
+	}
+
+	rows = [][]driver.Value{{int64(1), []byte("ann")}, {int64(2), []byte("bob")}}
+	got, err := GetMap(ctx, db, "q")
+	if err != nil {
+		t.Fatalf("GetMap: %v", err)
+	}
+	if want := map[string]any{"id": int64(1), "name": "ann"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %#v want %#v", got, want)
+	}
+}
+
+func TestQueryMaps_QueryOpts(t *testing.T) {
+	db := newTestDB(t, func(q string, args []driver.NamedValue) ([]string, [][]driver.Value, error) {
+		if len(args) != 1 {
+			t.Fatalf("options reached the driver: %v", args)
+		}
+		return []string{"id"}, [][]driver.Value{{int64(1)}}, nil
+	})
+	defer func() { _ = db.Close() }()
+	ctx := context.Background()
+
+	if got, err := QueryMaps(ctx, db, "q", 1, WithTimeout(time.Minute)); err != nil || len(got) != 1 || got[0]["id"] != int64(1) {
+		t.Fatalf("QueryMaps: %v %v", got, err)
+	}
+	if got, err := GetMap(ctx, db, "q", WithTimeout(time.Minute), 1); err != nil || got["id"] != int64(1) {
+		t.Fatalf("GetMap: %v %v", got, err)
+	}
+	if _, err := GetMap(ctx, db, "q", 1, sql.Named("x", 1)); !errors.Is(err, ErrMixedArgs) {
+		t.Fatalf("GetMap mixed args: %v", err)
+	}
+}
+
+func TestGetMap_NextError(t *testing.T) {
+	db := sql.OpenDB(&errNextConnector{})
+	defer func() { _ = db.Close() }()
+	if _, err := GetMap(context.Background(), db, "q"); err == nil || err.Error() != "driver next error" {
+		t.Fatalf("want driver next error, got %v", err)
+	}
+}
+
+func TestQueryPairs(t *testing.T) {
+	type TeamID int32
+	db := newTestDB(t, func(q string, _ []driver.NamedValue) ([]string, [][]driver.Value, error) {
+		switch q {
+		case "three":
+			return []string{"a", "b", "c"}, nil, nil
+		case "empty":
+			return []string{"id", "name"}, nil, nil
+		}
+		return []string{"id", "name"}, [][]driver.Value{
+			{int64(1), []byte("red")},
+			{int64(2), "blue"},
+			{int64(1), "green"},
+		}, nil
+	})
+	defer func() { _ = db.Close() }()
+	ctx := context.Background()
+
+	got, err := QueryPairs[TeamID, string](ctx, db, "q")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, map[TeamID]string{1: "green", 2: "blue"}) {
+		t.Fatalf("unexpected pairs: %v", got)
+	}
+
+	empty, err := QueryPairs[int64, string](ctx, db, "empty")
+	if err != nil || empty == nil || len(empty) != 0 {
+		t.Fatalf("empty = %v, %v", empty, err)
+	}
+	if _, err := QueryPairs[int64, string](ctx, db, "three"); err == nil {
+		t.Fatalf("expected column count error")
+	}
+}
@@ -4,11 +4,17 @@ package xsql
 import (
 	"context"
 	"database/sql"
+	"database/sql/driver"
 	"errors"
 	"fmt"
+	"maps"
 	"reflect"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"unicode"
 	"unicode/utf8"
 )
@@ -16,10 +22,17 @@ import (
 // Placeholder selects the positional parameter style for a target database.
 //
 // Common choices:
-//   - PlaceholderQuestion   → "?"           (MySQL, SQLite, DuckDB, ClickHouse)
-//   - PlaceholderDollar     → "$1, $2, …"  (PostgreSQL)
-//   - PlaceholderAtP        → "@p1, @p2…"  (SQL Server)
-//   - PlaceholderColonNum   → ":1, :2, …"  (Oracle)
+//   - PlaceholderQuestion    → "?"          (MySQL, SQLite, DuckDB, ClickHouse)
+//   - PlaceholderDollar      → "$1, $2, …"  (PostgreSQL)
+//   - PlaceholderAtP         → "@p1, @p2…"  (SQL Server)
+//   - PlaceholderColonNum    → ":1, :2, …"  (Oracle)
+//   - PlaceholderQuestionNum → "?1, ?2, …"  (SQLite)
+//
+// PlaceholderAtName and PlaceholderColonName keep named parameters named:
+// :name is written as @name (SQL Server) or :name (Oracle) and its value is
+// passed as sql.Named("name", v), for drivers with native named parameters
+// such as go-mssqldb and godror. Other positional styles can be added with
+// [RegisterPlaceholder].
 type Placeholder int
 
 const (
@@ -27,8 +40,23 @@ const (
 	PlaceholderDollar
 	PlaceholderAtP
 	PlaceholderColonNum
+	PlaceholderAtName
+	PlaceholderColonName
+	PlaceholderQuestionNum
 )
 
+// native reports whether ph emits driver-native named parameters.
+func (ph Placeholder) native() bool {
+	return ph == PlaceholderAtName || ph == PlaceholderColonName
+}
+
+// passesOnce reports whether ph passes each named parameter once however
+// often the query uses it: native names, and ?N, which can refer back to an
+// earlier argument.
+func (ph Placeholder) passesOnce() bool {
+	return ph.native() || ph == PlaceholderQuestionNum
+}
+
 // ErrNilParams is returned when named binding is requested with a nil pointer
 // or nil params value. This typically means you passed a nil *struct to Rebind.
 var ErrNilParams = errors.New("xsql: named bind: nil params")
@@ -41,6 +69,14 @@ var ErrUnsupportedArg = errors.New("xsql: named bind: params must be struct or m
 // resolve to the same logical parameter name (case-insensitive), e.g. via db:"name".
 var ErrDuplicateKeyTag = errors.New("xsql: named bind: duplicate key from struct tags/fields")
 
+// ErrUnusedParam is returned, with [WithStrictParams], when a map passed for
+// named binding has keys that no :name in the query uses. The error lists them.
+var ErrUnusedParam = errors.New("xsql: named bind: unused parameters")
+
+// ErrMixedArgs is returned when sql.Named arguments are combined with plain
+// positional arguments in a single call.
+var ErrMixedArgs = errors.New("xsql: cannot mix sql.Named and positional arguments")
+
 // Rebind resolves :named parameters (if applicable) and rewrites placeholders.
 //
 // Usage:
@@ -54,19 +90,88 @@ var ErrDuplicateKeyTag = errors.New("xsql: named bind: duplicate key from struct
 //     // sql  => SELECT * FROM users WHERE status=$1 AND id IN ($2,$3,$4)
 //     // args => ["active", 1, 2, 3]
 //
+//     Nested structs and maps are addressed with dots: :filter.status.
+//
 //     Notes: slices/arrays expand; []byte is scalar; empty slice/array becomes NULL
-//     (so `IN (NULL)` matches no rows on most engines).
+//     (so `IN (NULL)` matches no rows on most engines). Slices implementing
+//     driver.Valuer bind as one value; values and slice elements implementing it
+//     are converted with Value, and nil pointers bind as NULL.
+//
+//   - A parameter holding a slice of structs or maps, followed by a column
+//     list, expands to one group per row, for multi-row VALUES:
+//     `INSERT INTO t (a, b) VALUES :rows(a, b)` => VALUES ($1,$2),($3,$4)
+//     A slice of slices expands the same way without a column list, for
+//     row-value IN: `(a, b) IN (:pairs)` => (a, b) IN (($1,$2),($3,$4))
+//
+//   - A section between /*:if name*/ and /*:end*/ is removed when the
+//     parameter is absent, nil, or an empty slice, map, or string, for
+//     optional filters: `WHERE a=:a /*:if ids*/AND id IN (:ids)/*:end*/`.
+//
+//   - Several structs and maps, for a query with :named parameters, are merged
+//     into one set of values; for a name present in several, the last one wins:
+//     sql, args, err := xsql.Rebind(
+//     `SELECT * FROM orders WHERE tenant_id=:tenant_id AND status=:status LIMIT :limit`,
+//     xsql.PlaceholderDollar,
+//     scope, // struct{ TenantID int64 `db:"tenant_id"`; Limit int `db:"limit"` }
+//     map[string]any{"status": "open", "limit": 10},
+//     )
 //
 //   - Positional passthrough (any other params shape):
 //     // params are already positional; only placeholder rewriting is applied
 //     sql, args, _ := xsql.Rebind(`a=? AND b=?`, xsql.PlaceholderColonNum, "A", 10)
 //
+//   - Driver-native named args ([sql.NamedArg] values from sql.Named):
+//     // query and args are returned unchanged for drivers with real named
+//     // parameters (e.g. @name on SQL Server, :name on Oracle)
+//     sql, args, _ := xsql.Rebind(`a=@a`, xsql.PlaceholderAtP, sql.Named("a", 1))
+//
+//     Mixing sql.Named with positional args returns [ErrMixedArgs].
+//
+//   - Native named output (PlaceholderAtName, PlaceholderColonName):
+//     sql, args, _ := xsql.Rebind(`a=:a AND id IN (:ids)`, xsql.PlaceholderAtName,
+//     map[string]any{"a": 1, "ids": []int{7, 8}})
+//     // sql  => a=@a AND id IN (@ids_1,@ids_2)
+//     // args => [sql.Named("a", 1), sql.Named("ids_1", 7), sql.Named("ids_2", 8)]
+//
+//     Each name is passed once however often it appears; slice elements get
+//     numbered names. Positional args are numbered as for PlaceholderAtP and
+//     PlaceholderColonNum.
+//
 // Rules of thumb:
 //   - Pass exactly one struct or map to use :named binding.
 //   - Pass multiple values (or a non-struct/map) to use positional args.
 //   - SQL scanning safely skips quoted strings, comments, and PostgreSQL $tag$…$tag$ blocks.
+//   - PostgreSQL casts (::int), MySQL assignments (@v := 1), and colons directly
+//     after a word (arr[1:2]) are not parameters.
 func Rebind(query string, ph Placeholder, params ...any) (string, []any, error) {
+	params, opts := splitOpts(params)
+	return rebind(query, ph, params, newCallConfig(opts))
+}
+
+func rebind(query string, ph Placeholder, params []any, cfg callConfig) (string, []any, error) {
+	if named, err := checkNamedArgs(params); err != nil {
+		return "", nil, err
+	} else if named {
+		return query, params, nil
+	}
+	if len(params) > 1 && allBindable(params) {
+		s, err := namedStmtFor(query, ph)
+		if err != nil {
+			return "", nil, err
+		}
+		if s.named {
+			return s.bind(params, cfg)
+		}
+	}
 	if len(params) == 1 && looksBindable(params[0]) {
+		// Without the cache, the plain case binds in a single scan.
+		if ph.passesOnce() || cfg.strictParams || rebindCache.limit.Load() > 0 {
+			s, err := namedStmtFor(query, ph)
+			if err != nil {
+				return "", nil, err
+			}
+			return s.bind(params, cfg)
+		}
 		qPos, args, err := bindNamedParams(query, params[0])
 		if err != nil {
 			return "", nil, err
@@ -77,7 +182,7 @@ func Rebind(query string, ph Placeholder, params ...any) (string, []any, error)
 }
 
 // NamedExec is a convenience for Exec with named or positional arguments.
-// It calls Rebind, then ExecContext on your Execer (e.g., *sql.DB, *sql.Tx, *sql.Conn).
+// It calls Rebind, then Exec on your Execer (e.g., *sql.DB, *sql.Tx, *sql.Conn).
 //
 // Example:
 //
@@ -86,11 +191,12 @@ func Rebind(query string, ph Placeholder, params ...any) (string, []any, error)
 //	    map[string]any{"p": 100, "ids": []int{7,8,9}},
 //	)
 func NamedExec(ctx context.Context, e Execer, ph Placeholder, query string, params ...any) (sql.Result, error) {
-	bound, args, err := Rebind(query, ph, params...)
+	params, opts := splitOpts(params)
+	bound, args, err := rebind(query, ph, params, newCallConfig(opts))
 	if err != nil {
 		return nil, err
 	}
-	return e.ExecContext(ctx, bound, args...)
+	return Exec(ctx, e, bound, appendOpts(args, opts)...)
 }
 
 // NamedQuery runs a query with named or positional arguments and scans results
@@ -104,23 +210,58 @@ func NamedExec(ctx context.Context, e Execer, ph Placeholder, query string, para
 //	    map[string]any{"s":"active"},
 //	)
 func NamedQuery[T any](ctx context.Context, q Querier, ph Placeholder, query string, params ...any) ([]T, error) {
-	bound, args, err := Rebind(query, ph, params...)
+	params, opts := splitOpts(params)
+	bound, args, err := rebind(query, ph, params, newCallConfig(opts))
 	if err != nil {
 		return nil, err
 	}
-	return Query[T](ctx, q, bound, args...)
+	return Query[T](ctx, q, bound, appendOpts(args, opts)...)
+}
+
+// NamedGet runs a query with named or positional arguments and scans the first
+// row using your existing Get[T]. Like Get, it returns sql.ErrNoRows when the
+// query produces no rows.
+//
+// Example:
+//
+//	u, err := xsql.NamedGet[User](ctx, db, xsql.PlaceholderDollar,
+//	    `SELECT id, email FROM users WHERE email=:email`,
+//	    map[string]any{"email": "a@x.com"},
+//	)
+//	if errors.Is(err, sql.ErrNoRows) {
+//	    // not found
+//	}
+func NamedGet[T any](ctx context.Context, q Querier, ph Placeholder, query string, params ...any) (T, error) {
+	params, opts := splitOpts(params)
+	bound, args, err := rebind(query, ph, params, newCallConfig(opts))
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return Get[T](ctx, q, bound, appendOpts(args, opts)...)
 }
 
 // PlaceholderFor picks a Placeholder based on a driver name string.
 // This is a convenience for one-off calls; you can also choose the enum directly.
+// With [PreferNumbered], SQLite drivers get PlaceholderQuestionNum.
 //
 // Examples:
 //
-//	ph := xsql.PlaceholderFor("pgx")       // => PlaceholderDollar
-//	ph := xsql.PlaceholderFor("sqlserver") // => PlaceholderAtP
-//	ph := xsql.PlaceholderFor("mysql")     // => PlaceholderQuestion
-func PlaceholderFor(driverName string) Placeholder {
+//	ph := xsql.PlaceholderFor("pgx")                            // => PlaceholderDollar
+//	ph := xsql.PlaceholderFor("sqlserver")                      // => PlaceholderAtP
+//	ph := xsql.PlaceholderFor("mysql")                          // => PlaceholderQuestion
+//	ph := xsql.PlaceholderFor("sqlite3", xsql.PreferNumbered()) // => PlaceholderQuestionNum
+func PlaceholderFor(driverName string, opts ...PlaceholderOpt) Placeholder {
+	var prefs placeholderPrefs
+	for _, o := range opts {
+		o(&prefs)
+	}
 	switch strings.ToLower(driverName) {
+	case "sqlite", "sqlite3", "libsql":
+		if prefs.numbered {
+			return PlaceholderQuestionNum
+		}
+		return PlaceholderQuestion
 	case "pgx", "postgres", "postgresql", "lib/pq", "pg":
 		return PlaceholderDollar
 	case "sqlserver", "mssql":
@@ -132,13 +273,237 @@ func PlaceholderFor(driverName string) Placeholder {
 	}
 }
 
+// PlaceholderOpt adjusts the style [PlaceholderFor] picks.
+type PlaceholderOpt func(*placeholderPrefs)
+
+type placeholderPrefs struct {
+	numbered bool
+}
+
+// PreferNumbered makes [PlaceholderFor] pick numbered ?1, ?2, … placeholders
+// (PlaceholderQuestionNum) for SQLite, which pass a repeated named parameter
+// once instead of once per use.
+func PreferNumbered() PlaceholderOpt {
+	return func(p *placeholderPrefs) { p.numbered = true }
+}
+
+// DetectPlaceholder reports the placeholder style a query is already written
+// in, going by its first positional placeholder outside quotes and comments:
+// $1 (PlaceholderDollar), @p1 (PlaceholderAtP), :1 (PlaceholderColonNum),
+// ?1 (PlaceholderQuestionNum), or ? (PlaceholderQuestion). :name parameters, casts, and operators such as ?|
+// do not count. A query without placeholders, or one that cannot be scanned,
+// reports PlaceholderQuestion.
+//
+// Example:
+//
+//	xsql.DetectPlaceholder(`SELECT * FROM users WHERE id = $1`) // => PlaceholderDollar
+//	xsql.DetectPlaceholder(`SELECT * FROM users WHERE id = ?`)  // => PlaceholderQuestion
+func DetectPlaceholder(query string) Placeholder {
+	isDigit := func(i int) bool { return i < len(query) && query[i] >= '0' && query[i] <= '9' }
+	i := 0
+	for i < len(query) {
+		j, err := skipNonCode(query, i)
+		if err != nil {
+			break
+		}
+		if j > i {
+			i = j
+			continue
+		}
+		switch query[i] {
+		case '$':
+			if isDigit(i + 1) {
+				return PlaceholderDollar
+			}
+		case '@':
+			if i+1 < len(query) && (query[i+1] == 'p' || query[i+1] == 'P') && isDigit(i+2) {
+				return PlaceholderAtP
+			}
+		case ':':
+			if hasPrefix(query[i:], "::") {
+				i += 2
+				continue
+			}
+			if prev, _ := utf8.DecodeLastRuneInString(query[:i]); isDigit(i+1) && !isTagChar(prev) {
+				return PlaceholderColonNum
+			}
+		case '?':
+			kind, n := scanQuestion(query, i)
+			if kind == questionPlaceholder {
+				if isDigit(i + 1) {
+					return PlaceholderQuestionNum
+				}
+				return PlaceholderQuestion
+			}
+			i += n
+			continue
+		}
+		i++
+	}
+	return PlaceholderQuestion
+}
+
+// PlaceholderForDB picks a Placeholder for the driver behind db, going by the
+// package of its driver type (pgx, lib/pq, go-mssqldb, godror, go-ora, …).
+// Drivers it does not recognize, including MySQL and SQLite, get
+// PlaceholderQuestion, as with PlaceholderFor.
+//
+// Example:
+//
+//	db, _ := sql.Open("pgx", dsn)
+//	ph := xsql.PlaceholderForDB(db) // => PlaceholderDollar
+func PlaceholderForDB(db *sql.DB) Placeholder {
+	return placeholderForType(reflect.TypeOf(db.Driver()))
+}
+
+// driverPlaceholders maps fragments of driver package paths to their
+// placeholder style; the first match wins.
+var driverPlaceholders = []struct {
+	frag string
+	ph   Placeholder
+}{
+	{"pgx", PlaceholderDollar},
+	{"lib/pq", PlaceholderDollar},
+	{"postgres", PlaceholderDollar},
+	{"mssql", PlaceholderAtP},
+	{"sqlserver", PlaceholderAtP},
+	{"godror", PlaceholderColonNum},
+	{"go-ora", PlaceholderColonNum},
+	{"oracle", PlaceholderColonNum},
+}
+
+func placeholderForType(t reflect.Type) Placeholder {
+	if t == nil {
+		return PlaceholderQuestion
+	}
+	return placeholderForPkg(derefPtr(t).PkgPath())
+}
+
+func placeholderForPkg(path string) Placeholder {
+	path = strings.ToLower(path)
+	for _, d := range driverPlaceholders {
+		if strings.Contains(path, d.frag) {
+			return d.ph
+		}
+	}
+	return PlaceholderQuestion
+}
+
+// detectPlaceholder picks the Placeholder for q: a *sql.DB (or any wrapper
+// exposing Driver) by its driver, a *sql.Conn by its driver connection.
+// Transactions and other queriers do not reveal their driver.
+func detectPlaceholder(q any) (Placeholder, error) {
+	switch v := q.(type) {
+	case interface{ Driver() driver.Driver }:
+		return placeholderForType(reflect.TypeOf(v.Driver())), nil
+	case *sql.Conn:
+		var ph Placeholder
+		err := v.Raw(func(dc any) error {
+			ph = placeholderForType(reflect.TypeOf(dc))
+			return nil
+		})
+		return ph, err
+	}
+	return 0, fmt.Errorf("xsql: cannot detect the placeholder style of %T; pass one explicitly", q)
+}
+
+// NamedExecAuto is like NamedExec but detects the Placeholder from e, which
+// must be a *sql.DB or *sql.Conn (see PlaceholderForDB). Inside a transaction,
+// use NamedExec with an explicit Placeholder.
+//
+// Example:
+//
+//	_, err := xsql.NamedExecAuto(ctx, db,
+//	    `UPDATE users SET email=:email WHERE id=:id`, u)
+func NamedExecAuto(ctx context.Context, e Execer, query string, params ...any) (sql.Result, error) {
+	ph, err := detectPlaceholder(e)
+	if err != nil {
+		return nil, err
+	}
+	return NamedExec(ctx, e, ph, query, params...)
+}
+
+// NamedQueryAuto is like NamedQuery but detects the Placeholder from q, which
+// must be a *sql.DB or *sql.Conn (see PlaceholderForDB).
+//
+// Example:
+//
+//	users, err := xsql.NamedQueryAuto[User](ctx, db,
+//	    `SELECT id, email FROM users WHERE status=:s`, map[string]any{"s": "active"})
+func NamedQueryAuto[T any](ctx context.Context, q Querier, query string, params ...any) ([]T, error) {
+	ph, err := detectPlaceholder(q)
+	if err != nil {
+		return nil, err
+	}
+	return NamedQuery[T](ctx, q, ph, query, params...)
+}
+
+// trimStatement strips surrounding whitespace and trailing semicolons so the
+// query can be extended or nested.
+func trimStatement(query string) string {
+	return strings.TrimRight(strings.TrimSpace(query), "; \t\r\n")
+}
+
+// Param is a :name parameter found by [ParseNamed].
+type Param struct {
+	Name    string   // as written, without the colon; dotted for nested values (user.id)
+	Start   int      // byte offset of the colon
+	End     int      // byte offset just past the parameter, including any column list
+	Columns []string // for a row list, :name(col, …), the listed columns
+}
+
+// ParseNamed returns the :name parameters of query in order of appearance,
+// using the same scanner as [Rebind]: quoted strings, comments, dollar-quoted
+// bodies, casts (::int), and MySQL assignments (:=) are skipped. A name used
+// several times appears once per use. It fails on unterminated quotes and
+// comments.
+//
+// It suits tooling that checks queries ahead of time, such as verifying that
+// every parameter has a matching struct field.
+//
+// Example:
+//
+//	params, err := xsql.ParseNamed(`SELECT * FROM users WHERE id = :id AND org = :org.id`)
+//	// params => [{Name: "id", Start: 31, End: 34} {Name: "org.id", Start: 45, End: 52}]
+func ParseNamed(query string) ([]Param, error) {
+	toks, err := findNamedParams(query)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]Param, len(toks))
+	for i, t := range toks {
+		out[i] = Param{Name: t.name, Start: t.start, End: t.end, Columns: t.cols}
+	}
+	return out, nil
+}
+
 type nameToken struct {
 	name  string
 	start int
 	end   int
+	esc   bool     // a "??" escape rather than a parameter
+	cols  []string // :name(col, …): rows of a slice, for VALUES lists
+}
+
+// checkNamedArgs reports whether args are sql.NamedArg values. It returns
+// ErrMixedArgs if only some of them are.
+func checkNamedArgs(args []any) (bool, error) {
+	n := 0
+	for _, a := range args {
+		if _, ok := a.(sql.NamedArg); ok {
+			n++
+		}
+	}
+	if n > 0 && n != len(args) {
+		return false, ErrMixedArgs
+	}
+	return n > 0, nil
 }
 
 func looksBindable(v any) bool {
+	if _, ok := v.(sql.NamedArg); ok {
+		return false
+	}
 	rv := reflect.ValueOf(v)
 	for rv.Kind() == reflect.Pointer {
 		if rv.IsNil() {
@@ -152,11 +517,47 @@ func looksBindable(v any) bool {
 	return rv.Kind() == reflect.Struct
 }
 
+// allBindable reports whether every param is a struct or map[string]any.
+func allBindable(params []any) bool {
+	for _, p := range params {
+		if !looksBindable(p) {
+			return false
+		}
+	}
+	return true
+}
+
+// mergeParams merges several structs and maps into one map for named
+// binding; for a name present in several of them, the last one wins.
+func mergeParams(params []any) (map[string]any, error) {
+	merged := make(map[string]any)
+	for _, p := range params {
+		lut, err := buildParamLookup(p)
+		if err != nil {
+			return nil, err
+		}
+		maps.Copy(merged, lut.m)
+	}
+	return merged, nil
+}
+
 func bindNamedParams(query string, params any) (string, []any, error) {
 	if params == nil {
 		return "", nil, ErrNilParams
 	}
 
+	conds, err := findConditions(query)
+	if err != nil {
+		return "", nil, err
+	}
+	var lut *paramLookup
+	if len(conds) > 0 {
+		if lut, err = buildParamLookup(params); err != nil {
+			return "", nil, err
+		}
+		query = applyConditions(query, conds, lut)
+	}
+
 	toks, err := findNamedParams(query)
 	if err != nil {
 		return "", nil, err
@@ -165,105 +566,78 @@ func bindNamedParams(query string, params any) (string, []any, error) {
 		return query, nil, nil
 	}
 
-	lut, err := buildParamLookup(params)
-	if err != nil {
-		return "", nil, err
+	if lut == nil {
+		if lut, err = buildParamLookup(params); err != nil {
+			return "", nil, err
+		}
 	}
 
-	var b strings.Builder
-	b.Grow(len(query))
+	out := make([]byte, 0, len(query)+2*len(toks))
 	args := make([]any, 0, len(toks))
 	last := 0
 
 	for _, t := range toks {
-		b.WriteString(query[last:t.start])
+		out = append(out, query[last:t.start]...)
+		last = t.end
 
 		val, ok := lut.lookup(t.name)
 		if !ok {
 			return "", nil, fmt.Errorf("xsql: named bind: missing value for :%s", t.name)
 		}
 
-		rv := reflect.ValueOf(val)
-		if isSliceOrArray(rv) {
-			n := rv.Len()
-			if n == 0 {
-				b.WriteString("NULL")
-			} else {
-				for i := 0; i < n; i++ {
-					if i > 0 {
-						b.WriteByte(',')
-					}
-					b.WriteByte('?')
-					args = append(args, rv.Index(i).Interface())
-				}
+		if t.cols != nil {
+			rows, err := rowValues(t.name, val, t.cols)
+			if err != nil {
+				return "", nil, err
+			}
+			if out, args, _, err = appendRows(out, args, 0, PlaceholderQuestion, t.name, rows, t.cols, nil); err != nil {
+				return "", nil, err
 			}
-		} else {
-			b.WriteByte('?')
-			args = append(args, val)
+			continue
+		}
+		if out, args, _, err = appendBound(out, args, 0, PlaceholderQuestion, t.name, val); err != nil {
+			return "", nil, err
 		}
-		last = t.end
 	}
-	b.WriteString(query[last:])
-	return b.String(), args, nil
+	out = append(out, query[last:]...)
+	return string(out), args, nil
 }
 
 func findNamedParams(query string) ([]nameToken, error) {
 	var out []nameToken
 	i := 0
 	for i < len(query) {
-		r, w := utf8.DecodeRuneInString(query[i:])
-		switch r {
-		case '\'':
-			j, err := skipSingleQuoted(query, i+w)
-			if err != nil {
-				return nil, err
-			}
-			i = j
-			continue
-		case '"':
-			j, err := skipDoubleQuoted(query, i+w)
-			if err != nil {
-				return nil, err
-			}
-			i = j
-			continue
-		case '`':
-			j, err := skipBacktickQuoted(query, i+w)
-			if err != nil {
-				return nil, err
-			}
+		j, err := skipNonCode(query, i)
+		if err != nil {
+			return nil, err
+		}
+		if j > i {
 			i = j
 			continue
-		case '-':
-			if hasPrefix(query[i:], "--") {
-				i = skipLineComment(query, i+2)
-				continue
-			}
-		case '/':
-			if hasPrefix(query[i:], "/*") {
-				j, err := skipBlockComment(query, i+2)
-				if err != nil {
-					return nil, err
-				}
-				i = j
+		}
+		r, w := utf8.DecodeRuneInString(query[i:])
+		if r == ':' {
+			if hasPrefix(query[i:], "::") {
+				i += 2 // skip PG cast
 				continue
 			}
-		case '$':
-			if j, ok, err := skipDollarQuoted(query, i); err != nil {
-				return nil, err
-			} else if ok {
-				i = j
+			if hasPrefix(query[i:], ":=") {
+				i += 2 // skip MySQL assignment (@v := 1)
 				continue
 			}
-		case ':':
-			if hasPrefix(query[i:], "::") {
-				i += 2 // skip PG cast
+			// A colon right after a word (key:value, arr[1:2]) is not a parameter.
+			if prev, _ := utf8.DecodeLastRuneInString(query[:i]); isTagChar(prev) {
+				i += w
 				continue
 			}
 			start := i
-			name, end := parseIdent(query, i+1)
+			name, end := parseParamName(query, i+1)
 			if name != "" {
-				out = append(out, nameToken{name: name, start: start, end: end})
+				cols, cend := parseColumnList(query, end)
+				if cols != nil {
+					end = cend
+				}
+				out = append(out, nameToken{name: name, start: start, end: end, cols: cols})
 				i = end
 				continue
 			}
@@ -281,59 +655,29 @@ func rewritePlaceholders(query string, ph Placeholder) string {
 	i, arg := 0, 1
 
 	for i < len(query) {
-		r, w := utf8.DecodeRuneInString(query[i:])
-		switch r {
-		case '\'':
-			j, _ := skipSingleQuoted(query, i+w)
-			out = append(out, query[i:j]...)
-			i = j
-			continue
-		case '"':
-			j, _ := skipDoubleQuoted(query, i+w)
-			out = append(out, query[i:j]...)
-			i = j
-			continue
-		case '`':
-			j, _ := skipBacktickQuoted(query, i+w)
+		j, err := skipNonCode(query, i)
+		if err != nil {
+			j = len(query) // unterminated: callers tokenize first and report it
+		}
+		if j > i {
 			out = append(out, query[i:j]...)
 			i = j
 			continue
-		case '-':
-			if hasPrefix(query[i:], "--") {
-				j := skipLineComment(query, i+2)
-				out = append(out, query[i:j]...)
-				i = j
-				continue
-			}
-		case '/':
-			if hasPrefix(query[i:], "/*") {
-				j, _ := skipBlockComment(query, i+2)
-				out = append(out, query[i:j]...)
-				i = j
-				continue
-			}
-		case '$':
-			if j, ok, _ := skipDollarQuoted(query, i); ok {
-				out = append(out, query[i:j]...)
-				i = j
-				continue
-			}
-		case '?':
-			switch ph {
-			case PlaceholderDollar:
-				out = append(out, '$')
-				out = strconv.AppendInt(out, int64(arg), 10)
-			case PlaceholderAtP:
-				out = append(out, '@', 'p')
-				out = strconv.AppendInt(out, int64(arg), 10)
-			case PlaceholderColonNum:
-				out = append(out, ':')
-				out = strconv.AppendInt(out, int64(arg), 10)
-			default:
+		}
+		r, w := utf8.DecodeRuneInString(query[i:])
+		if r == '?' {
+			switch kind, n := scanQuestion(query, i); kind {
+			case questionEscape:
 				out = append(out, '?')
+				i += n
+			case questionOperator:
+				out = append(out, query[i:i+n]...)
+				i += n
+			default:
+				out = appendPlaceholder(out, ph, arg)
+				arg++
+				i += n
 			}
-			arg++
-			i += w
 			continue
 		}
 		out = append(out, query[i:i+w]...)
@@ -342,6 +686,166 @@ func rewritePlaceholders(query string, ph Placeholder) string {
 	return string(out)
 }
 
+// appendPlaceholder appends the n-th (1-based) positional placeholder in style ph.
+func appendPlaceholder(out []byte, ph Placeholder, n int) []byte {
+	switch ph {
+	case PlaceholderDollar:
+		out = append(out, '$')
+		return strconv.AppendInt(out, int64(n), 10)
+	case PlaceholderAtP, PlaceholderAtName:
+		out = append(out, '@', 'p')
+		return strconv.AppendInt(out, int64(n), 10)
+	case PlaceholderColonNum, PlaceholderColonName:
+		out = append(out, ':')
+		return strconv.AppendInt(out, int64(n), 10)
+	case PlaceholderQuestionNum:
+		out = append(out, '?')
+		return strconv.AppendInt(out, int64(n), 10)
+	default:
+		if fn, ok := customPlaceholder(ph); ok {
+			return append(out, fn(n)...)
+		}
+		return append(out, '?')
+	}
+}
+
+// questionOps are the operators beginning with '?' that placeholder
+// rewriting leaves alone; see SetQuestionOperators.
+var questionOps atomic.Pointer[[]string]
+
+func init() { SetQuestionOperators("?|", "?&") }
+
+// SetQuestionOperators replaces, process-wide, the list of SQL operators that
+// begin with '?' and must survive placeholder rewriting. The default list is
+// PostgreSQL's JSONB "?|" and "?&". An operator only matches when it is not
+// followed by another operator character, so "?||" (a parameter followed by
+// string concatenation) is still a parameter.
+//
+// The bare "?" operator cannot be told apart from a parameter; write it as
+// "??", which is rewritten to a literal "?" for the $1, @p1, and :1 styles.
+// The Rebind cache is emptied, as cached statements were tokenized with the
+// previous list. It panics if an operator does not begin with '?'.
+//
+// Example:
+//
+//	// PostgreSQL geometric operators, in addition to the JSONB defaults
+//	xsql.SetQuestionOperators("?|", "?&", "?-|", "?||", "?-", "?#")
+//
+//	q, _, _ := xsql.Rebind(`SELECT * FROM docs WHERE data ?| ? AND data ?? ?`,
+//	    xsql.PlaceholderDollar, []string{"a", "b"}, "c")
+//	// q => SELECT * FROM docs WHERE data ?| $1 AND data ? $2
+func SetQuestionOperators(ops ...string) {
+	list := make([]string, 0, len(ops))
+	for _, op := range ops {
+		if len(op) < 2 || op[0] != '?' {
+			panic(fmt.Sprintf("xsql: SetQuestionOperators: %q is not an operator beginning with '?'", op))
+		}
+		list = append(list, op)
+	}
+	// Longest first, so "?||" is tried before "?|".
+	sort.SliceStable(list, func(i, j int) bool { return len(list[i]) > len(list[j]) })
+	questionOps.Store(&list)
+	clearRebindCache()
+}
+
+const (
+	questionPlaceholder = iota
+	questionEscape      // ?? for a literal ?
+	questionOperator    // one of questionOps
+)
+
+// scanQuestion classifies the '?' at query[i] and returns its length.
+func scanQuestion(query string, i int) (kind, n int) {
+	rest := query[i:]
+	if hasPrefix(rest, "??") {
+		return questionEscape, 2
+	}
+	for _, op := range *questionOps.Load() {
+		if hasPrefix(rest, op) && (len(rest) == len(op) || !strings.ContainsRune("+-*/<>=~!@#%^&|`?", rune(rest[len(op)]))) {
+			return questionOperator, len(op)
+		}
+	}
+	return questionPlaceholder, 1
+}
+
+// customPlaceholderBase is the first Placeholder value handed out by
+// RegisterPlaceholder, leaving room for built-in styles below it.
+const customPlaceholderBase Placeholder = 1 << 10
+
+var (
+	customMu           sync.Mutex
+	customPlaceholders atomic.Pointer[[]func(n int) string]
+)
+
+// RegisterPlaceholder adds a positional placeholder style written by format,
+// which returns the placeholder for the 1-based argument n, and returns the
+// Placeholder to pass to [Rebind], [PrepareNamed], and the named functions.
+// It lets drivers with uncommon syntax use named binding without changes to
+// xsql. Register styles once, during program initialization; it panics if
+// format is nil.
+//
+// Example:
+//
+//	var PlaceholderColonV = xsql.RegisterPlaceholder(func(n int) string {
+//	    return ":v" + strconv.Itoa(n)
+//	})
+//
+//	q, args, err := xsql.Rebind(`SELECT * FROM t WHERE a = :a AND b = :b`, PlaceholderColonV,
+//	    map[string]any{"a": 1, "b": 2})
+//	// q => SELECT * FROM t WHERE a = :v1 AND b = :v2
+func RegisterPlaceholder(format func(n int) string) Placeholder {
+	if format == nil {
+		panic("xsql: RegisterPlaceholder: nil format")
+	}
+	customMu.Lock()
+	defer customMu.Unlock()
+	var fns []func(int) string
+	if p := customPlaceholders.Load(); p != nil {
+		fns = slices.Clone(*p)
+	}
+	fns = append(fns, format)
+	customPlaceholders.Store(&fns)
+	return customPlaceholderBase + Placeholder(len(fns)-1)
+}
+
+// customPlaceholder returns the format of a registered placeholder style.
+func customPlaceholder(ph Placeholder) (func(int) string, bool) {
+	p := customPlaceholders.Load()
+	if p == nil || ph < customPlaceholderBase || int(ph-customPlaceholderBase) >= len(*p) {
+		return nil, false
+	}
+	return (*p)[ph-customPlaceholderBase], true
+}
+
+// skipNonCode returns the index just past the quoted string, quoted
+// identifier, comment, or dollar-quoted body starting at i, or i itself when
+// none starts there.
+func skipNonCode(s string, i int) (int, error) {
+	switch s[i] {
+	case '\'':
+		return skipSingleQuoted(s, i+1)
+	case '"':
+		return skipDoubleQuoted(s, i+1)
+	case '`':
+		return skipBacktickQuoted(s, i+1)
+	case '-':
+		if hasPrefix(s[i:], "--") {
+			return skipLineComment(s, i+2), nil
+		}
+	case '/':
+		if hasPrefix(s[i:], "/*") {
+			return skipBlockComment(s, i+2)
+		}
+	case '$':
+		if j, ok, err := skipDollarQuoted(s, i); err != nil {
+			return 0, err
+		} else if ok {
+			return j, nil
+		}
+	}
+	return i, nil
+}
+
 func skipSingleQuoted(s string, i int) (int, error) {
 	for i < len(s) {
 		r, w := utf8.DecodeRuneInString(s[i:])
@@ -438,7 +942,7 @@ func parseIdent(s string, i int) (string, int) {
 	start := i
 	for i < len(s) {
 		r, w := utf8.DecodeRuneInString(s[i:])
-		if !(r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)) {
+		if !isTagChar(r) {
 			break
 		}
 		i += w
@@ -449,13 +953,192 @@ func parseIdent(s string, i int) (string, int) {
 	return s[start:i], i
 }
 
+// checkUnusedParams returns an ErrUnusedParam error if params is a map with
+// keys that none of toks or conds uses. A dotted token (:filter.status) uses
+// its first segment. Struct params are not checked: one struct usually serves
+// several statements, each using some of its fields.
+func checkUnusedParams(toks []nameToken, conds []condBlock, params any) error {
+	rv := reflect.ValueOf(params)
+	for rv.Kind() == reflect.Pointer && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Map {
+		return nil
+	}
+	used := make(map[string]bool, len(toks)+len(conds))
+	use := func(name string) {
+		name = strings.ToLower(name)
+		used[name] = true
+		if head, _, ok := strings.Cut(name, "."); ok {
+			used[head] = true
+		}
+	}
+	for _, t := range toks {
+		use(t.name)
+	}
+	for _, c := range conds {
+		use(c.name)
+	}
+	var unused []string
+	iter := rv.MapRange()
+	for iter.Next() {
+		if k := iter.Key().String(); !used[strings.ToLower(k)] {
+			unused = append(unused, ":"+k)
+		}
+	}
+	if len(unused) == 0 {
+		return nil
+	}
+	sort.Strings(unused)
+	return fmt.Errorf("%w: %s", ErrUnusedParam, strings.Join(unused, ", "))
+}
+
+// parseParamName parses a parameter name at s[i:]: an identifier, optionally
+// followed by .identifier segments addressing nested values (:filter.status).
+func parseParamName(s string, i int) (string, int) {
+	start := i
+	name, end := parseIdent(s, i)
+	if name == "" {
+		return "", end
+	}
+	for end < len(s) && s[end] == '.' {
+		seg, next := parseIdent(s, end+1)
+		if seg == "" {
+			break
+		}
+		end = next
+	}
+	return s[start:end], end
+}
+
+// parseColumnList parses the "(col, col, …)" directly after a parameter name
+// at s[i:], which makes it a row-list parameter. It returns nil if there is no
+// such list, as for a parameter followed by an unrelated parenthesis.
+func parseColumnList(s string, i int) ([]string, int) {
+	if i >= len(s) || s[i] != '(' {
+		return nil, i
+	}
+	var cols []string
+	i++
+	for {
+		i = skipSpaces(s, i)
+		col, end := parseParamName(s, i)
+		if col == "" || (col[0] >= '0' && col[0] <= '9') { // :fn(1) is not a column list
+			return nil, i
+		}
+		cols = append(cols, col)
+		i = end
+		i = skipSpaces(s, i)
+		switch {
+		case i < len(s) && s[i] == ',':
+			i++
+		case i < len(s) && s[i] == ')':
+			return cols, i + 1
+		default:
+			return nil, i
+		}
+	}
+}
+
+func skipSpaces(s string, i int) int {
+	for i < len(s) && strings.IndexByte(" \t\r\n", s[i]) >= 0 {
+		i++
+	}
+	return i
+}
+
+// rowValues resolves a :name(cols) parameter: val must be a non-empty slice or
+// array of structs or maps, and each row yields the values of cols in order.
+func rowValues(name string, val any, cols []string) ([][]any, error) {
+	rv := reflect.ValueOf(val)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil, fmt.Errorf("xsql: named bind: :%s(…) needs a slice of structs or maps, not %T", name, val)
+	}
+	if rv.Len() == 0 {
+		return nil, fmt.Errorf("xsql: named bind: :%s(…) has no rows", name)
+	}
+	rows := make([][]any, rv.Len())
+	for i := range rows {
+		lut, err := buildParamLookup(rv.Index(i).Interface())
+		if err != nil {
+			return nil, fmt.Errorf("xsql: named bind: :%s row %d: %w", name, i+1, err)
+		}
+		row := make([]any, len(cols))
+		for j, c := range cols {
+			v, ok := lut.lookup(c)
+			if !ok {
+				return nil, fmt.Errorf("xsql: named bind: :%s row %d: missing value for %s", name, i+1, c)
+			}
+			row[j] = v
+		}
+		rows[i] = row
+	}
+	return rows, nil
+}
+
+// tupleValues returns the rows of a non-empty slice whose elements are
+// themselves slices or arrays (not []byte or Valuers), for expansion into
+// row-value tuples: [][]any{{1, "a"}, {2, "b"}} => (?,?),(?,?). It returns
+// nil for a slice of scalars, and an error if the elements are mixed or the
+// tuples differ in length.
+func tupleValues(name string, rv reflect.Value) ([][]any, error) {
+	if !isSliceOrArray(reflect.ValueOf(rv.Index(0).Interface())) {
+		return nil, nil
+	}
+	rows := make([][]any, rv.Len())
+	for i := range rows {
+		e := reflect.ValueOf(rv.Index(i).Interface())
+		if !isSliceOrArray(e) {
+			return nil, fmt.Errorf("xsql: named bind: :%s element %d is %T, not a tuple", name, i+1, rv.Index(i).Interface())
+		}
+		if e.Len() == 0 {
+			return nil, fmt.Errorf("xsql: named bind: :%s tuple %d is empty", name, i+1)
+		}
+		if i > 0 && e.Len() != len(rows[0]) {
+			return nil, fmt.Errorf("xsql: named bind: :%s tuple %d has %d values, want %d", name, i+1, e.Len(), len(rows[0]))
+		}
+		row := make([]any, e.Len())
+		for j := range row {
+			row[j] = e.Index(j).Interface()
+		}
+		rows[i] = row
+	}
+	return rows, nil
+}
+
 type paramLookup struct {
-	m map[string]any // lowercase name -> value
+	m   map[string]any          // lowercase name -> value
+	sub map[string]*paramLookup // lazily built lookups of nested values
 }
 
+// lookup returns the value of a parameter. A dotted name (user.id) that is not
+// itself a key resolves its first segment and looks the rest up in that
+// value, which must be a struct or map[string]any.
 func (l *paramLookup) lookup(name string) (any, bool) {
-	v, ok := l.m[strings.ToLower(name)]
-	return v, ok
+	key := strings.ToLower(name)
+	if v, ok := l.m[key]; ok {
+		return v, true
+	}
+	head, rest, ok := strings.Cut(key, ".")
+	if !ok {
+		return nil, false
+	}
+	sub, ok := l.sub[head]
+	if !ok {
+		v, found := l.m[head]
+		if !found {
+			return nil, false
+		}
+		sub, _ = buildParamLookup(v) // nil (missing) for nil or non-struct values
+		if l.sub == nil {
+			l.sub = make(map[string]*paramLookup)
+		}
+		l.sub[head] = sub
+	}
+	if sub == nil {
+		return nil, false
+	}
+	return sub.lookup(rest)
 }
 
 func buildParamLookup(params any) (*paramLookup, error) {
@@ -517,21 +1200,41 @@ func addStructFields(dst map[string]any, v reflect.Value) error {
 				}
 				continue
 			}
+			// Embedded non-struct types bind like named fields, unless unexported.
+			if f.PkgPath != "" {
+				continue
+			}
 		}
 
 		tag := f.Tag.Get("db")
-		if tag == "-" {
+		name, _, omit := parseTag(tag)
+		if omit || hasTagOption(tag, "readonly") { // readonly: never written
 			continue
 		}
-		name := tag
+		name, alts := splitAliases(name)
 		if name == "" {
 			name = f.Name
 		}
-		key := strings.ToLower(name)
-		if _, exists := dst[key]; exists {
-			return fmt.Errorf("%w: %q", ErrDuplicateKeyTag, key)
+		val := v.Field(i).Interface()
+		if out, inout := hasTagOption(tag, "out"), hasTagOption(tag, "inout"); out || inout {
+			if !v.Field(i).CanAddr() {
+				return fmt.Errorf("xsql: named bind: output field %s.%s requires params passed by pointer", t, f.Name)
+			}
+			val = sql.Out{Dest: v.Field(i).Addr().Interface(), In: inout}
+		} else if codec, ok := tagValue(tag, "codec"); ok {
+			var err error
+			if val, err = encodeField(v.Field(i), codec); err != nil {
+				return err
+			}
+		}
+		// Alternative names (`db:"id|user_id"`) bind the same value.
+		for _, n := range append([]string{name}, alts...) {
+			key := strings.ToLower(n)
+			if _, exists := dst[key]; exists {
+				return fmt.Errorf("%w: %q", ErrDuplicateKeyTag, key)
+			}
+			dst[key] = val
 		}
-		dst[key] = v.Field(i).Interface()
 	}
 	return nil
 }
@@ -540,6 +1243,14 @@ func isSliceOrArray(v reflect.Value) bool {
 	if !v.IsValid() {
 		return false
 	}
+	// A slice that converts itself (pq.StringArray, a JSON column type) binds
+	// as one value.
+	if v.Type().Implements(valuerType) {
+		return false
+	}
+	if _, ok := globalConverters.valuer(v.Type()); ok {
+		return false
+	}
 	switch v.Kind() {
 	case reflect.Slice:
 		return v.Type().Elem().Kind() != reflect.Uint8 // []byte → scalar
@@ -549,3 +1260,30 @@ func isSliceOrArray(v reflect.Value) bool {
 		return false
 	}
 }
+
+var valuerType = reflect.TypeOf((*driver.Valuer)(nil)).Elem()
+
+// bindArg returns the value bound for parameter name, converted by argValue.
+func bindArg(name string, v any) (any, error) {
+	dv, err := argValue(v)
+	if err != nil {
+		return nil, fmt.Errorf("xsql: named bind: :%s: %w", name, err)
+	}
+	return dv, nil
+}
+
+// argValue converts an expanded argument as database/sql would: a nil pointer
+// becomes NULL and a driver.Valuer the result of its Value method.
+func argValue(v any) (any, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Pointer && rv.IsNil() {
+		// A Valuer with a pointer receiver may still handle nil itself.
+		if _, ok := v.(driver.Valuer); !ok || rv.Type().Elem().Implements(valuerType) {
+			return nil, nil
+		}
+	}
+	if vr, ok := v.(driver.Valuer); ok {
+		return vr.Value()
+	}
+	return v, nil
+}
@@ -0,0 +1,82 @@
+package xsql
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// DefaultRebindCacheSize is the initial number of statements the Rebind cache
+// holds; see [SetRebindCacheSize].
+const DefaultRebindCacheSize = 1024
+
+// rebindCache holds the statements tokenized by Rebind and the named
+// functions, keyed by query text and placeholder style, so repeated calls skip
+// the SQL scanner. When it grows past its limit it is emptied and refilled by
+// the statements in use, which keeps memory bounded for applications that
+// build many distinct queries at run time.
+var rebindCache struct {
+	m     sync.Map // namedKey -> *NamedStmt
+	n     atomic.Int64
+	limit atomic.Int64
+}
+
+func init() { rebindCache.limit.Store(DefaultRebindCacheSize) }
+
+type namedKey struct {
+	query string
+	ph    Placeholder
+}
+
+// SetRebindCacheSize sets how many tokenized statements [Rebind],
+// [NamedExec], [NamedQuery], and [NamedGet] keep for reuse, and empties the
+// cache. A size of 0 or less disables caching, so every call scans the SQL.
+// The cache is safe for concurrent use; set its size during program
+// initialization.
+//
+// Example:
+//
+//	xsql.SetRebindCacheSize(10000) // many distinct statements in the hot path
+func SetRebindCacheSize(n int) {
+	rebindCache.limit.Store(int64(n))
+	clearRebindCache()
+}
+
+// clearRebindCache empties the cache; settings that change how statements are
+// tokenized call it so that no statement tokenized the old way is reused.
+func clearRebindCache() {
+	rebindCache.m.Clear()
+	rebindCache.n.Store(0)
+}
+
+// cachedNamed returns the statement for query and ph from the cache,
+// tokenizing and storing it on a miss. ok is false when caching is disabled.
+func cachedNamed(query string, ph Placeholder) (s *NamedStmt, ok bool, err error) {
+	limit := rebindCache.limit.Load()
+	if limit <= 0 {
+		return nil, false, nil
+	}
+	key := namedKey{query, ph}
+	if v, found := rebindCache.m.Load(key); found {
+		return v.(*NamedStmt), true, nil
+	}
+	if s, err = PrepareNamed(query, ph); err != nil {
+		return nil, true, err
+	}
+	if rebindCache.n.Add(1) > limit {
+		rebindCache.m.Clear()
+		rebindCache.n.Store(1)
+	}
+	if v, loaded := rebindCache.m.LoadOrStore(key, s); loaded {
+		return v.(*NamedStmt), true, nil
+	}
+	return s, true, nil
+}
+
+// namedStmtFor returns the cached statement for query and ph, or a newly
+// tokenized one when caching is disabled.
+func namedStmtFor(query string, ph Placeholder) (*NamedStmt, error) {
+	if s, ok, err := cachedNamed(query, ph); ok || err != nil {
+		return s, err
+	}
+	return PrepareNamed(query, ph)
+}
@@ -0,0 +1,62 @@
+package xsql
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestRebindCache(t *testing.T) {
+	t.Cleanup(func() { SetRebindCacheSize(DefaultRebindCacheSize) })
+	SetRebindCacheSize(2)
+
+	const q = `SELECT * FROM t WHERE a = :a AND id IN (:ids) AND b = ?`
+	s1, ok, err := cachedNamed(q, PlaceholderDollar)
+	if err != nil || !ok {
+		t.Fatal(ok, err)
+	}
+	if s2, _, _ := cachedNamed(q, PlaceholderDollar); s2 != s1 {
+		t.Fatal("expected a cache hit")
+	}
+	if s3, _, _ := cachedNamed(q, PlaceholderAtP); s3 == s1 {
+		t.Fatal("placeholder must be part of the key")
+	}
+	for i := range 5 {
+		if _, _, err := cachedNamed(fmt.Sprintf("SELECT %d", i), PlaceholderDollar); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := rebindCache.n.Load(); n > 2 {
+		t.Fatalf("cache holds %d statements, limit 2", n)
+	}
+	if _, _, err := cachedNamed(`SELECT 'open`, PlaceholderDollar); err == nil {
+		t.Fatal("expected scan error")
+	}
+
+	// Cached and uncached binding agree.
+	params := map[string]any{"a": 1, "ids": []int{2, 3}}
+	cq, cargs, cerr := Rebind(q, PlaceholderDollar, params)
+	SetRebindCacheSize(0)
+	if _, ok, _ := cachedNamed(q, PlaceholderDollar); ok {
+		t.Fatal("cache should be disabled")
+	}
+	uq, uargs, uerr := Rebind(q, PlaceholderDollar, params)
+	if cerr != nil || uerr != nil || cq != uq || fmt.Sprint(cargs) != fmt.Sprint(uargs) {
+		t.Fatalf("cached %q %v %v, uncached %q %v %v", cq, cargs, cerr, uq, uargs, uerr)
+	}
+	if cq != `SELECT * FROM t WHERE a = $1 AND id IN ($2,$3) AND b = $4` {
+		t.Fatalf("sql = %q", cq)
+	}
+}
+
+func TestRebindCache_QuestionOperators(t *testing.T) {
+	t.Cleanup(func() { SetQuestionOperators("?|", "?&") })
+	const q = `SELECT * FROM t WHERE a ?# b AND c = :c`
+	params := map[string]any{"c": 1}
+	if got, _, err := Rebind(q, PlaceholderDollar, params); err != nil || got != `SELECT * FROM t WHERE a $1# b AND c = $2` {
+		t.Fatalf("default operators: %q, %v", got, err)
+	}
+	SetQuestionOperators("?|", "?&", "?#")
+	if got, _, err := Rebind(q, PlaceholderDollar, params); err != nil || got != `SELECT * FROM t WHERE a ?# b AND c = $1` {
+		t.Fatalf("after SetQuestionOperators: %q, %v", got, err)
+	}
+}
@@ -0,0 +1,83 @@
+package xsql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// NamedExecMany executes a named statement once per element of items, binding
+// each struct or map like [NamedExec]. The query is tokenized once (see
+// [PrepareNamed]); when e can prepare statements (*sql.DB, *sql.Tx,
+// *sql.Conn), the bound SQL is also prepared once and re-executed for every
+// item whose expansion yields the same text, so the driver sends only the
+// arguments.
+//
+// Items run in order and the call stops at the first failure; the returned
+// error names the 1-based item and wraps the cause. NamedExecMany does not
+// open a transaction; use [NamedExecManyTx] to apply all items atomically.
+// Options apply to the whole call.
+//
+// Example:
+//
+//	err := xsql.NamedExecMany(ctx, db, xsql.PlaceholderDollar,
+//	    `INSERT INTO users (email, name) VALUES (:email, :name)`, users)
+func NamedExecMany[T any](ctx context.Context, e Execer, ph Placeholder, query string, items []T, opts ...QueryOpt) error {
+	s, err := PrepareNamed(query, ph)
+	if err != nil {
+		return err
+	}
+	cfg := newCallConfig(opts)
+	ctx, cancel := cfg.context(ctx)
+	defer cancel()
+
+	p, _ := e.(Preparer)
+	var (
+		stmt     *sql.Stmt
+		prepared string
+	)
+	defer func() {
+		if stmt != nil {
+			_ = stmt.Close()
+		}
+	}()
+	exec := func(item T) error {
+		q, args, err := s.bind([]any{item}, cfg)
+		if err != nil {
+			return err
+		}
+		if args, err = convertArgs(args); err != nil {
+			return err
+		}
+		if p == nil {
+			_, err = e.ExecContext(ctx, q, args...)
+			return err
+		}
+		if stmt == nil || q != prepared {
+			if stmt != nil {
+				_ = stmt.Close()
+			}
+			if stmt, err = p.PrepareContext(ctx, q); err != nil {
+				return err
+			}
+			prepared = q
+		}
+		_, err = stmt.ExecContext(ctx, args...)
+		return err
+	}
+	for i, item := range items {
+		if err := exec(item); err != nil {
+			return fmt.Errorf("xsql: named exec item %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+// NamedExecManyTx is like [NamedExecMany] but runs all items inside a single
+// transaction started on db. The transaction is committed if every item
+// succeeds and rolled back otherwise.
+func NamedExecManyTx[T any](ctx context.Context, db Beginner, ph Placeholder, query string, items []T, opts ...QueryOpt) error {
+	return WithTx(ctx, db, func(tx *sql.Tx) error {
+		return NamedExecMany(ctx, tx, ph, query, items, opts...)
+	})
+}
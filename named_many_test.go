@@ -0,0 +1,67 @@
+package xsql
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestNamedExecMany(t *testing.T) {
+	type user struct {
+		Email string `db:"email"`
+		Tags  []int  `db:"tags"`
+	}
+	var calls []string
+	var seen [][]driver.NamedValue
+	db := newTestDB(t, func(q string, args []driver.NamedValue) ([]string, [][]driver.Value, error) {
+		if len(args) > 0 && args[0].Value == "bad" {
+			return nil, nil, errors.New("boom")
+		}
+		calls = append(calls, q)
+		seen = append(seen, args)
+		return nil, nil, nil
+	})
+	defer func() { _ = db.Close() }()
+	ctx := context.Background()
+
+	const q = `INSERT INTO users (email, tags) VALUES (:email, :tags)`
+	items := []user{{"a", []int{1}}, {"b", []int{2}}, {"c", []int{3, 4}}}
+	if err := NamedExecMany(ctx, db, PlaceholderDollar, q, items); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		`INSERT INTO users (email, tags) VALUES ($1, $2)`,
+		`INSERT INTO users (email, tags) VALUES ($1, $2)`,
+		`INSERT INTO users (email, tags) VALUES ($1, $2,$3)`,
+	}
+	if strings.Join(calls, "\n") != strings.Join(want, "\n") || len(seen[2]) != 3 || seen[1][0].Value != "b" {
+		t.Fatalf("calls = %q, args = %v", calls, seen)
+	}
+
+	// Maps work too, and the failing item is named.
+	calls = nil
+	maps := []map[string]any{{"email": "x", "tags": 1}, {"email": "bad", "tags": 2}, {"email": "y", "tags": 3}}
+	err := NamedExecMany(ctx, db, PlaceholderQuestion, q, maps)
+	if err == nil || !strings.Contains(err.Error(), "item 2: boom") || len(calls) != 1 {
+		t.Fatalf("err = %v, calls = %q", err, calls)
+	}
+	if err := NamedExecMany(ctx, db, PlaceholderQuestion, q, []map[string]any{{"email": "x"}}); err == nil || !strings.Contains(err.Error(), "item 1: xsql: named bind: missing value for :tags") {
+		t.Fatalf("missing param: %v", err)
+	}
+
+	// Through an Execer that cannot prepare.
+	e := &execer{}
+	if err := NamedExecMany(ctx, e, PlaceholderAtP, q, items[:1]); err != nil || e.lastQuery != `INSERT INTO users (email, tags) VALUES (@p1, @p2)` {
+		t.Fatalf("execer: %q, %v", e.lastQuery, err)
+	}
+
+	calls = nil
+	if err := NamedExecManyTx(ctx, db, PlaceholderQuestion, q, items); err != nil || len(calls) != 3 {
+		t.Fatalf("tx: %v, %d calls", err, len(calls))
+	}
+	if err := NamedExecManyTx(ctx, db, PlaceholderQuestion, q, maps); err == nil {
+		t.Fatal("tx: expected error")
+	}
+}
@@ -0,0 +1,387 @@
+package xsql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// NamedStmt is a named-parameter query that has been tokenized once for a
+// target Placeholder style. Binding it only looks up parameter values and
+// expands slices; the SQL text is never rescanned for quotes, comments, or
+// dollar-quoted bodies.
+//
+// A NamedStmt is immutable and safe for concurrent use. It is a client-side
+// object: it does not hold a driver connection or prepared statement.
+type NamedStmt struct {
+	query string
+	ph    Placeholder
+	segs  []string    // len(toks)+1 literal SQL pieces around the tokens
+	toks  []nameToken // named parameters, plus literal '?' markers (empty name)
+	named bool        // at least one :name token
+	conds []condBlock // /*:if name*/ … /*:end*/ sections
+}
+
+// PrepareNamed tokenizes a query written with :name parameters for placeholder
+// style ph and returns the compiled statement. It accepts the same SQL as
+// [Rebind] and produces identical output, so it can replace Rebind, NamedExec,
+// and NamedQuery on hot paths.
+//
+// Example:
+//
+//	updatePrice, err := xsql.PrepareNamed(
+//	    `UPDATE items SET price = :price WHERE id IN (:ids)`, xsql.PlaceholderDollar)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+//	q, args, err := updatePrice.Bind(map[string]any{"price": 100, "ids": []int{7, 8}})
+//	// q    => UPDATE items SET price = $1 WHERE id IN ($2,$3)
+//	// args => [100 7 8]
+func PrepareNamed(query string, ph Placeholder) (*NamedStmt, error) {
+	conds, err := findConditions(query)
+	if err != nil {
+		return nil, err
+	}
+	toks, err := findNamedParams(query)
+	if err != nil {
+		return nil, err
+	}
+	s := &NamedStmt{query: query, ph: ph, named: len(toks) > 0 || len(conds) > 0, conds: conds}
+	qs, err := findQuestionMarks(query)
+	if err != nil {
+		return nil, err
+	}
+	toks = append(toks, qs...)
+	sort.Slice(toks, func(i, j int) bool { return toks[i].start < toks[j].start })
+
+	s.toks = toks
+	s.segs = make([]string, 0, len(toks)+1)
+	last := 0
+	for _, t := range toks {
+		s.segs = append(s.segs, query[last:t.start])
+		last = t.end
+	}
+	s.segs = append(s.segs, query[last:])
+	return s, nil
+}
+
+// Bind resolves params and returns the final SQL and positional args, with the
+// same rules as [Rebind]: one struct or map[string]any, or several merged,
+// bind :name parameters; otherwise params are treated as positional args.
+func (s *NamedStmt) Bind(params ...any) (string, []any, error) {
+	params, opts := splitOpts(params)
+	return s.bind(params, newCallConfig(opts))
+}
+
+func (s *NamedStmt) bind(params []any, cfg callConfig) (string, []any, error) {
+	if named, err := checkNamedArgs(params); err != nil {
+		return "", nil, err
+	} else if named {
+		return s.source(), params, nil
+	}
+	if len(params) > 1 && s.named && allBindable(params) {
+		if cfg.strictParams {
+			for _, p := range params {
+				if err := checkUnusedParams(s.toks, s.conds, p); err != nil {
+					return "", nil, err
+				}
+			}
+			cfg.strictParams = false
+		}
+		merged, err := mergeParams(params)
+		if err != nil {
+			return "", nil, err
+		}
+		params = []any{merged}
+	}
+	var lut *paramLookup
+	args := params
+	if len(params) == 1 && looksBindable(params[0]) {
+		args = nil
+		if s.named {
+			var err error
+			if lut, err = buildParamLookup(params[0]); err != nil {
+				return "", nil, err
+			}
+			if cfg.strictParams {
+				if err := checkUnusedParams(s.toks, s.conds, params[0]); err != nil {
+					return "", nil, err
+				}
+			}
+			args = make([]any, 0, len(s.toks))
+			if len(s.conds) > 0 {
+				// Removing sections changes the text, so the remainder is
+				// tokenized again; its own markers are already resolved.
+				if q := applyConditions(s.query, s.conds, lut); q != s.query {
+					cs, err := PrepareNamed(q, s.ph)
+					if err != nil {
+						return "", nil, err
+					}
+					s = cs
+				}
+			}
+		}
+	}
+
+	size := 0
+	for _, seg := range s.segs {
+		size += len(seg)
+	}
+	out := make([]byte, 0, size+4*len(s.toks))
+	n := 1
+	var names map[string]string  // native mode: emitted name -> source parameter
+	var reused map[string]string // ?N mode: token text -> placeholders emitted for it
+	for i, t := range s.toks {
+		out = append(out, s.segs[i]...)
+		switch {
+		case t.esc:
+			out = appendEscape(out, s.ph)
+		case t.name == "" && lut != nil && s.ph.native():
+			return "", nil, fmt.Errorf("xsql: named bind: cannot mix ? with native named parameters")
+		case t.name == "":
+			out = appendPlaceholder(out, s.ph, n)
+			n++
+		case lut == nil:
+			// Positional params: leave :name tokens untouched, as Rebind does.
+			out = append(out, s.query[t.start:t.end]...)
+		default:
+			// ?N placeholders let a repeated parameter refer back to its
+			// first placeholders instead of passing its value again.
+			var key string
+			if s.ph == PlaceholderQuestionNum {
+				key = strings.ToLower(s.query[t.start:t.end])
+				if prev, ok := reused[key]; ok {
+					out = append(out, prev...)
+					continue
+				}
+			}
+			val, ok := lut.lookup(t.name)
+			if !ok {
+				return "", nil, fmt.Errorf("xsql: named bind: missing value for :%s", t.name)
+			}
+			if s.ph.native() && names == nil {
+				names = make(map[string]string)
+			}
+			start := len(out)
+			var err error
+			switch {
+			case t.cols != nil:
+				var rows [][]any
+				if rows, err = rowValues(t.name, val, t.cols); err == nil {
+					out, args, n, err = appendRows(out, args, n, s.ph, t.name, rows, t.cols, names)
+				}
+			case s.ph.native():
+				out, args, err = appendNative(out, args, s.ph, t.name, val, names)
+			default:
+				out, args, n, err = appendBound(out, args, n, s.ph, t.name, val)
+			}
+			if err != nil {
+				return "", nil, err
+			}
+			if key != "" {
+				if reused == nil {
+					reused = make(map[string]string)
+				}
+				reused[key] = string(out[start:])
+			}
+		}
+	}
+	out = append(out, s.segs[len(s.segs)-1]...)
+	return string(out), args, nil
+}
+
+// source returns the original query text.
+func (s *NamedStmt) source() string { return s.query }
+
+// Exec binds params and executes the statement on e.
+func (s *NamedStmt) Exec(ctx context.Context, e Execer, params ...any) (sql.Result, error) {
+	params, opts := splitOpts(params)
+	q, args, err := s.bind(params, newCallConfig(opts))
+	if err != nil {
+		return nil, err
+	}
+	return Exec(ctx, e, q, appendOpts(args, opts)...)
+}
+
+// appendBound appends the placeholder(s) for val, numbered from n, and val's
+// argument(s), converted by bindArg. Slices and arrays (except []byte and
+// Valuers) expand to one placeholder per element; empty ones become NULL. A
+// slice of slices expands to one parenthesized tuple per element, for
+// row-value IN lists.
+func appendBound(out []byte, args []any, n int, ph Placeholder, name string, val any) ([]byte, []any, int, error) {
+	rv := reflect.ValueOf(val)
+	if !isSliceOrArray(rv) {
+		v, err := bindArg(name, val)
+		if err != nil {
+			return nil, nil, 0, err
+		}
+		out = appendPlaceholder(out, ph, n)
+		return out, append(args, v), n + 1, nil
+	}
+	l := rv.Len()
+	if l == 0 {
+		return append(out, "NULL"...), args, n, nil
+	}
+	rows, err := tupleValues(name, rv)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	if rows != nil {
+		return appendRows(out, args, n, ph, name, rows, nil, nil)
+	}
+	for i := 0; i < l; i++ {
+		if i > 0 {
+			out = append(out, ',')
+		}
+		v, err := bindArg(name, rv.Index(i).Interface())
+		if err != nil {
+			return nil, nil, 0, err
+		}
+		out = appendPlaceholder(out, ph, n)
+		args = append(args, v)
+		n++
+	}
+	return out, args, n, nil
+}
+
+// appendRows appends one parenthesized group of placeholders per row,
+// numbered from n, and the row values, each bound as one value even if it is
+// a slice. In native mode the values are named name_row_col (rows_1_email),
+// or name_row_index for rows without cols (pairs_1_2).
+func appendRows(out []byte, args []any, n int, ph Placeholder, name string, rows [][]any, cols []string, names map[string]string) ([]byte, []any, int, error) {
+	var err error
+	for i, row := range rows {
+		if i > 0 {
+			out = append(out, ',')
+		}
+		out = append(out, '(')
+		for j, v := range row {
+			if j > 0 {
+				out = append(out, ',')
+			}
+			if ph.native() {
+				col := strconv.Itoa(j + 1)
+				if cols != nil {
+					col = cols[j]
+				}
+				nn := strings.ReplaceAll(name+"_"+strconv.Itoa(i+1)+"_"+col, ".", "_")
+				if out, args, err = appendNamed(out, args, ph, name, nn, v, names); err != nil {
+					return nil, nil, 0, err
+				}
+				continue
+			}
+			if v, err = bindArg(name, v); err != nil {
+				return nil, nil, 0, err
+			}
+			out = appendPlaceholder(out, ph, n)
+			args = append(args, v)
+			n++
+		}
+		out = append(out, ')')
+	}
+	return out, args, n, nil
+}
+
+// appendNative appends @name or :name for val and, the first time name is
+// seen, a sql.Named argument. Slices and arrays (except []byte) expand to
+// name_1, name_2, …, and slices of slices to tuples of name_1_1, name_1_2, …;
+// empty ones become NULL.
+func appendNative(out []byte, args []any, ph Placeholder, name string, val any, names map[string]string) ([]byte, []any, error) {
+	base := strings.ReplaceAll(name, ".", "_") // :filter.status -> @filter_status
+	rv := reflect.ValueOf(val)
+	if !isSliceOrArray(rv) {
+		return appendNamed(out, args, ph, name, base, val, names)
+	}
+	l := rv.Len()
+	if l == 0 {
+		return append(out, "NULL"...), args, nil
+	}
+	rows, err := tupleValues(name, rv)
+	if err != nil {
+		return nil, nil, err
+	}
+	if rows != nil {
+		out, args, _, err = appendRows(out, args, 0, ph, name, rows, nil, names)
+		return out, args, err
+	}
+	for i := 0; i < l; i++ {
+		if i > 0 {
+			out = append(out, ',')
+		}
+		if out, args, err = appendNamed(out, args, ph, name, base+"_"+strconv.Itoa(i+1), rv.Index(i).Interface(), names); err != nil {
+			return nil, nil, err
+		}
+	}
+	return out, args, nil
+}
+
+// appendNamed appends @n or :n for the value v of parameter src and, the first
+// time n is seen, sql.Named(n, v). names records the source of each emitted
+// name, case-insensitively, to reject expansions that collide with another
+// parameter.
+func appendNamed(out []byte, args []any, ph Placeholder, src, n string, v any, names map[string]string) ([]byte, []any, error) {
+	key := strings.ToLower(n)
+	if prev, ok := names[key]; ok {
+		if prev != strings.ToLower(src) {
+			return nil, nil, fmt.Errorf("xsql: named bind: :%s expands to %s, which collides with :%s", src, n, prev)
+		}
+	} else {
+		bv, err := bindArg(src, v)
+		if err != nil {
+			return nil, nil, err
+		}
+		names[key] = strings.ToLower(src)
+		args = append(args, sql.Named(n, bv))
+	}
+	if ph == PlaceholderColonName {
+		out = append(out, ':')
+	} else {
+		out = append(out, '@')
+	}
+	return append(out, n...), args, nil
+}
+
+// findQuestionMarks returns the '?' placeholders outside quotes, comments, and
+// dollar-quoted bodies as unnamed tokens, and "??" escapes as tokens with esc
+// set. Operators beginning with '?' (see SetQuestionOperators) are skipped.
+func findQuestionMarks(query string) ([]nameToken, error) {
+	var out []nameToken
+	i := 0
+	for i < len(query) {
+		j, err := skipNonCode(query, i)
+		if err != nil {
+			return nil, err
+		}
+		if j > i {
+			i = j
+			continue
+		}
+		if query[i] != '?' {
+			i++
+			continue
+		}
+		kind, n := scanQuestion(query, i)
+		switch kind {
+		case questionPlaceholder:
+			out = append(out, nameToken{start: i, end: i + n})
+		case questionEscape:
+			out = append(out, nameToken{start: i, end: i + n, esc: true})
+		}
+		i += n
+	}
+	return out, nil
+}
+
+// appendEscape appends the text for a "??" escape: a literal '?' when '?'
+// is not the placeholder, or the escape unchanged when it is.
+func appendEscape(out []byte, ph Placeholder) []byte {
+	if ph == PlaceholderQuestion {
+		return append(out, "??"...)
+	}
+	return append(out, '?')
+}
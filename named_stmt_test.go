@@ -0,0 +1,315 @@
+package xsql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestPrepareNamed_MatchesRebind(t *testing.T) {
+	a := argStruct{baseEmb: baseEmb{Tenant: 1}, Status: "on", IDs: []int64{4, 5}}
+	cases := []struct {
+		query  string
+		params []any
+	}{
+		{`SELECT * FROM t WHERE tenant=:tenant AND status=:status AND id IN (:ids)`, []any{a}},
+		{`SELECT :p, ':p', "x:p", :p -- :p
+/* :p */ $q$ :p $q$ ::int`, []any{map[string]any{"p": 1}}},
+		{`SELECT 1 WHERE id IN (:ids)`, []any{map[string]any{"ids": []int{}}}},
+		{`SELECT ? , ':a', ?`, []any{1, 2}},
+		{`SELECT :a, ?`, []any{map[string]any{"a": "x"}}},
+		{`SELECT 1`, []any{map[string]any{"a": 1}}},
+		{`SELECT :a`, []any{1, 2}},
+	}
+	for _, ph := range []Placeholder{PlaceholderQuestion, PlaceholderDollar, PlaceholderAtP, PlaceholderColonNum} {
+		for i, tc := range cases {
+			wantQ, wantArgs, wantErr := Rebind(tc.query, ph, tc.params...)
+			s, err := PrepareNamed(tc.query, ph)
+			if err != nil {
+				t.Fatalf("ph=%d case %d: PrepareNamed: %v", ph, i, err)
+			}
+			gotQ, gotArgs, gotErr := s.Bind(tc.params...)
+			if (wantErr == nil) != (gotErr == nil) {
+				t.Fatalf("ph=%d case %d: err mismatch: rebind=%v bind=%v", ph, i, wantErr, gotErr)
+			}
+			if gotQ != wantQ {
+				t.Fatalf("ph=%d case %d: sql mismatch:\n got=%q\nwant=%q", ph, i, gotQ, wantQ)
+			}
+			if len(gotArgs) != 0 || len(wantArgs) != 0 {
+				eqSlice(t, gotArgs, wantArgs, "args")
+			}
+		}
+	}
+}
+
+func TestPrepareNamed_Errors(t *testing.T) {
+	if _, err := PrepareNamed(`SELECT 'open`, PlaceholderDollar); err == nil {
+		t.Fatal("expected tokenizer error")
+	}
+	s, err := PrepareNamed(`SELECT :missing`, PlaceholderDollar)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := s.Bind(map[string]any{"other": 1}); err == nil {
+		t.Fatal("expected missing value error")
+	}
+}
+
+func TestNamedStmt_Exec(t *testing.T) {
+	s, err := PrepareNamed(`UPDATE items SET price=:p WHERE id IN (:ids)`, PlaceholderAtP)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ex := &execer{}
+	if _, err := s.Exec(context.Background(), ex, map[string]any{"p": 10, "ids": []int{1, 2}}); err != nil {
+		t.Fatalf("Exec: %v", err)
+	}
+	eq(t, ex.lastQuery, `UPDATE items SET price=@p1 WHERE id IN (@p2,@p3)`, "sql")
+	if !reflect.DeepEqual(ex.lastArgs, []any{10, 1, 2}) {
+		t.Fatalf("args: %#v", ex.lastArgs)
+	}
+
+	sentinel := errors.New("boom")
+	ex.err = sentinel
+	if _, err := s.Exec(context.Background(), ex, map[string]any{"p": 1, "ids": 1}); !errors.Is(err, sentinel) {
+		t.Fatalf("want exec error, got %v", err)
+	}
+	if _, err := s.Exec(context.Background(), ex, map[string]any{}); err == nil {
+		t.Fatal("expected bind error")
+	}
+}
+
+func TestRebind_NativeNamed(t *testing.T) {
+	params := map[string]any{"a": 1, "ids": []int{7, 8}, "e": []int{}}
+	q, args, err := Rebind(`SELECT * FROM t WHERE a=:a AND id IN (:ids) AND x NOT IN (:e) OR b=:a -- :a`, PlaceholderAtName, params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `SELECT * FROM t WHERE a=@a AND id IN (@ids_1,@ids_2) AND x NOT IN (NULL) OR b=@a -- :a`; q != want {
+		t.Fatalf("sql mismatch:\n got=%q\nwant=%q", q, want)
+	}
+	eqSlice(t, args, []any{sql.Named("a", 1), sql.Named("ids_1", 7), sql.Named("ids_2", 8)}, "args")
+
+	q, args, err = Rebind(`BEGIN proc(:id, :out); END;`, PlaceholderColonName, map[string]any{"id": 5, "out": sql.Out{}})
+	if err != nil || q != `BEGIN proc(:id, :out); END;` {
+		t.Fatalf("oracle: %q, %v", q, err)
+	}
+	eqSlice(t, args, []any{sql.Named("id", 5), sql.Named("out", sql.Out{})}, "oracle args")
+
+	s, err := PrepareNamed(`SELECT :a`, PlaceholderAtName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q, _, err := s.Bind(map[string]any{"a": 1}); err != nil || q != `SELECT @a` {
+		t.Fatalf("NamedStmt.Bind: %q, %v", q, err)
+	}
+
+	// Positional params are numbered as with PlaceholderAtP.
+	if q, _, _ := Rebind(`a=? AND b=?`, PlaceholderAtName, 1, 2); q != `a=@p1 AND b=@p2` {
+		t.Fatalf("positional: %q", q)
+	}
+	if _, _, err := Rebind(`SELECT :a, ?`, PlaceholderAtName, map[string]any{"a": 1}); err == nil {
+		t.Fatal("expected error mixing ? with native named parameters")
+	}
+	if _, _, err := Rebind(`SELECT :ids, :ids_1`, PlaceholderAtName, map[string]any{"ids": []int{1}, "ids_1": 2}); err == nil {
+		t.Fatal("expected expansion collision error")
+	}
+}
+
+func TestRebind_QuestionOperatorsAndEscape(t *testing.T) {
+	q := `SELECT * FROM docs WHERE data ?| :keys AND data ?& :all AND data ?? :one AND s = :s||'x' AND '?' = '??'`
+	params := map[string]any{"keys": []string{"a", "b"}, "all": "c", "one": "d", "s": "e"}
+	want := map[Placeholder]string{
+		PlaceholderQuestion: `SELECT * FROM docs WHERE data ?| ?,? AND data ?& ? AND data ?? ? AND s = ?||'x' AND '?' = '??'`,
+		PlaceholderDollar:   `SELECT * FROM docs WHERE data ?| $1,$2 AND data ?& $3 AND data ? $4 AND s = $5||'x' AND '?' = '??'`,
+	}
+	for ph, w := range want {
+		got, args, err := Rebind(q, ph, params)
+		if err != nil || got != w || len(args) != 5 {
+			t.Fatalf("ph=%d Rebind:\n got=%q\nwant=%q (%v, %v)", ph, got, w, args, err)
+		}
+		s, err := PrepareNamed(q, ph)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, _, err := s.Bind(params); err != nil || got != w {
+			t.Fatalf("ph=%d NamedStmt.Bind:\n got=%q\nwant=%q (%v)", ph, got, w, err)
+		}
+		if got := s.source(); got != q {
+			t.Fatalf("source = %q", got)
+		}
+	}
+
+	// Positional queries: escapes and operators are not counted as args.
+	got, _, err := Rebind(`SELECT data ?| ? , data ?? ?`, PlaceholderDollar, 1, 2)
+	if err != nil || got != `SELECT data ?| $1 , data ? $2` {
+		t.Fatalf("positional: %q, %v", got, err)
+	}
+	if got, args, err := In(`SELECT data ?& ? AND data ?? ?`, []string{"a", "b"}, "c"); err != nil || got != `SELECT data ?& ?,? AND data ?? ?` || len(args) != 3 {
+		t.Fatalf("In: %q %v %v", got, args, err)
+	}
+
+	defer SetQuestionOperators("?|", "?&")
+	SetQuestionOperators("?-|")
+	if got, _, _ := Rebind(`SELECT a ?-| b, c ?| ?`, PlaceholderDollar, 1, 2); got != `SELECT a ?-| b, c $1| $2` {
+		t.Fatalf("custom operators: %q", got)
+	}
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected panic for an operator not beginning with '?'")
+		}
+	}()
+	SetQuestionOperators("|?")
+}
+
+func TestRebind_RowList(t *testing.T) {
+	type user struct {
+		Email string `db:"email"`
+		Name  string `db:"name"`
+		Tags  []int  `db:"tags"`
+	}
+	users := []user{{"a@x", "A", []int{1, 2}}, {"b@x", "B", nil}}
+	q := `INSERT INTO users (email, name, tags) VALUES :users( email,name , tags) RETURNING id`
+	params := map[string]any{"users": users}
+
+	want := map[Placeholder]string{
+		PlaceholderQuestion: `INSERT INTO users (email, name, tags) VALUES (?,?,?),(?,?,?) RETURNING id`,
+		PlaceholderDollar:   `INSERT INTO users (email, name, tags) VALUES ($1,$2,$3),($4,$5,$6) RETURNING id`,
+	}
+	for ph, w := range want {
+		got, args, err := Rebind(q, ph, params)
+		if err != nil || got != w {
+			t.Fatalf("ph=%d Rebind:\n got=%q\nwant=%q (%v)", ph, got, w, err)
+		}
+		eqSlice(t, args, []any{"a@x", "A", []int{1, 2}, "b@x", "B", []int(nil)}, "args")
+		s, err := PrepareNamed(q, ph)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got2, args2, err := s.Bind(params)
+		if err != nil || got2 != w {
+			t.Fatalf("ph=%d NamedStmt.Bind: %q (%v)", ph, got2, err)
+		}
+		eqSlice(t, args2, args, "NamedStmt args")
+	}
+
+	got, args, err := Rebind(`INSERT INTO t (a, b) VALUES :rows(a, b)`, PlaceholderAtName,
+		map[string]any{"rows": []map[string]any{{"a": 1, "b": 2}, {"a": 3, "b": 4}}})
+	if err != nil || got != `INSERT INTO t (a, b) VALUES (@rows_1_a,@rows_1_b),(@rows_2_a,@rows_2_b)` {
+		t.Fatalf("native: %q, %v", got, err)
+	}
+	eqSlice(t, args, []any{sql.Named("rows_1_a", 1), sql.Named("rows_1_b", 2), sql.Named("rows_2_a", 3), sql.Named("rows_2_b", 4)}, "native args")
+
+	// Without a well-formed column list, the parenthesis is ordinary SQL.
+	if got, _, err := Rebind(`SELECT :fn(1), :a ()`, PlaceholderDollar, map[string]any{"fn": 1, "a": 2}); err != nil || got != `SELECT $1(1), $2 ()` {
+		t.Fatalf("plain param: %q, %v", got, err)
+	}
+	for name, p := range map[string]any{
+		"empty":       map[string]any{"users": []user{}},
+		"not a slice": map[string]any{"users": users[0]},
+		"missing col": map[string]any{"users": []map[string]any{{"email": "x", "name": "y"}}},
+		"bad row":     map[string]any{"users": []int{1}},
+	} {
+		if _, _, err := Rebind(q, PlaceholderQuestion, p); err == nil {
+			t.Fatalf("%s: expected error", name)
+		}
+	}
+
+	// RebindChunked splits row lists into batches of whole rows.
+	many := make([]user, 5)
+	stmts, err := RebindChunked(q, PlaceholderDollar, 7, map[string]any{"users": many})
+	if err != nil || len(stmts) != 3 || len(stmts[0].Args) != 6 || len(stmts[2].Args) != 3 {
+		t.Fatalf("chunked: %#v, %v", stmts, err)
+	}
+}
+
+func TestRebind_RowTuples(t *testing.T) {
+	type key struct {
+		ID     int64  `db:"id"`
+		Tenant string `db:"tenant"`
+	}
+	const q = `SELECT * FROM t WHERE (id, tenant) IN (:keys)`
+	pairs := map[string]any{"keys": [][]any{{1, "a"}, {2, "b"}}}
+
+	for ph, w := range map[Placeholder]string{
+		PlaceholderQuestion: `SELECT * FROM t WHERE (id, tenant) IN ((?,?),(?,?))`,
+		PlaceholderDollar:   `SELECT * FROM t WHERE (id, tenant) IN (($1,$2),($3,$4))`,
+	} {
+		got, args, err := Rebind(q, ph, pairs)
+		if err != nil || got != w {
+			t.Fatalf("ph=%d:\n got=%q\nwant=%q (%v)", ph, got, w, err)
+		}
+		eqSlice(t, args, []any{1, "a", 2, "b"}, "args")
+		if got, _, err := bindNamedParams(q, pairs); err != nil || got != `SELECT * FROM t WHERE (id, tenant) IN ((?,?),(?,?))` {
+			t.Fatalf("uncached: %q, %v", got, err)
+		}
+	}
+
+	// Fixed-size arrays work as tuples; structs use a column list.
+	got, args, err := Rebind(q, PlaceholderDollar, map[string]any{"keys": [][2]int{{1, 2}}})
+	if err != nil || got != `SELECT * FROM t WHERE (id, tenant) IN (($1,$2))` || len(args) != 2 {
+		t.Fatalf("arrays: %q %v %v", got, args, err)
+	}
+	got, _, err = Rebind(`SELECT * FROM t WHERE (id, tenant) IN (:keys(id, tenant))`, PlaceholderDollar,
+		map[string]any{"keys": []key{{1, "a"}, {2, "b"}}})
+	if err != nil || got != `SELECT * FROM t WHERE (id, tenant) IN (($1,$2),($3,$4))` {
+		t.Fatalf("structs: %q, %v", got, err)
+	}
+
+	got, args, err = Rebind(q, PlaceholderAtName, pairs)
+	if err != nil || got != `SELECT * FROM t WHERE (id, tenant) IN ((@keys_1_1,@keys_1_2),(@keys_2_1,@keys_2_2))` {
+		t.Fatalf("native: %q, %v", got, err)
+	}
+	eqSlice(t, args, []any{sql.Named("keys_1_1", 1), sql.Named("keys_1_2", "a"), sql.Named("keys_2_1", 2), sql.Named("keys_2_2", "b")}, "native args")
+
+	// [][]byte is a list of scalars.
+	if got, _, _ := Rebind(`IN (:b)`, PlaceholderDollar, map[string]any{"b": [][]byte{{1}, {2}}}); got != `IN ($1,$2)` {
+		t.Fatalf("bytes: %q", got)
+	}
+
+	for name, keys := range map[string]any{
+		"ragged": [][]any{{1, "a"}, {2}},
+		"mixed":  []any{[]int{1, 2}, 3},
+		"empty":  [][]int{{}},
+	} {
+		if _, _, err := Rebind(q, PlaceholderDollar, map[string]any{"keys": keys}); err == nil {
+			t.Fatalf("%s: expected error", name)
+		}
+	}
+
+	stmts, err := RebindChunked(q, PlaceholderDollar, 4, map[string]any{"keys": [][]int{{1, 1}, {2, 2}, {3, 3}}})
+	if err != nil || len(stmts) != 2 || len(stmts[0].Args) != 4 || len(stmts[1].Args) != 2 {
+		t.Fatalf("chunked: %#v, %v", stmts, err)
+	}
+}
+
+func TestRebind_QuestionNum(t *testing.T) {
+	const q = `SELECT * FROM t WHERE (a = :a OR b = :a) AND id IN (:ids) AND c = ? AND d NOT IN (:IDS) AND e = :a`
+	params := map[string]any{"a": 1, "ids": []int{2, 3}}
+	got, args, err := Rebind(q, PlaceholderQuestionNum, params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `SELECT * FROM t WHERE (a = ?1 OR b = ?1) AND id IN (?2,?3) AND c = ?4 AND d NOT IN (?2,?3) AND e = ?1`; got != want {
+		t.Fatalf("sql mismatch:\n got=%q\nwant=%q", got, want)
+	}
+	eqSlice(t, args, []any{1, 2, 3}, "args")
+
+	got, args, err = Rebind(`INSERT INTO t VALUES :r(a, b) RETURNING :x, :x`, PlaceholderQuestionNum,
+		map[string]any{"r": []map[string]any{{"a": 1, "b": 2}}, "x": 3})
+	if err != nil || got != `INSERT INTO t VALUES (?1,?2) RETURNING ?3, ?3` || len(args) != 3 {
+		t.Fatalf("rows: %q %v %v", got, args, err)
+	}
+
+	// Repeated parameters count once when splitting.
+	stmts, err := RebindChunked(`SELECT :ids UNION SELECT :ids`, PlaceholderQuestionNum, 2, map[string]any{"ids": []int{1, 2, 3}})
+	if err != nil || len(stmts) != 2 || stmts[0].Query != `SELECT ?1,?2 UNION SELECT ?1,?2` {
+		t.Fatalf("chunked: %#v, %v", stmts, err)
+	}
+
+	if got, _, _ := Rebind(`a = ? AND b = ?`, PlaceholderQuestionNum, 1, 2); got != `a = ?1 AND b = ?2` {
+		t.Fatalf("positional: %q", got)
+	}
+}
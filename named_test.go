@@ -4,6 +4,7 @@ package xsql
 import (
 	"context"
 	"database/sql"
+	"database/sql/driver"
 	"errors"
 	"reflect"
 	"regexp"
@@ -173,6 +174,65 @@ func TestRebind_PositionalPassthrough_Oracle(t *testing.T) {
 	eqSlice(t, args, []any{"aa", 2, 3}, "positional passthrough")
 }
 
+func TestRebind_SQLNamedPassthrough(t *testing.T) {
+	in := `SELECT * FROM t WHERE a=@a AND b=@b`
+	out, args, err := Rebind(in, PlaceholderAtP, sql.Named("a", 1), sql.Named("b", "x"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	eq(t, out, in, "query unchanged")
+	eqSlice(t, args, []any{sql.Named("a", 1), sql.Named("b", "x")}, "named args kept")
+
+	// A single sql.NamedArg is not mistaken for a struct of named params.
+	out, args, err = Rebind(`SELECT :name FROM dual`, PlaceholderColonNum, sql.Named("name", "v"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	eq(t, out, `SELECT :name FROM dual`, "oracle native named")
+	eqSlice(t, args, []any{sql.Named("name", "v")}, "single named arg kept")
+}
+
+func TestRebind_MixedNamedAndPositional_Error(t *testing.T) {
+	_, _, err := Rebind(`SELECT ? , @b`, PlaceholderAtP, 1, sql.Named("b", 2))
+	if !errors.Is(err, ErrMixedArgs) {
+		t.Fatalf("want ErrMixedArgs, got %v", err)
+	}
+	ns, err := PrepareNamed(`SELECT ?, @b`, PlaceholderAtP)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := ns.Bind(sql.Named("b", 2), 1); !errors.Is(err, ErrMixedArgs) {
+		t.Fatalf("NamedStmt.Bind: want ErrMixedArgs, got %v", err)
+	}
+	q, _, err := ns.Bind(sql.Named("b", 2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	eq(t, q, `SELECT ?, @b`, "NamedStmt passthrough")
+}
+
+func TestQueryGetExec_MixedNamedArgs_Error(t *testing.T) {
+	ctx := context.Background()
+	q := &querier{}
+	if _, err := Query[int](ctx, q, `SELECT @a, ?`, sql.Named("a", 1), 2); !errors.Is(err, ErrMixedArgs) {
+		t.Fatalf("Query: want ErrMixedArgs, got %v", err)
+	}
+	if _, err := Get[int](ctx, q, `SELECT @a, ?`, 2, sql.Named("a", 1)); !errors.Is(err, ErrMixedArgs) {
+		t.Fatalf("Get: want ErrMixedArgs, got %v", err)
+	}
+	if q.lastQuery != "" {
+		t.Fatalf("querier should not be called, got %q", q.lastQuery)
+	}
+	e := &execer{}
+	if _, err := Exec(ctx, e, `UPDATE t SET a=@a WHERE id=?`, sql.Named("a", 1), 2); !errors.Is(err, ErrMixedArgs) {
+		t.Fatalf("Exec: want ErrMixedArgs, got %v", err)
+	}
+	if _, err := Exec(ctx, e, `UPDATE t SET a=@a`, sql.Named("a", 1)); err != nil {
+		t.Fatal(err)
+	}
+	eqSlice(t, e.lastArgs, []any{sql.Named("a", 1)}, "Exec forwards named args")
+}
+
 func TestRebind_NoParams_QuestionUnchanged(t *testing.T) {
 	in := "SELECT ? AS x, '--' AS y"
 	out, args, err := Rebind(in, PlaceholderQuestion)
@@ -530,12 +590,90 @@ func TestNamedQuery_PositionalPassthrough_UsesQuerier(t *testing.T) {
 	eqSlice(t, q.lastArgs, []any{"A", "B"}, "NamedQuery passthrough args")
 }
 
+func TestNamedGet(t *testing.T) {
+	var gotQuery string
+	var gotArgs []driver.NamedValue
+	db := newTestDB(t, func(q string, args []driver.NamedValue) ([]string, [][]driver.Value, error) {
+		gotQuery, gotArgs = q, args
+		if args[0].Value == int64(0) {
+			return []string{"id", "email"}, nil, nil
+		}
+		return []string{"id", "email"}, [][]driver.Value{{int64(7), "a@x"}}, nil
+	})
+	defer func() { _ = db.Close() }()
+	ctx := context.Background()
+
+	type User struct {
+		ID    int64  `db:"id"`
+		Email string `db:"email"`
+	}
+	u, err := NamedGet[User](ctx, db, PlaceholderDollar, `SELECT id, email FROM users WHERE id=:id`, map[string]any{"id": 7})
+	if err != nil || u != (User{7, "a@x"}) {
+		t.Fatalf("NamedGet = %+v, %v", u, err)
+	}
+	if gotQuery != "SELECT id, email FROM users WHERE id=$1" || len(gotArgs) != 1 || gotArgs[0].Value != int64(7) {
+		t.Fatalf("sent %q %v", gotQuery, gotArgs)
+	}
+	if _, err := NamedGet[User](ctx, db, PlaceholderDollar, `SELECT id, email FROM users WHERE id=:id`, map[string]any{"id": 0}); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("expected sql.ErrNoRows, got %v", err)
+	}
+	if _, err := NamedGet[User](ctx, db, PlaceholderDollar, `SELECT :missing`, map[string]any{}); err == nil {
+		t.Fatal("expected bind error")
+	}
+}
+
 func TestPlaceholderFor(t *testing.T) {
 	eq(t, PlaceholderFor("pgx"), PlaceholderDollar, "pgx")
 	eq(t, PlaceholderFor("lib/pq"), PlaceholderDollar, "lib/pq")
 	eq(t, PlaceholderFor("sqlserver"), PlaceholderAtP, "sqlserver")
 	eq(t, PlaceholderFor("godror"), PlaceholderColonNum, "oracle")
 	eq(t, PlaceholderFor("mysql"), PlaceholderQuestion, "default")
+	eq(t, PlaceholderFor("sqlite3"), PlaceholderQuestion, "sqlite3")
+	eq(t, PlaceholderFor("sqlite", PreferNumbered()), PlaceholderQuestionNum, "sqlite numbered")
+	eq(t, PlaceholderFor("pgx", PreferNumbered()), PlaceholderDollar, "pgx numbered")
+}
+
+func TestPlaceholderForDB(t *testing.T) {
+	for path, want := range map[string]Placeholder{
+		"github.com/jackc/pgx/v5/stdlib":  PlaceholderDollar,
+		"github.com/lib/pq":               PlaceholderDollar,
+		"github.com/microsoft/go-mssqldb": PlaceholderAtP,
+		"github.com/godror/godror":        PlaceholderColonNum,
+		"github.com/sijms/go-ora/v2":      PlaceholderColonNum,
+		"github.com/go-sql-driver/mysql":  PlaceholderQuestion,
+		"modernc.org/sqlite":              PlaceholderQuestion,
+	} {
+		eq(t, placeholderForPkg(path), want, path)
+	}
+
+	var gotQuery string
+	db := newTestDB(t, func(q string, _ []driver.NamedValue) ([]string, [][]driver.Value, error) {
+		gotQuery = q
+		return []string{"x"}, [][]driver.Value{{int64(1)}}, nil
+	})
+	defer func() { _ = db.Close() }()
+	ctx := context.Background()
+	eq(t, PlaceholderForDB(db), PlaceholderQuestion, "test driver")
+
+	if _, err := NamedQueryAuto[int64](ctx, db, `SELECT x FROM t WHERE a=:a`, map[string]any{"a": 1}); err != nil || gotQuery != "SELECT x FROM t WHERE a=?" {
+		t.Fatalf("NamedQueryAuto sent %q, %v", gotQuery, err)
+	}
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = conn.Close() }()
+	if _, err := NamedExecAuto(ctx, conn, `DELETE FROM t WHERE a=:a`, map[string]any{"a": 1}); err != nil || gotQuery != "DELETE FROM t WHERE a=?" {
+		t.Fatalf("NamedExecAuto sent %q, %v", gotQuery, err)
+	}
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = tx.Rollback() }()
+	if _, err := NamedExecAuto(ctx, tx, `DELETE FROM t`); err == nil {
+		t.Fatal("expected detection error for *sql.Tx")
+	}
 }
 
 func TestIsSliceOrArray(t *testing.T) {
@@ -552,3 +690,272 @@ func TestIsSliceOrArray(t *testing.T) {
 		t.Fatalf("invalid value should not expand")
 	}
 }
+
+type testCode string
+
+func (c testCode) Value() (driver.Value, error) {
+	if c == "" {
+		return nil, errors.New("empty code")
+	}
+	return "code-" + string(c), nil
+}
+
+type testTags []string
+
+func (t testTags) Value() (driver.Value, error) { return "{" + strings.Join(t, ",") + "}", nil }
+
+func TestRebind_ValuerArgs(t *testing.T) {
+	var nilCode *testCode
+	var nilInt *int
+	params := map[string]any{
+		"codes": []testCode{"a", "b"},
+		"one":   testCode("c"),
+		"tags":  testTags{"x", "y"},
+		"ptrs":  []*testCode{nilCode},
+		"n":     nilInt,
+	}
+	query := `SELECT :codes, :one, :tags, :ptrs, :n`
+	for _, ph := range []Placeholder{PlaceholderQuestion, PlaceholderDollar} {
+		q, args, err := Rebind(query, ph, params)
+		if err != nil {
+			t.Fatal(err)
+		}
+		eqSlice(t, args, []any{"code-a", "code-b", "code-c", "{x,y}", nil, nil}, "args")
+		s, err := PrepareNamed(query, ph)
+		if err != nil {
+			t.Fatal(err)
+		}
+		q2, args2, err := s.Bind(params)
+		if err != nil || q2 != q {
+			t.Fatalf("NamedStmt.Bind: %q vs %q, %v", q2, q, err)
+		}
+		eqSlice(t, args2, args, "NamedStmt args")
+	}
+	_, args, err := Rebind(`SELECT :codes`, PlaceholderAtName, params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	eqSlice(t, args, []any{sql.Named("codes_1", "code-a"), sql.Named("codes_2", "code-b")}, "native args")
+
+	_, _, err = Rebind(`SELECT :codes`, PlaceholderQuestion, map[string]any{"codes": []testCode{"a", ""}})
+	if err == nil || !strings.Contains(err.Error(), ":codes: empty code") {
+		t.Fatalf("expected Valuer error, got %v", err)
+	}
+}
+
+func TestRebind_DotPathParams(t *testing.T) {
+	type Filter struct {
+		Status string `db:"status"`
+		IDs    []int  `db:"ids"`
+	}
+	type User struct {
+		ID int64 `db:"id"`
+	}
+	type Request struct {
+		User   *User          `db:"user"`
+		Filter Filter         `db:"filter"`
+		Extra  map[string]any `db:"extra"`
+	}
+	r := Request{User: &User{ID: 7}, Filter: Filter{Status: "on", IDs: []int{1, 2}}, Extra: map[string]any{"Tag": "x"}}
+	q, args, err := Rebind(`SELECT * FROM t WHERE owner=:user.id AND status=:Filter.Status AND id IN (:filter.ids) AND tag=:extra.tag AND n=:user.id::int.`, PlaceholderDollar, r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `SELECT * FROM t WHERE owner=$1 AND status=$2 AND id IN ($3,$4) AND tag=$5 AND n=$6::int.`; q != want {
+		t.Fatalf("sql mismatch:\n got=%q\nwant=%q", q, want)
+	}
+	eqSlice(t, args, []any{int64(7), "on", 1, 2, "x", int64(7)}, "args")
+
+	// A flat key containing a dot wins over path resolution.
+	_, args, err = Rebind(`SELECT :a.b`, PlaceholderQuestion, map[string]any{"a.b": 1, "a": map[string]any{"b": 2}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	eqSlice(t, args, []any{1}, "flat dotted key")
+
+	if q, _, err := Rebind(`SELECT :user.id`, PlaceholderAtName, r); err != nil || q != `SELECT @user_id` {
+		t.Fatalf("native: %q, %v", q, err)
+	}
+	for _, query := range []string{`SELECT :user.name`, `SELECT :filter.status.x`, `SELECT :nope.id`} {
+		if _, _, err := Rebind(query, PlaceholderQuestion, r); err == nil {
+			t.Fatalf("%s: expected missing value error", query)
+		}
+	}
+	if _, _, err := Rebind(`SELECT :user.id`, PlaceholderQuestion, Request{}); err == nil {
+		t.Fatal("expected missing value error for nil nested pointer")
+	}
+}
+
+func TestFindNamedParams_MySQLAssignmentAndWordColons(t *testing.T) {
+	q := `SELECT @n := @n + 1, @v:=:x, arr[1:2], a:b, JSON_EXTRACT(doc, '$.a:b'), :y`
+	toks, err := findNamedParams(q)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, tk := range toks {
+		names = append(names, tk.name)
+	}
+	if !reflect.DeepEqual(names, []string{"x", "y"}) {
+		t.Fatalf("names = %v", names)
+	}
+	got, args, err := Rebind(q, PlaceholderQuestion, map[string]any{"x": 1, "y": 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `SELECT @n := @n + 1, @v:=?, arr[1:2], a:b, JSON_EXTRACT(doc, '$.a:b'), ?`; got != want {
+		t.Fatalf("sql mismatch:\n got=%q\nwant=%q", got, want)
+	}
+	eqSlice(t, args, []any{1, 2}, "args")
+}
+
+func TestRebind_StrictParams(t *testing.T) {
+	q := `SELECT * FROM t WHERE status = :status AND owner = :user.id`
+	params := map[string]any{"Status": "on", "user": map[string]any{"id": 1}, "ofset": 2, "limt": 3}
+	if _, _, err := Rebind(q, PlaceholderDollar, params); err != nil {
+		t.Fatalf("non-strict: %v", err)
+	}
+	_, _, err := Rebind(q, PlaceholderDollar, params, WithStrictParams())
+	if !errors.Is(err, ErrUnusedParam) || !strings.HasSuffix(err.Error(), ": :limt, :ofset") {
+		t.Fatalf("expected unused params error, got %v", err)
+	}
+	delete(params, "ofset")
+	delete(params, "limt")
+	got, args, err := Rebind(q, PlaceholderDollar, params, WithStrictParams())
+	if err != nil || got != `SELECT * FROM t WHERE status = $1 AND owner = $2` || len(args) != 2 {
+		t.Fatalf("strict: %q %v %v", got, args, err)
+	}
+
+	// Structs are not checked; the named functions and NamedStmt honor the option.
+	type filter struct {
+		Status string `db:"status"`
+		Extra  int    `db:"extra"`
+	}
+	if _, _, err := Rebind(`SELECT :status`, PlaceholderDollar, filter{}, WithStrictParams()); err != nil {
+		t.Fatalf("struct: %v", err)
+	}
+	if _, err := NamedQuery[string](context.Background(), &querier{}, PlaceholderDollar, `SELECT :a`, map[string]any{"a": 1, "b": 2}, WithStrictParams()); !errors.Is(err, ErrUnusedParam) {
+		t.Fatalf("NamedQuery: %v", err)
+	}
+	s, err := PrepareNamed(`SELECT :a`, PlaceholderQuestion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := s.Bind(map[string]any{"a": 1, "b": 2}, WithStrictParams()); !errors.Is(err, ErrUnusedParam) {
+		t.Fatalf("NamedStmt.Bind: %v", err)
+	}
+}
+
+func TestRebind_MultipleSources(t *testing.T) {
+	type scope struct {
+		TenantID int64 `db:"tenant_id"`
+		Limit    int   `db:"limit"`
+	}
+	q := `SELECT * FROM orders WHERE tenant_id=:tenant_id AND status=:status LIMIT :limit`
+	sc := scope{TenantID: 9, Limit: 50}
+	over := map[string]any{"status": "open", "LIMIT": 10}
+	want := `SELECT * FROM orders WHERE tenant_id=$1 AND status=$2 LIMIT $3`
+	got, args, err := Rebind(q, PlaceholderDollar, sc, over)
+	if err != nil || got != want {
+		t.Fatalf("Rebind: %q, %v", got, err)
+	}
+	eqSlice(t, args, []any{int64(9), "open", 10}, "later source wins")
+	_, args, _ = Rebind(q, PlaceholderDollar, over, &sc)
+	eqSlice(t, args, []any{int64(9), "open", 50}, "order matters")
+
+	s, err := PrepareNamed(q, PlaceholderDollar)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, args, err := s.Bind(sc, over); err != nil || got != want || len(args) != 3 {
+		t.Fatalf("NamedStmt.Bind: %q %v %v", got, args, err)
+	}
+
+	// Strict mode checks each map source on its own; struct fields may go unused.
+	if _, _, err := Rebind(q, PlaceholderDollar, sc, map[string]any{"status": "x", "stat": 1}, WithStrictParams()); !errors.Is(err, ErrUnusedParam) {
+		t.Fatalf("strict: %v", err)
+	}
+	if _, _, err := Rebind(`SELECT :status`, PlaceholderDollar, sc, over, WithStrictParams()); !errors.Is(err, ErrUnusedParam) {
+		t.Fatalf("strict, unused map key: %v", err)
+	}
+
+	// Without :name tokens, several values stay positional, even structs.
+	ts := time.Unix(0, 0)
+	got, args, err = Rebind(`SELECT ? , ?`, PlaceholderDollar, ts, ts)
+	if err != nil || got != `SELECT $1 , $2` || len(args) != 2 {
+		t.Fatalf("positional: %q %v %v", got, args, err)
+	}
+}
+
+func TestDetectPlaceholder(t *testing.T) {
+	for q, want := range map[string]Placeholder{
+		`SELECT * FROM t WHERE a = $1 AND b = $2`:          PlaceholderDollar,
+		`SELECT * FROM t WHERE a = @p1`:                    PlaceholderAtP,
+		`SELECT * FROM t WHERE a = @P1`:                    PlaceholderAtP,
+		`SELECT * FROM t WHERE a = :1`:                     PlaceholderColonNum,
+		`SELECT * FROM t WHERE a = ?`:                      PlaceholderQuestion,
+		`SELECT * FROM t WHERE a = ?1 OR b = ?1`:           PlaceholderQuestionNum,
+		`SELECT '$1', "@p1" /* :1 */, x::int, $$ $1 $$, ?`: PlaceholderQuestion,
+		`SELECT data ?| $1, arr[1:2], @pname`:              PlaceholderDollar,
+		`SELECT :name, @v`:                                 PlaceholderQuestion,
+		`SELECT 1`:                                         PlaceholderQuestion,
+		`SELECT 'unterminated`:                             PlaceholderQuestion,
+	} {
+		eq(t, DetectPlaceholder(q), want, q)
+	}
+}
+
+func TestParseNamed(t *testing.T) {
+	q := `SELECT * FROM users WHERE id = :id AND org = :org.id`
+	got, err := ParseNamed(q)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Param{{Name: "id", Start: 31, End: 34}, {Name: "org.id", Start: 45, End: 52}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+	for _, p := range got {
+		if q[p.Start:p.End] != ":"+p.Name {
+			t.Fatalf("span %d:%d = %q", p.Start, p.End, q[p.Start:p.End])
+		}
+	}
+
+	got, err = ParseNamed(`INSERT INTO t VALUES :rows(a, b) -- :c
+		ON CONFLICT DO UPDATE SET x = ':x', y = :y::int, @v := 1`)
+	if err != nil || len(got) != 2 || got[0].Name != "rows" || !reflect.DeepEqual(got[0].Columns, []string{"a", "b"}) || got[1].Name != "y" {
+		t.Fatalf("got %+v, %v", got, err)
+	}
+	if _, err := ParseNamed(`SELECT ':a`); err == nil {
+		t.Fatal("expected error for an unterminated string")
+	}
+}
+
+func TestRegisterPlaceholder(t *testing.T) {
+	colonV := RegisterPlaceholder(func(n int) string { return ":v" + strconv.Itoa(n) })
+	pct := RegisterPlaceholder(func(int) string { return "%s" })
+	if colonV == pct || colonV.native() {
+		t.Fatalf("placeholders %d, %d", colonV, pct)
+	}
+
+	got, args, err := Rebind(`SELECT * FROM t WHERE a = :a AND id IN (:ids) AND note <> '?'`, colonV,
+		map[string]any{"a": 1, "ids": []int{2, 3}})
+	if err != nil || got != `SELECT * FROM t WHERE a = :v1 AND id IN (:v2,:v3) AND note <> '?'` {
+		t.Fatalf("named: %q, %v", got, err)
+	}
+	eqSlice(t, args, []any{1, 2, 3}, "args")
+
+	if got, _, _ := Rebind(`a = ? AND b = ?`, pct, 1, 2); got != `a = %s AND b = %s` {
+		t.Fatalf("positional: %q", got)
+	}
+	if got, _, _ := Rebind(`a = ?`, customPlaceholderBase+100, 1); got != `a = ?` {
+		t.Fatalf("unregistered: %q", got)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected panic for nil format")
+		}
+	}()
+	RegisterPlaceholder(nil)
+}
@@ -0,0 +1,64 @@
+package xsql
+
+import (
+	"reflect"
+	"strings"
+)
+
+// nullValueType returns T for t = sql.Null[T] or t = Option[T].
+func nullValueType(t reflect.Type) (reflect.Type, bool) {
+	if isOptionType(t) {
+		return reflect.Zero(t).Interface().(interface{ optionElem() reflect.Type }).optionElem(), true
+	}
+	if t.Kind() != reflect.Struct || t.PkgPath() != "database/sql" || !strings.HasPrefix(t.Name(), "Null[") {
+		return nil, false
+	}
+	v, ok := t.FieldByName("V")
+	if !ok || t.NumField() != 2 || t.Field(1).Name != "Valid" {
+		return nil, false
+	}
+	return v.Type, true
+}
+
+// pickNull adapts inner, the step for T, to a destination of type t, either
+// sql.Null[T] or Option[T]. When inner scans a sql.Null[T] directly, its Scan
+// method already does the job and the field is left to it; otherwise the
+// column is scanned into an interface value, NULL clears the destination, and
+// anything else is converted to T with inner's rules (converters, enums,
+// UUIDs, …) and stored as a valid value.
+func pickNull(t reflect.Type, inner step) step {
+	isOption := isOptionType(t)
+	var elem reflect.Type // T of Option[T]
+	if isOption {
+		elem, _ = nullValueType(t)
+	}
+	if !isOption && inner.kind != stepIndirect {
+		return step{kind: stepDirect, fpath: inner.fpath}
+	}
+	apply := stepApplier(inner)
+	return step{
+		kind:   stepIndirect,
+		fpath:  inner.fpath,
+		convTo: reflect.TypeOf((*any)(nil)).Elem(),
+		post: func(dst, src reflect.Value) error {
+			v := src.Interface()
+			if v == nil {
+				dst.SetZero()
+				return nil
+			}
+			if isOption {
+				tmp := reflect.New(elem).Elem()
+				if err := apply(tmp, v); err != nil {
+					return err
+				}
+				dst.Addr().Interface().(optionSetter).setSome(tmp)
+				return nil
+			}
+			if err := apply(dst.Field(0), v); err != nil {
+				return err
+			}
+			dst.Field(1).SetBool(true)
+			return nil
+		},
+	}
+}
@@ -0,0 +1,67 @@
+package xsql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"testing"
+	"time"
+)
+
+type nullTestLevel int
+
+func init() {
+	RegisterConverter(func(src any) (nullTestLevel, error) {
+		switch fmt.Sprint(src) {
+		case "low":
+			return 1, nil
+		case "high":
+			return 2, nil
+		}
+		return 0, fmt.Errorf("bad level %v", src)
+	})
+}
+
+func TestScan_GenericNull(t *testing.T) {
+	type Status string
+	type Row struct {
+		Level  sql.Null[nullTestLevel] `db:"level"`
+		Status sql.Null[Status]        `db:"status"`
+		TTL    sql.Null[time.Duration] `db:"ttl,seconds"`
+		Active sql.Null[bool]          `db:"active"`
+	}
+	db := newTestDB(t, func(q string, _ []driver.NamedValue) ([]string, [][]driver.Value, error) {
+		if q == "one" {
+			return []string{"level"}, [][]driver.Value{{"high"}}, nil
+		}
+		return []string{"level", "status", "ttl", "active"}, [][]driver.Value{
+			{"low", "open", int64(90), "yes"},
+			{nil, nil, nil, nil},
+		}, nil
+	})
+	defer func() { _ = db.Close() }()
+	ctx := context.Background()
+
+	got, err := Query[Row](ctx, db, "q")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := got[0]
+	if !r.Level.Valid || r.Level.V != 1 || !r.Status.Valid || r.Status.V != "open" ||
+		!r.TTL.Valid || r.TTL.V != 90*time.Second || !r.Active.Valid || !r.Active.V {
+		t.Fatalf("unexpected row: %+v", r)
+	}
+	if got[1] != (Row{}) {
+		t.Fatalf("NULLs should leave invalid zero values: %+v", got[1])
+	}
+
+	lvl, err := Get[sql.Null[nullTestLevel]](ctx, db, "one")
+	if err != nil || !lvl.Valid || lvl.V != 2 {
+		t.Fatalf("Get sql.Null[T]: %+v %v", lvl, err)
+	}
+	s, err := Get[sql.NullString](ctx, db, "one")
+	if err != nil || !s.Valid || s.String != "high" {
+		t.Fatalf("Get sql.NullString: %+v %v", s, err)
+	}
+}
@@ -0,0 +1,141 @@
+package xsql
+
+import (
+	"database/sql/driver"
+	"reflect"
+	"strings"
+)
+
+// Option is an optional value: Some holds a value and None holds nothing. It
+// models nullable columns without pointers, both as a destination (a NULL
+// column scans as None) and as an argument (None binds as NULL), including
+// struct fields bound with NamedExec, NamedQuery, and Rebind.
+//
+// Query and Get convert the inner value with the rules for T, including
+// converters registered on the mapper; the Scan method, used when an Option is
+// handed to database/sql directly, applies those of the default mapper.
+//
+// Example:
+//
+//	type User struct {
+//	    ID       int64               `db:"id"`
+//	    Nickname xsql.Option[string] `db:"nickname"`
+//	}
+//
+//	u, err := xsql.Get[User](ctx, db, `SELECT id, nickname FROM users WHERE id = $1`, id)
+//	if nick, ok := u.Nickname.Get(); ok {
+//	    fmt.Println(nick)
+//	}
+type Option[T any] struct {
+	v  T
+	ok bool
+}
+
+// Some returns an Option holding v.
+func Some[T any](v T) Option[T] { return Option[T]{v: v, ok: true} }
+
+// None returns an empty Option. It is the same as the zero Option.
+func None[T any]() Option[T] { return Option[T]{} }
+
+// IsSome reports whether o holds a value.
+func (o Option[T]) IsSome() bool { return o.ok }
+
+// IsNone reports whether o is empty.
+func (o Option[T]) IsNone() bool { return !o.ok }
+
+// Get returns the value held by o and true, or the zero T and false for None.
+func (o Option[T]) Get() (T, bool) { return o.v, o.ok }
+
+// Or returns the value held by o, or def for None.
+func (o Option[T]) Or(def T) T {
+	if o.ok {
+		return o.v
+	}
+	return def
+}
+
+// Scan implements sql.Scanner. NULL scans as None; any other value is
+// converted to T with the default mapper's rules and scans as Some.
+func (o *Option[T]) Scan(src any) error {
+	if src == nil {
+		*o = Option[T]{}
+		return nil
+	}
+	var v T
+	rv := reflect.ValueOf(&v).Elem()
+	apply, err := getMapper().valueApplier(rv.Type())
+	if err != nil {
+		return err
+	}
+	if err := apply(rv, src); err != nil {
+		return err
+	}
+	*o = Some(v)
+	return nil
+}
+
+// Value implements driver.Valuer. None is NULL; Some is its value, passed
+// through a valuer registered for T or else converted like any other argument.
+func (o Option[T]) Value() (driver.Value, error) {
+	if !o.ok {
+		return nil, nil
+	}
+	if fn, ok := globalConverters.valuer(reflect.TypeOf(o.v)); ok {
+		return fn(o.v)
+	}
+	return driver.DefaultParameterConverter.ConvertValue(o.v)
+}
+
+func (o Option[T]) optionElem() reflect.Type { return reflect.TypeOf((*T)(nil)).Elem() }
+
+// setSome stores v as the value. For an interface T, a nil v stores the nil
+// interface rather than failing the type assertion.
+func (o *Option[T]) setSome(v reflect.Value) {
+	var x T
+	if v.IsValid() && (v.Kind() != reflect.Interface || !v.IsNil()) {
+		x = v.Interface().(T)
+	}
+	*o = Some(x)
+}
+
+// optionSetter is implemented by *Option[T]; it lets the mapper fill Options
+// of any T through their unexported fields.
+type optionSetter interface {
+	optionElem() reflect.Type
+	setSome(v reflect.Value)
+}
+
+var optionSetterType = reflect.TypeOf((*optionSetter)(nil)).Elem()
+
+// optionPkgPath is the package path of Option, for telling Option[T] apart
+// from structs that embed it (and so also implement optionSetter).
+var optionPkgPath = reflect.TypeOf(Option[int]{}).PkgPath()
+
+// isOptionType reports whether t is an instance of Option.
+func isOptionType(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && t.PkgPath() == optionPkgPath && strings.HasPrefix(t.Name(), "Option[") &&
+		reflect.PointerTo(t).Implements(optionSetterType)
+}
+
+// valueKey identifies the conversion a mapper applies to a single value of
+// type t under converter generations gen (global) and mgen (the mapper's).
+type valueKey struct {
+	t         reflect.Type
+	gen, mgen uint64
+}
+
+// valueApplier returns the func that stores a driver value in a t the way m
+// scans a whole value of type t, for [Option.Scan]. It is built once per type
+// and converter generation.
+func (m *Mapper) valueApplier(t reflect.Type) (func(dst reflect.Value, v any) error, error) {
+	key := valueKey{t: t, gen: globalConverters.gen.Load(), mgen: m.convs.gen.Load()}
+	if fn, ok := m.valueAppliers.Load(key); ok {
+		return fn.(func(reflect.Value, any) error), nil
+	}
+	st, err := m.makeWholeStep(t)
+	if err != nil {
+		return nil, err
+	}
+	fn, _ := m.valueAppliers.LoadOrStore(key, stepApplier(st))
+	return fn.(func(reflect.Value, any) error), nil
+}
@@ -0,0 +1,136 @@
+package xsql
+
+import (
+	"context"
+	"database/sql/driver"
+	"reflect"
+	"testing"
+)
+
+func TestOption_Scan(t *testing.T) {
+	type Row struct {
+		Name  Option[string]        `db:"name"`
+		Level Option[nullTestLevel] `db:"level"`
+		Flag  Option[bool]          `db:"flag"`
+	}
+	db := newTestDB(t, func(q string, _ []driver.NamedValue) ([]string, [][]driver.Value, error) {
+		if q == "one" {
+			return []string{"n"}, [][]driver.Value{{int64(7)}}, nil
+		}
+		return []string{"name", "level", "flag"}, [][]driver.Value{
+			{"ann", "high", int64(1)},
+			{nil, nil, nil},
+		}, nil
+	})
+	defer func() { _ = db.Close() }()
+	ctx := context.Background()
+
+	got, err := Query[Row](ctx, db, "q")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name, ok := got[0].Name.Get(); !ok || name != "ann" {
+		t.Fatalf("name: %q %v", name, ok)
+	}
+	if got[0].Level.Or(0) != 2 || !got[0].Flag.Or(false) {
+		t.Fatalf("unexpected row: %+v", got[0])
+	}
+	if got[1].Name.IsSome() || got[1].Level.IsSome() || !got[1].Flag.IsNone() {
+		t.Fatalf("NULLs should scan as None: %+v", got[1])
+	}
+
+	n, err := Get[Option[int32]](ctx, db, "one")
+	if err != nil || n != Some[int32](7) {
+		t.Fatalf("Get Option: %+v %v", n, err)
+	}
+
+	var o Option[nullTestLevel]
+	if err := o.Scan("low"); err != nil || o != Some[nullTestLevel](1) {
+		t.Fatalf("Scan: %+v %v", o, err)
+	}
+	if err := o.Scan(nil); err != nil || o.IsSome() {
+		t.Fatalf("Scan NULL: %+v %v", o, err)
+	}
+}
+
+func TestOption_SetSomeNilInterface(t *testing.T) {
+	var o Option[error]
+	o.setSome(reflect.Zero(reflect.TypeOf((*error)(nil)).Elem()))
+	if v, ok := o.Get(); !ok || v != nil {
+		t.Fatalf("nil interface: %v %v", v, ok)
+	}
+	var a Option[any]
+	a.setSome(reflect.Value{})
+	if v, ok := a.Get(); !ok || v != nil {
+		t.Fatalf("invalid value: %v %v", v, ok)
+	}
+}
+
+func TestOption_ScanCachesConversion(t *testing.T) {
+	prev := getMapper()
+	t.Cleanup(func() { SetDefaultMapper(prev) })
+	m := NewMapper()
+	SetDefaultMapper(m)
+
+	count := func() (n int) {
+		m.valueAppliers.Range(func(any, any) bool { n++; return true })
+		return n
+	}
+	var o Option[int32]
+	for _, src := range []any{int64(1), int64(2)} {
+		if err := o.Scan(src); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if o != Some[int32](2) || count() != 1 {
+		t.Fatalf("got %+v with %d cached conversions, want 1", o, count())
+	}
+
+	// A converter registered afterwards applies to the next Scan.
+	m.RegisterConverter(reflect.TypeOf(int32(0)), func(any) (any, error) { return int32(7), nil })
+	if err := o.Scan(int64(3)); err != nil || o != Some[int32](7) {
+		t.Fatalf("after RegisterConverter: %+v %v", o, err)
+	}
+}
+
+// embedsOption is a user struct that embeds an Option, and so has its
+// methods, without being one.
+type embedsOption struct {
+	Label string
+	Option[int64]
+}
+
+func TestOption_TypeIdentity(t *testing.T) {
+	if vt, ok := nullValueType(reflect.TypeOf(Option[int64]{})); !ok || vt != reflect.TypeOf(int64(0)) {
+		t.Fatalf("Option[int64]: %v %v", vt, ok)
+	}
+	if vt, ok := nullValueType(reflect.TypeOf(embedsOption{})); ok {
+		t.Fatalf("struct embedding an Option taken for one, with value type %v", vt)
+	}
+}
+
+func TestOption_Value(t *testing.T) {
+	type Status string
+	type Row struct {
+		Name   Option[string] `db:"name"`
+		Status Option[Status] `db:"status"`
+	}
+	var args []driver.NamedValue
+	db := newTestDB(t, func(q string, a []driver.NamedValue) ([]string, [][]driver.Value, error) {
+		args = a
+		return nil, nil, nil
+	})
+	defer func() { _ = db.Close() }()
+
+	in := Row{Name: None[string](), Status: Some[Status]("open")}
+	if _, err := NamedExec(context.Background(), db, PlaceholderDollar, `UPDATE t SET name = :name, status = :status`, in); err != nil {
+		t.Fatal(err)
+	}
+	var got []any
+	for _, a := range args {
+		got = append(got, a.Value)
+	}
+	if !reflect.DeepEqual(got, []any{nil, "open"}) {
+		t.Fatalf("bound args = %#v", got)
+	}
+}
@@ -0,0 +1,168 @@
+package xsql
+
+import (
+	"context"
+	"time"
+)
+
+// QueryOpt configures a single call to Query, Get, Exec, and their named
+// variants. Options are passed among the query args and are removed before the
+// args reach the driver, so they can appear anywhere in the list.
+//
+// Example:
+//
+//	users, err := xsql.Query[User](ctx, db,
+//	    `SELECT id, email FROM users WHERE status = $1`, "active",
+//	    xsql.WithTimeout(2*time.Second), xsql.WithStrict())
+type QueryOpt func(*callConfig)
+
+// callConfig is the per-call configuration assembled from QueryOpt values.
+type callConfig struct {
+	timeout time.Duration
+	strict  bool
+	mapper  *Mapper
+	ph      Placeholder // QueryPage: dialect for the LIMIT/OFFSET clause
+	dialect *Dialect    // QueryPage: overrides ph
+	total   bool        // QueryPage: also count all matching rows
+	ndjson  bool        // StreamJSON: one object per line instead of an array
+
+	strictParams bool // named binding: reject unused map keys
+}
+
+// WithTimeout bounds the call (including reading all rows) by d. It has no
+// effect if d <= 0.
+func WithTimeout(d time.Duration) QueryOpt {
+	return func(c *callConfig) { c.timeout = d }
+}
+
+// WithStrict makes the call fail with [ErrUnmappedColumn] when a result column
+// has no destination field in T, instead of silently discarding it.
+func WithStrict() QueryOpt {
+	return func(c *callConfig) { c.strict = true }
+}
+
+// WithStrictParams makes named binding fail with [ErrUnusedParam] when a
+// map[string]any param has keys the query does not use, which catches typos
+// in keys. Missing parameters are always an error. It applies to Rebind,
+// the named functions, and NamedStmt.Bind.
+//
+// Example:
+//
+//	q, args, err := xsql.Rebind(`SELECT * FROM users WHERE status = :status LIMIT :limit`,
+//	    xsql.PlaceholderDollar, map[string]any{"status": "active", "limit": 10, "ofset": 20},
+//	    xsql.WithStrictParams())
+//	// err: xsql: named bind: unused parameters: :ofset
+func WithStrictParams() QueryOpt {
+	return func(c *callConfig) { c.strictParams = true }
+}
+
+// WithMapper scans the call's results with m instead of the package-level
+// mapper. A nil m selects the package-level mapper.
+func WithMapper(m *Mapper) QueryOpt {
+	return func(c *callConfig) { c.mapper = m }
+}
+
+// WithPlaceholder tells [QueryPage] which database family it is talking to,
+// so it can generate a matching LIMIT/OFFSET clause. The default is
+// PlaceholderQuestion (LIMIT … OFFSET).
+func WithPlaceholder(ph Placeholder) QueryOpt {
+	return func(c *callConfig) { c.ph = ph }
+}
+
+// WithDialect tells [QueryPage] to generate the row-limiting clause of d,
+// including TOP paging, which [WithPlaceholder] cannot select.
+func WithDialect(d Dialect) QueryOpt {
+	return func(c *callConfig) { c.dialect = &d }
+}
+
+// WithTotal makes [QueryPage] run a second query counting every matching row
+// and report it in [Page.Total].
+func WithTotal() QueryOpt {
+	return func(c *callConfig) { c.total = true }
+}
+
+// WithNDJSON makes [StreamJSON] write newline-delimited JSON, one object per
+// line, instead of a single JSON array.
+func WithNDJSON() QueryOpt {
+	return func(c *callConfig) { c.ndjson = true }
+}
+
+// splitOpts separates QueryOpt values from args. When args contains no
+// options it is returned as is, without allocating.
+func splitOpts(args []any) ([]any, []QueryOpt) {
+	n := 0
+	for _, a := range args {
+		if _, ok := a.(QueryOpt); ok {
+			n++
+		}
+	}
+	if n == 0 {
+		return args, nil
+	}
+	rest := make([]any, 0, len(args)-n)
+	opts := make([]QueryOpt, 0, n)
+	for _, a := range args {
+		if o, ok := a.(QueryOpt); ok {
+			opts = append(opts, o)
+		} else {
+			rest = append(rest, a)
+		}
+	}
+	return rest, opts
+}
+
+// callArgs prepares the args of a call: it splits off QueryOpt values into a
+// callConfig, rejects mixed sql.Named usage, and applies registered valuers.
+func callArgs(args []any) ([]any, callConfig, error) {
+	args, opts := splitOpts(args)
+	cfg := newCallConfig(opts)
+	if _, err := checkNamedArgs(args); err != nil {
+		return nil, cfg, err
+	}
+	args, err := convertArgs(args)
+	return args, cfg, err
+}
+
+// appendOpts appends opts to args, for forwarding them to another call.
+func appendOpts(args []any, opts []QueryOpt) []any {
+	for _, o := range opts {
+		args = append(args, o)
+	}
+	return args
+}
+
+func newCallConfig(opts []QueryOpt) callConfig {
+	var c callConfig
+	for _, o := range opts {
+		if o != nil {
+			o(&c)
+		}
+	}
+	return c
+}
+
+// context applies the configured timeout, if any, to ctx.
+func (c *callConfig) context(ctx context.Context) (context.Context, context.CancelFunc) {
+	if c.timeout > 0 {
+		return context.WithTimeout(ctx, c.timeout)
+	}
+	return ctx, func() {}
+}
+
+// pageDialect returns the dialect QueryPage paginates for.
+func (c *callConfig) pageDialect() Dialect {
+	if c.dialect != nil {
+		return *c.dialect
+	}
+	return dialectForPlaceholder(c.ph)
+}
+
+// scanMapper returns the mapper to scan with and whether strict mapping is on,
+// either for this call or on the mapper itself.
+func (c *callConfig) scanMapper() (*Mapper, bool) {
+	m := c.mapper
+	if m == nil {
+		m = getMapper()
+	}
+	return m, c.strict || m.Strict
+}
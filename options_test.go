@@ -0,0 +1,97 @@
+package xsql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"testing"
+	"time"
+)
+
+type optRow struct {
+	ID int64 `db:"id"`
+}
+
+func optDB(t *testing.T, gotArgs *[]driver.NamedValue) *sql.DB {
+	t.Helper()
+	return newTestDB(t, func(q string, args []driver.NamedValue) ([]string, [][]driver.Value, error) {
+		*gotArgs = args
+		return []string{"id", "extra"}, [][]driver.Value{{int64(1), "x"}}, nil
+	})
+}
+
+func TestQueryOpts_StrippedFromArgs(t *testing.T) {
+	var got []driver.NamedValue
+	db := optDB(t, &got)
+	defer func() { _ = db.Close() }()
+
+	rows, err := Query[optRow](context.Background(), db, "q", WithTimeout(time.Second), 7, WithMapper(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 1 || rows[0].ID != 1 {
+		t.Fatalf("unexpected rows: %+v", rows)
+	}
+	if len(got) != 1 || got[0].Value != int64(7) {
+		t.Fatalf("driver args = %+v, want [7]", got)
+	}
+}
+
+func TestQueryOpts_Strict(t *testing.T) {
+	var got []driver.NamedValue
+	db := optDB(t, &got)
+	defer func() { _ = db.Close() }()
+	ctx := context.Background()
+
+	// Lenient by default: the extra column is dropped.
+	if _, err := Get[optRow](ctx, db, "q"); err != nil {
+		t.Fatalf("lenient Get: %v", err)
+	}
+	_, err := Get[optRow](ctx, db, "q", WithStrict())
+	if !errors.Is(err, ErrUnmappedColumn) {
+		t.Fatalf("Get strict: want ErrUnmappedColumn, got %v", err)
+	}
+	_, err = Query[optRow](ctx, db, "q", WithStrict())
+	if !errors.Is(err, ErrUnmappedColumn) {
+		t.Fatalf("Query strict: want ErrUnmappedColumn, got %v", err)
+	}
+
+	// A strict Mapper applies through WithMapper.
+	m := NewMapper()
+	m.Strict = true
+	_, err = Query[optRow](ctx, db, "q", WithMapper(m))
+	if !errors.Is(err, ErrUnmappedColumn) {
+		t.Fatalf("strict mapper: want ErrUnmappedColumn, got %v", err)
+	}
+	n := 0
+	m.planCache.Range(func(any, any) bool { n++; return true })
+	if n != 1 {
+		t.Fatalf("expected one plan cached on the per-call mapper, got %d", n)
+	}
+}
+
+// ctxExecer records whether the context it was called with had a deadline.
+type ctxExecer struct{ deadline bool }
+
+func (e *ctxExecer) ExecContext(ctx context.Context, _ string, _ ...any) (sql.Result, error) {
+	_, e.deadline = ctx.Deadline()
+	return result{}, nil
+}
+
+func TestQueryOpts_TimeoutExec(t *testing.T) {
+	e := &ctxExecer{}
+	if _, err := Exec(context.Background(), e, "x"); err != nil {
+		t.Fatal(err)
+	}
+	if e.deadline {
+		t.Fatalf("no deadline expected without WithTimeout")
+	}
+	if _, err := NamedExec(context.Background(), e, PlaceholderDollar, "x = :a",
+		map[string]any{"a": 1}, WithTimeout(time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	if !e.deadline {
+		t.Fatalf("WithTimeout should set a deadline")
+	}
+}
@@ -0,0 +1,34 @@
+package xsql
+
+import "database/sql"
+
+// Out marks a parameter as an output parameter of a stored procedure call:
+// dest, a pointer, is filled in by the driver when the statement runs. Use it
+// as a named-parameter value or a positional arg; it is passed to the driver
+// as [sql.Out]. Drivers that support output parameters include go-mssqldb and
+// godror.
+//
+// Struct params can instead tag a field `db:"name,out"` (or `,inout`); the
+// struct must then be passed by pointer so the field can be filled.
+//
+// Example:
+//
+//	var total int64
+//	_, err := xsql.NamedExec(ctx, db, xsql.PlaceholderColonName,
+//	    `BEGIN order_total(:customer, :total); END;`,
+//	    map[string]any{"customer": 42, "total": xsql.Out(&total)})
+func Out(dest any) sql.Out {
+	return sql.Out{Dest: dest}
+}
+
+// InOut is like [Out] for an input/output parameter: the driver sends the
+// current value of *dest and stores the returned value in it.
+//
+// Example:
+//
+//	counter := int64(10)
+//	_, err := xsql.NamedExec(ctx, db, xsql.PlaceholderAtName,
+//	    `EXEC bump @n = :n OUTPUT`, map[string]any{"n": xsql.InOut(&counter)})
+func InOut(dest any) sql.Out {
+	return sql.Out{Dest: dest, In: true}
+}
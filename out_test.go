@@ -0,0 +1,81 @@
+package xsql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"reflect"
+	"testing"
+)
+
+// outConn is a test connection that accepts sql.Out args and, on Exec, stores
+// 100 plus the input value (or 100) in every output destination.
+type outConn struct{ testConn }
+
+func (c *outConn) CheckNamedValue(nv *driver.NamedValue) error {
+	if _, ok := nv.Value.(sql.Out); ok {
+		return nil
+	}
+	var err error
+	nv.Value, err = driver.DefaultParameterConverter.ConvertValue(nv.Value)
+	return err
+}
+
+func (c *outConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	for _, a := range args {
+		if o, ok := a.Value.(sql.Out); ok {
+			n := int64(100)
+			if o.In {
+				n += *o.Dest.(*int64)
+			}
+			*o.Dest.(*int64) = n
+		}
+	}
+	return c.testConn.ExecContext(ctx, query, args)
+}
+
+type outConnector struct{ testConnector }
+
+func (c *outConnector) Connect(context.Context) (driver.Conn, error) {
+	return &outConn{testConn{h: c.h}}, nil
+}
+
+func TestOutParams(t *testing.T) {
+	var got []driver.NamedValue
+	db := sql.OpenDB(&outConnector{testConnector{h: func(q string, args []driver.NamedValue) ([]string, [][]driver.Value, error) {
+		got = args
+		return nil, nil, nil
+	}}})
+	defer func() { _ = db.Close() }()
+	ctx := context.Background()
+
+	var total int64
+	counter := int64(5)
+	_, err := NamedExec(ctx, db, PlaceholderAtName, `EXEC proc @id = :id, @total = :total OUTPUT, @n = :n OUTPUT`,
+		map[string]any{"id": 1, "total": Out(&total), "n": InOut(&counter)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 100 || counter != 105 {
+		t.Fatalf("total=%d counter=%d", total, counter)
+	}
+	if len(got) != 3 || got[0].Name != "id" || got[1].Name != "total" {
+		t.Fatalf("args = %+v", got)
+	}
+
+	type call struct {
+		ID    int64 `db:"id"`
+		Total int64 `db:"total,out"`
+		N     int64 `db:"n,inout"`
+	}
+	c := call{ID: 1, N: 1}
+	if _, err := NamedExec(ctx, db, PlaceholderQuestion, `CALL proc(:id, :total, :n)`, &c); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(c, call{ID: 1, Total: 100, N: 101}) {
+		t.Fatalf("struct out fields: %+v", c)
+	}
+	if _, err := NamedExec(ctx, db, PlaceholderQuestion, `CALL proc(:id, :total, :n)`, c); err == nil {
+		t.Fatal("expected error for output fields of a struct passed by value")
+	}
+}
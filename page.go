@@ -0,0 +1,73 @@
+package xsql
+
+import (
+	"context"
+	"fmt"
+)
+
+// Page is one page of results from [QueryPage].
+type Page[T any] struct {
+	Items   []T
+	Total   int64 // all matching rows; only set with WithTotal
+	Page    int   // 1-based page number
+	PerPage int
+	HasNext bool // at least one row exists after this page
+}
+
+// QueryPage runs query for one page of results using offset pagination and
+// scans the rows into T, like [Query]. page is 1-based (values below 1 select
+// the first page) and perPage must be positive.
+//
+// QueryPage appends the row-limiting clause itself, so query must not have
+// one. It reads perPage+1 rows to compute HasNext without counting. Pass
+// [WithPlaceholder] or [WithDialect] among the args to target SQL Server or
+// Oracle, which use OFFSET … FETCH and need the query to have an ORDER BY, and
+// [WithTotal] to fill Total with a second query, SELECT COUNT(*) FROM (query),
+// which needs a query the database accepts as a subquery (SQL Server rejects
+// ORDER BY there).
+//
+// Example:
+//
+//	p, err := xsql.QueryPage[User](ctx, db,
+//	    `SELECT id, email FROM users WHERE status = $1 ORDER BY id`, 3, 50,
+//	    "active", xsql.WithPlaceholder(xsql.PlaceholderDollar), xsql.WithTotal())
+//	// p.Items holds users 101–150; p.HasNext reports whether page 4 exists.
+func QueryPage[T any](ctx context.Context, q Querier, query string, page, perPage int, args ...any) (Page[T], error) {
+	if perPage <= 0 {
+		return Page[T]{}, fmt.Errorf("xsql: perPage must be positive, got %d", perPage)
+	}
+	if page < 1 {
+		page = 1
+	}
+	args, opts := splitOpts(args)
+	cfg := newCallConfig(opts)
+	ctx, cancel := cfg.context(ctx)
+	defer cancel()
+
+	out := Page[T]{Page: page, PerPage: perPage}
+	paged, err := cfg.pageDialect().Paginate(query, perPage+1, (page-1)*perPage)
+	if err != nil {
+		return Page[T]{}, err
+	}
+	items, err := Query[T](ctx, q, paged, appendOpts(args, opts)...)
+	if err != nil {
+		return Page[T]{}, err
+	}
+	if len(items) > perPage {
+		items, out.HasNext = items[:perPage], true
+	}
+	out.Items = items
+
+	if cfg.total {
+		out.Total, err = Get[int64](ctx, q, countQuery(query), args...)
+		if err != nil {
+			return Page[T]{}, fmt.Errorf("xsql: page total: %w", err)
+		}
+	}
+	return out, nil
+}
+
+// countQuery wraps query to count the rows it returns.
+func countQuery(query string) string {
+	return "SELECT COUNT(*) FROM (" + trimStatement(query) + ") xsql_count"
+}
@@ -0,0 +1,68 @@
+package xsql
+
+import (
+	"context"
+	"database/sql/driver"
+	"strings"
+	"testing"
+)
+
+func TestQueryPage_LimitOffsetAndHasNext(t *testing.T) {
+	var queries []string
+	db := newTestDB(t, func(q string, _ []driver.NamedValue) ([]string, [][]driver.Value, error) {
+		queries = append(queries, q)
+		if strings.HasPrefix(q, "SELECT COUNT(*)") {
+			return []string{"count"}, [][]driver.Value{{int64(7)}}, nil
+		}
+		// Page 2 of 3 per page, with a 4th row signalling a next page.
+		return []string{"n"}, [][]driver.Value{{int64(4)}, {int64(5)}, {int64(6)}, {int64(7)}}, nil
+	})
+	defer func() { _ = db.Close() }()
+
+	p, err := QueryPage[int64](context.Background(), db, "SELECT n FROM t ORDER BY n;", 2, 3, WithTotal())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(p.Items) != 3 || p.Items[0] != 4 || p.Items[2] != 6 || !p.HasNext {
+		t.Fatalf("unexpected page: %+v", p)
+	}
+	if p.Page != 2 || p.PerPage != 3 || p.Total != 7 {
+		t.Fatalf("unexpected metadata: %+v", p)
+	}
+	if queries[0] != "SELECT n FROM t ORDER BY n LIMIT 4 OFFSET 3" {
+		t.Fatalf("page query = %q", queries[0])
+	}
+	if queries[1] != "SELECT COUNT(*) FROM (SELECT n FROM t ORDER BY n) xsql_count" {
+		t.Fatalf("count query = %q", queries[1])
+	}
+}
+
+func TestQueryPage_LastPage_NoTotal(t *testing.T) {
+	var queries []string
+	db := newTestDB(t, func(q string, args []driver.NamedValue) ([]string, [][]driver.Value, error) {
+		queries = append(queries, q)
+		if len(args) != 1 || args[0].Value != "x" {
+			t.Errorf("args = %+v", args)
+		}
+		return []string{"n"}, [][]driver.Value{{int64(1)}}, nil
+	})
+	defer func() { _ = db.Close() }()
+
+	p, err := QueryPage[int64](context.Background(), db, "SELECT n FROM t WHERE s = @p1 ORDER BY n", 0, 10,
+		"x", WithPlaceholder(PlaceholderAtP))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Page != 1 || p.HasNext || p.Total != 0 || len(p.Items) != 1 {
+		t.Fatalf("unexpected page: %+v", p)
+	}
+	if len(queries) != 1 || queries[0] != "SELECT n FROM t WHERE s = @p1 ORDER BY n OFFSET 0 ROWS FETCH NEXT 11 ROWS ONLY" {
+		t.Fatalf("queries = %q", queries)
+	}
+}
+
+func TestQueryPage_InvalidPerPage(t *testing.T) {
+	if _, err := QueryPage[int64](context.Background(), &querier{}, "SELECT 1", 1, 0); err == nil {
+		t.Fatalf("expected error for perPage=0")
+	}
+}
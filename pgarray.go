@@ -0,0 +1,264 @@
+package xsql
+
+import (
+	"database/sql/driver"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// pickArray handles slice destinations other than []byte, such as []int64 or
+// []string, for drivers that return PostgreSQL arrays as text ("{1,2,3}"),
+// as lib/pq does without pq.Array. Elements may be strings, integers, floats,
+// bools, named types based on them, or pointers to any of these; a NULL
+// element requires a pointer element type. A NULL column leaves a nil slice.
+//
+// Slice types with their own sql.Scanner (pq.StringArray, …) never reach this step.
+func pickArray(dstType reflect.Type) (reflect.Type, func(dst, src reflect.Value) error, bool) {
+	if dstType.Kind() != reflect.Slice || dstType.Elem().Kind() == reflect.Uint8 {
+		return nil, nil, false
+	}
+	elem := dstType.Elem()
+	base := derefPtr(elem)
+	switch base.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+	default:
+		return nil, nil, false
+	}
+	if elem.Kind() == reflect.Ptr && elem.Elem().Kind() == reflect.Ptr {
+		return nil, nil, false
+	}
+	return reflect.TypeOf((*any)(nil)).Elem(), func(dst, src reflect.Value) error {
+		var text string
+		switch v := src.Interface().(type) {
+		case nil:
+			dst.SetZero()
+			return nil
+		case []byte:
+			text = string(v)
+		case string:
+			text = v
+		default:
+			return fmt.Errorf("xsql: cannot scan %T into %s", v, dstType)
+		}
+		items, err := parseArray(text)
+		if err != nil {
+			return fmt.Errorf("xsql: scan %s: %w", dstType, err)
+		}
+		out := reflect.MakeSlice(dstType, len(items), len(items))
+		for i, it := range items {
+			ev := out.Index(i)
+			if it == nil {
+				if elem.Kind() != reflect.Ptr {
+					return fmt.Errorf("xsql: scan %s: NULL element %d", dstType, i+1)
+				}
+				continue
+			}
+			if elem.Kind() == reflect.Ptr {
+				ev.Set(reflect.New(base))
+				ev = ev.Elem()
+			}
+			if err := setArrayElem(ev, *it); err != nil {
+				return fmt.Errorf("xsql: scan %s: element %d: %w", dstType, i+1, err)
+			}
+		}
+		dst.Set(out)
+		return nil
+	}, true
+}
+
+func setArrayElem(v reflect.Value, s string) error {
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := parseBool(s)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	}
+	return nil
+}
+
+var errArraySyntax = errors.New("malformed array literal")
+
+// parseArray splits a one-dimensional PostgreSQL array literal into its
+// elements. Quoted elements may contain backslash escapes; an unquoted NULL
+// is returned as a nil element.
+func parseArray(s string) ([]*string, error) {
+	if len(s) < 2 || s[0] != '{' || s[len(s)-1] != '}' {
+		return nil, errArraySyntax
+	}
+	body := s[1 : len(s)-1]
+	items := []*string{}
+	if strings.TrimSpace(body) == "" {
+		return items, nil
+	}
+	for i := 0; ; {
+		for i < len(body) && body[i] == ' ' {
+			i++
+		}
+		var item *string
+		if i < len(body) && body[i] == '"' {
+			var b strings.Builder
+			i++
+			for ; i < len(body) && body[i] != '"'; i++ {
+				if body[i] == '\\' {
+					i++
+					if i == len(body) {
+						return nil, errArraySyntax
+					}
+				}
+				b.WriteByte(body[i])
+			}
+			if i == len(body) {
+				return nil, errArraySyntax
+			}
+			i++
+			str := b.String()
+			item = &str
+			for i < len(body) && body[i] == ' ' {
+				i++
+			}
+		} else {
+			j := strings.IndexByte(body[i:], ',')
+			if j < 0 {
+				j = len(body) - i
+			}
+			str := strings.TrimSpace(body[i : i+j])
+			i += j
+			switch {
+			case str == "" || strings.ContainsAny(str, "{}\""):
+				if strings.ContainsRune(str, '{') {
+					return nil, errors.New("multidimensional arrays are not supported")
+				}
+				return nil, errArraySyntax
+			case !strings.EqualFold(str, "NULL"):
+				item = &str
+			}
+		}
+		items = append(items, item)
+		if i == len(body) {
+			return items, nil
+		}
+		if body[i] != ',' {
+			return nil, errArraySyntax
+		}
+		i++
+	}
+}
+
+// Array wraps a slice or array so it is bound as a single PostgreSQL array
+// parameter instead of being expanded into one placeholder per element, for
+// `= ANY(:ids)` queries. The statement text then no longer depends on the
+// slice length, which keeps prepared statements and their plans stable.
+//
+// The value is sent as an array literal ("{1,2,3}"), which lib/pq and pgx
+// both accept for array-typed parameters. Elements may be strings, []byte,
+// numbers, bools, times, driver.Valuers, or pointers to these; nil elements
+// become NULL and a nil slice binds as NULL.
+//
+// Example:
+//
+//	users, err := xsql.NamedQuery[User](ctx, db, xsql.PlaceholderDollar,
+//	    `SELECT id, email FROM users WHERE id = ANY(:ids)`,
+//	    map[string]any{"ids": xsql.Array(ids)})
+//	// SELECT id, email FROM users WHERE id = ANY($1) with args ["{1,2,3}"]
+func Array(v any) driver.Valuer { return pgArray{v} }
+
+type pgArray struct{ v any }
+
+func (a pgArray) Value() (driver.Value, error) {
+	rv := reflect.ValueOf(a.v)
+	if rv.Kind() == reflect.Slice && rv.IsNil() {
+		return nil, nil
+	}
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil, fmt.Errorf("xsql: Array: %T is not a slice or array", a.v)
+	}
+	var b strings.Builder
+	b.WriteByte('{')
+	for i := 0; i < rv.Len(); i++ {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		dv, err := driver.DefaultParameterConverter.ConvertValue(rv.Index(i).Interface())
+		if err != nil {
+			return nil, fmt.Errorf("xsql: Array: element %d: %w", i, err)
+		}
+		writeArrayElem(&b, dv)
+	}
+	b.WriteByte('}')
+	return b.String(), nil
+}
+
+// writeArrayElem writes a driver value as an element of an array literal.
+func writeArrayElem(b *strings.Builder, v driver.Value) {
+	switch v := v.(type) {
+	case nil:
+		b.WriteString("NULL")
+	case int64:
+		b.WriteString(strconv.FormatInt(v, 10))
+	case float64:
+		switch {
+		case math.IsInf(v, 1):
+			b.WriteString("Infinity")
+		case math.IsInf(v, -1):
+			b.WriteString("-Infinity")
+		default:
+			b.WriteString(strconv.FormatFloat(v, 'g', -1, 64))
+		}
+	case bool:
+		if v {
+			b.WriteByte('t')
+		} else {
+			b.WriteByte('f')
+		}
+	case []byte:
+		b.WriteString(`"\\x`)
+		b.WriteString(hex.EncodeToString(v))
+		b.WriteByte('"')
+	case time.Time:
+		quoteArrayElem(b, v.Format(time.RFC3339Nano))
+	case string:
+		quoteArrayElem(b, v)
+	}
+}
+
+func quoteArrayElem(b *strings.Builder, s string) {
+	b.WriteByte('"')
+	for i := 0; i < len(s); i++ {
+		if s[i] == '"' || s[i] == '\\' {
+			b.WriteByte('\\')
+		}
+		b.WriteByte(s[i])
+	}
+	b.WriteByte('"')
+}
@@ -0,0 +1,127 @@
+package xsql
+
+import (
+	"context"
+	"database/sql/driver"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseArray(t *testing.T) {
+	str := func(s string) *string { return &s }
+	tests := []struct {
+		in   string
+		want []*string
+	}{
+		{"{}", []*string{}},
+		{"{1,2,3}", []*string{str("1"), str("2"), str("3")}},
+		{`{a,"b c","d\"e","f\\g",NULL,"NULL",""}`, []*string{str("a"), str("b c"), str(`d"e`), str(`f\g`), nil, str("NULL"), str("")}},
+		{"{ x , y }", []*string{str("x"), str("y")}},
+	}
+	for _, tc := range tests {
+		got, err := parseArray(tc.in)
+		if err != nil || !reflect.DeepEqual(got, tc.want) {
+			t.Fatalf("parseArray(%q) = %v, %v", tc.in, got, err)
+		}
+	}
+	for _, bad := range []string{"", "1,2", "{1,,2}", `{"a}`, `{"a"b}`, "{{1,2},{3,4}}", "{1,}"} {
+		if _, err := parseArray(bad); err == nil {
+			t.Fatalf("parseArray(%q) should fail", bad)
+		}
+	}
+}
+
+func TestScan_PostgresArrays(t *testing.T) {
+	type Level int32
+	type Row struct {
+		IDs    []int64   `db:"ids"`
+		Tags   []string  `db:"tags"`
+		Scores []float64 `db:"scores"`
+		Flags  []bool    `db:"flags"`
+		Levels []Level   `db:"levels"`
+		Notes  []*string `db:"notes"`
+	}
+	db := newTestDB(t, func(q string, _ []driver.NamedValue) ([]string, [][]driver.Value, error) {
+		switch q {
+		case "null_elem":
+			return []string{"ids"}, [][]driver.Value{{"{1,NULL}"}}, nil
+		case "one":
+			return []string{"ids"}, [][]driver.Value{{[]byte("{4,5}")}}, nil
+		}
+		return []string{"ids", "tags", "scores", "flags", "levels", "notes"}, [][]driver.Value{
+			{[]byte("{1,2,3}"), `{go,"sql, db"}`, "{1.5,-2}", "{t,f}", "{7}", `{a,NULL}`},
+			{nil, "{}", nil, nil, nil, nil},
+		}, nil
+	})
+	defer func() { _ = db.Close() }()
+	ctx := context.Background()
+
+	got, err := Query[Row](ctx, db, "q")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := got[0]
+	if !reflect.DeepEqual(r.IDs, []int64{1, 2, 3}) || !reflect.DeepEqual(r.Tags, []string{"go", "sql, db"}) ||
+		!reflect.DeepEqual(r.Scores, []float64{1.5, -2}) || !reflect.DeepEqual(r.Flags, []bool{true, false}) ||
+		!reflect.DeepEqual(r.Levels, []Level{7}) || len(r.Notes) != 2 || *r.Notes[0] != "a" || r.Notes[1] != nil {
+		t.Fatalf("row 0: %+v", r)
+	}
+	r = got[1]
+	if r.IDs != nil || r.Tags == nil || len(r.Tags) != 0 || r.Notes != nil {
+		t.Fatalf("row 1: %+v", r)
+	}
+
+	if _, err := Query[Row](ctx, db, "null_elem"); err == nil || !strings.Contains(err.Error(), "NULL element") {
+		t.Fatalf("expected NULL element error, got %v", err)
+	}
+	if ids, err := Get[[]int64](ctx, db, "one"); err != nil || !reflect.DeepEqual(ids, []int64{4, 5}) {
+		t.Fatalf("Get[[]int64] = %v, %v", ids, err)
+	}
+}
+
+func TestArrayParam(t *testing.T) {
+	s := func(v string) *string { return &v }
+	tests := []struct {
+		in   any
+		want driver.Value
+	}{
+		{[]int64{1, 2, 3}, "{1,2,3}"},
+		{[2]float64{1.5, math.Inf(1)}, "{1.5,Infinity}"},
+		{[]string{"a", `b"c`, `d\e`, "NULL", ""}, `{"a","b\"c","d\\e","NULL",""}`},
+		{[]*string{s("x"), nil}, `{"x",NULL}`},
+		{[]bool{true, false}, "{t,f}"},
+		{[][]byte{{0xde, 0xad}}, `{"\\xdead"}`},
+		{[]testCode{"a"}, `{"code-a"}`},
+		{[]time.Time{time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)}, `{"2024-01-02T03:04:05Z"}`},
+		{[]int{}, "{}"},
+		{[]int(nil), nil},
+	}
+	for _, tt := range tests {
+		got, err := Array(tt.in).Value()
+		if err != nil || got != tt.want {
+			t.Fatalf("Array(%#v) = %#v, %v; want %#v", tt.in, got, err, tt.want)
+		}
+	}
+	if _, err := Array(1).Value(); err == nil {
+		t.Fatal("expected error for a non-slice")
+	}
+	if _, err := Array([][]int{{1}}).Value(); err == nil {
+		t.Fatal("expected error for a nested slice")
+	}
+
+	// The literal round-trips through the array parser.
+	lit, _ := Array([]string{"a b", `q"`, "NULL"}).Value()
+	elems, err := parseArray(lit.(string))
+	if err != nil || len(elems) != 3 || *elems[0] != "a b" || *elems[1] != `q"` || *elems[2] != "NULL" {
+		t.Fatalf("round trip %q: %v", lit, err)
+	}
+
+	// Named binding passes it as one argument.
+	q, args, err := Rebind(`SELECT * FROM t WHERE id = ANY(:ids)`, PlaceholderDollar, map[string]any{"ids": Array([]int64{4, 5})})
+	if err != nil || q != `SELECT * FROM t WHERE id = ANY($1)` || !reflect.DeepEqual(args, []any{"{4,5}"}) {
+		t.Fatalf("Rebind: %q %v %v", q, args, err)
+	}
+}
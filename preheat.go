@@ -0,0 +1,90 @@
+package xsql
+
+import (
+	"context"
+	"reflect"
+)
+
+// Preheat builds and caches m's plan for scanning rows with the result columns
+// cols into T, so that the first query returning them does not pay for
+// reflection. Latency-sensitive services call it during startup. A nil m
+// selects the package-level mapper.
+//
+// Preheat returns the error such a scan would fail with, like
+// [ErrMissingColumn], or [ErrUnmappedColumn] for a strict mapper, which also
+// makes it a cheap check of struct tags against a query. Types implementing
+// [RowScanner] scan themselves and need no plan.
+//
+// Example:
+//
+//	if err := xsql.Preheat[User](nil, "id", "email", "created_at"); err != nil {
+//	    log.Fatal(err)
+//	}
+func Preheat[T any](m *Mapper, cols ...string) error {
+	if scansOwnRows[T]() {
+		return nil
+	}
+	if m == nil {
+		m = getMapper()
+	}
+	_, err := planCols[T](m, m.Strict, append([]string(nil), cols...))
+	return err
+}
+
+// PreheatQuery is [Preheat] for the columns query returns. It runs query with
+// the row-limiting clause of the call's dialect appended, so that no rows are
+// read, and plans T for the result columns. Like [QueryPage], it needs a query
+// without a row-limiting clause of its own; pass [WithDialect] or
+// [WithPlaceholder] among the args for databases other than PostgreSQL,
+// MySQL, and SQLite. args are bound like those of [Query]; a nil m selects the
+// package-level mapper.
+//
+// Example:
+//
+//	const listUsers = `SELECT id, email FROM users WHERE status = $1 ORDER BY id`
+//	if err := xsql.PreheatQuery[User](ctx, nil, db, listUsers, "active"); err != nil {
+//	    log.Fatal(err)
+//	}
+func PreheatQuery[T any](ctx context.Context, m *Mapper, q Querier, query string, args ...any) (err error) {
+	args, cfg, err := callArgs(args)
+	if err != nil {
+		return err
+	}
+	if m != nil {
+		cfg.mapper = m
+	}
+	ctx, cancel := cfg.context(ctx)
+	defer cancel()
+
+	d := cfg.pageDialect()
+	limit := 0
+	if d.Paging == PagingOffsetFetch {
+		limit = 1 // SQL Server rejects FETCH NEXT 0 ROWS
+	}
+	query, err = d.Paginate(query, limit, 0)
+	if err != nil {
+		return err
+	}
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := rows.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}()
+	if scansOwnRows[T]() {
+		return nil
+	}
+	m, strict := cfg.scanMapper()
+	_, err = planRows[T](m, strict, rows)
+	return err
+}
+
+// scansOwnRows reports whether T implements [RowScanner], directly or through
+// its pointer, so that scanOwn scans it without a plan.
+func scansOwnRows[T any]() bool {
+	rt := reflect.TypeOf((*T)(nil)).Elem()
+	return rt.Implements(rowScannerType) || reflect.PointerTo(rt).Implements(rowScannerType)
+}
@@ -0,0 +1,81 @@
+package xsql
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"testing"
+)
+
+func TestPreheat(t *testing.T) {
+	type User struct {
+		ID    int64  `db:"id"`
+		Email string `db:"email,required"`
+	}
+	db := newTestDB(t, func(q string, _ []driver.NamedValue) ([]string, [][]driver.Value, error) {
+		return []string{"ID", "Email"}, [][]driver.Value{{int64(1), "a@x"}}, nil
+	})
+	defer func() { _ = db.Close() }()
+
+	m := NewMapper()
+	if err := Preheat[User](m, "id", "email"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := QueryWith[User](context.Background(), m, db, "q"); err != nil {
+		t.Fatal(err)
+	}
+	if s := m.Stats(); s.PlanMisses != 1 || s.PlanHits != 1 {
+		t.Fatalf("query did not use the preheated plan: %+v", s)
+	}
+
+	if err := Preheat[User](m, "id"); !errors.Is(err, ErrMissingColumn) {
+		t.Fatalf("err = %v, want ErrMissingColumn", err)
+	}
+	m.Strict = true
+	if err := Preheat[User](m, "id", "email", "name"); !errors.Is(err, ErrUnmappedColumn) {
+		t.Fatalf("err = %v, want ErrUnmappedColumn", err)
+	}
+}
+
+func TestPreheatQuery(t *testing.T) {
+	type User struct {
+		ID    int64  `db:"id"`
+		Email string `db:"email"`
+	}
+	var queries []string
+	db := newTestDB(t, func(q string, args []driver.NamedValue) ([]string, [][]driver.Value, error) {
+		queries = append(queries, q)
+		if len(args) != 1 || args[0].Value != "active" {
+			t.Errorf("args = %v", args)
+		}
+		return []string{"id", "email"}, [][]driver.Value{{int64(1), "a@x"}}, nil
+	})
+	defer func() { _ = db.Close() }()
+
+	ctx := context.Background()
+	m := NewMapper()
+	const q = `SELECT id, email FROM users WHERE status = ? ORDER BY id`
+	if err := PreheatQuery[User](ctx, m, db, q, "active"); err != nil {
+		t.Fatal(err)
+	}
+	if err := PreheatQuery[User](ctx, m, db, q+";", "active", WithDialect(SQLServer)); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		q + " LIMIT 0 OFFSET 0",
+		q + " OFFSET 0 ROWS FETCH NEXT 1 ROWS ONLY",
+	}
+	if len(queries) != 2 || queries[0] != want[0] || queries[1] != want[1] {
+		t.Fatalf("queries = %q", queries)
+	}
+	if s := m.Stats(); s.PlanMisses != 1 || s.Plans != 1 {
+		t.Fatalf("stats = %+v", s)
+	}
+
+	type Strict struct {
+		ID int64 `db:"id"`
+	}
+	if err := PreheatQuery[Strict](ctx, m, db, q, "active", WithStrict()); !errors.Is(err, ErrUnmappedColumn) {
+		t.Fatalf("err = %v, want ErrUnmappedColumn", err)
+	}
+}
@@ -2,6 +2,7 @@ package xsql
 
 import (
 	"context"
+	"database/sql"
 )
 
 // Query executes the SQL query and scans all result rows into a slice of T.
@@ -11,7 +12,8 @@ import (
 // otherwise it matches case-insensitive field names.
 //
 // Extra columns are ignored and missing columns set zero values unless strict
-// mode is enabled internally. Safe for concurrent use, Query internally uses a
+// mode is enabled with [WithStrict] or [Mapper.Strict]; args may include
+// [QueryOpt] values to configure the call. Safe for concurrent use, Query internally uses a
 // lazily-initialized, concurrency-safe plan cache based on [sync.Map], which
 // avoids global locks for most read operations.
 //
@@ -32,22 +34,48 @@ import (
 //	    fmt.Println(u.ID, u.Email)
 //	}
 func Query[T any](ctx context.Context, q Querier, query string, args ...any) (out []T, err error) {
+	args, cfg, err := callArgs(args)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := cfg.context(ctx)
+	defer cancel()
+
 	rows, err := q.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
-	// Propagate rows.Close() error if nothing else failed.
+	m, strict := cfg.scanMapper() // lazy, thread-safe
+	return scanAll[T](m, strict, rows)
+}
+
+// QueryWith is [Query] using mapper m instead of the package-level mapper, so
+// applications can keep several differently configured mappers side by side.
+// It is equivalent to passing [WithMapper](m) among the args.
+//
+// Example:
+//
+//	strict := xsql.NewMapper()
+//	strict.Strict = true
+//	users, err := xsql.QueryWith[User](ctx, strict, db, `SELECT id, email FROM users`)
+func QueryWith[T any](ctx context.Context, m *Mapper, q Querier, query string, args ...any) ([]T, error) {
+	return Query[T](ctx, q, query, append(args[:len(args):len(args)], WithMapper(m))...)
+}
+
+// scanAll scans every remaining row of rows into a slice of T and closes rows,
+// propagating the Close error if nothing else failed.
+func scanAll[T any](m *Mapper, strict bool, rows *sql.Rows) (out []T, err error) {
 	defer func() {
 		if cerr := rows.Close(); cerr != nil && err == nil {
 			err = cerr
 		}
 	}()
 
-	m := getMapper() // lazy, thread-safe
+	s := rowScanner[T]{m: m, strict: strict}
 	for rows.Next() {
-		v, scanErr := scanWithMapper[T](m, rows)
+		v, scanErr := s.scan(rows)
 		if scanErr != nil {
-			return nil, scanErr
+			return nil, atRow(scanErr, len(out))
 		}
 		out = append(out, v)
 	}
@@ -32,6 +32,65 @@ func TestQuery_SuccessStruct_MultiRows(t *testing.T) {
 	}
 }
 
+func TestQuery_PointerRows(t *testing.T) {
+	type Row struct {
+		ID   int64  `db:"id"`
+		Name string `db:"name"`
+	}
+	db := newTestDB(t, func(q string, _ []driver.NamedValue) ([]string, [][]driver.Value, error) {
+		return []string{"id", "name"}, [][]driver.Value{{int64(1), "alice"}, {int64(2), "bob"}}, nil
+	})
+	defer func() { _ = db.Close() }()
+
+	got, err := Query[*Row](context.Background(), db, "ok")
+	if err != nil {
+		t.Fatalf("Query error: %v", err)
+	}
+	if len(got) != 2 || got[0] == nil || got[0].ID != 1 || got[1].Name != "bob" || got[0] == got[1] {
+		t.Fatalf("unexpected rows: %+v", got)
+	}
+
+	pairs, err := Query2Split[*Row, string](context.Background(), db, 1, "ok")
+	if err != nil {
+		t.Fatalf("Query2Split error: %v", err)
+	}
+	if pairs[1].First == nil || pairs[1].First.ID != 2 || pairs[1].Second != "bob" {
+		t.Fatalf("unexpected pairs: %+v", pairs)
+	}
+}
+
+func TestQuery_PlansOncePerResult(t *testing.T) {
+	type Row struct {
+		ID   int64  `db:"id"`
+		Name string `db:"name"`
+	}
+	data := make([][]driver.Value, 100)
+	for i := range data {
+		data[i] = []driver.Value{int64(i), "n"}
+	}
+	db := newTestDB(t, func(q string, _ []driver.NamedValue) ([]string, [][]driver.Value, error) {
+		return []string{"id", "name"}, data, nil
+	})
+	defer func() { _ = db.Close() }()
+
+	calls := 0
+	m := NewMapper(WithUnicodeNames(func(s string) string { calls++; return s }))
+	if _, err := QueryWith[Row](context.Background(), m, db, "q"); err != nil {
+		t.Fatal(err)
+	}
+	calls = 0 // the struct index is built; only column names remain
+	got, err := QueryWith[Row](context.Background(), m, db, "q")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 100 || got[99].ID != 99 {
+		t.Fatalf("unexpected rows: %d", len(got))
+	}
+	if calls != 2 {
+		t.Fatalf("column names normalized %d times for 100 rows; want 2", calls)
+	}
+}
+
 func TestQuery_Primitive_MultiRows(t *testing.T) {
 	db := newTestDB(t, func(q string, _ []driver.NamedValue) ([]string, [][]driver.Value, error) {
 		return []string{"n"}, [][]driver.Value{{int64(10)}, {int64(20)}, {int64(30)}}, nil
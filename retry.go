@@ -0,0 +1,184 @@
+package xsql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math/rand/v2"
+	"reflect"
+	"time"
+)
+
+// RetryPolicy configures [WithTxRetry]. The zero value retries up to three
+// attempts in total on the errors [IsRetryable] recognizes, waiting 10ms, then
+// 20ms, and so on (capped at one second), with random jitter.
+type RetryPolicy struct {
+	MaxAttempts int           // total attempts, including the first; <1 means 3
+	BaseDelay   time.Duration // wait before the second attempt, doubled each time; <=0 means 10ms
+	MaxDelay    time.Duration // upper bound on one wait; <=0 means 1s
+
+	// Retryable reports whether an attempt that failed with err should be
+	// retried; nil means IsRetryable. Combine matchers for other drivers'
+	// errors: func(err error) bool { return xsql.IsRetryable(err) || isMyError(err) }.
+	Retryable func(err error) bool
+
+	// TxOptions begins every attempt's transaction, as with WithTxOptions.
+	TxOptions *sql.TxOptions
+}
+
+// WithTxRetry runs fn in a transaction like [WithTx] and, when the attempt
+// fails with a retryable error — a serialization failure or deadlock, raised
+// by a statement or by the commit — rolls back and runs fn again in a new
+// transaction, with exponential backoff, until it succeeds, fails otherwise,
+// or policy.MaxAttempts is reached. Waiting stops early when ctx is done.
+//
+// fn may run several times, so it must not have effects outside the
+// transaction (sending mail, mutating captured state) that cannot be repeated.
+// When every attempt fails, the returned error wraps the last one.
+//
+// Example:
+//
+//	err := xsql.WithTxRetry(ctx, db, xsql.RetryPolicy{
+//	    MaxAttempts: 5,
+//	    TxOptions:   xsql.TxOptions(xsql.TxIsolation(sql.LevelSerializable)),
+//	}, func(tx *sql.Tx) error {
+//	    return transfer(ctx, tx, from, to, amount)
+//	})
+func WithTxRetry(ctx context.Context, db Beginner, policy RetryPolicy, fn func(tx *sql.Tx) error) error {
+	attempts := policy.MaxAttempts
+	if attempts < 1 {
+		attempts = 3
+	}
+	retryable := policy.Retryable
+	if retryable == nil {
+		retryable = IsRetryable
+	}
+	var err error
+	for attempt := 1; ; attempt++ {
+		err = runTx(ctx, db, policy.TxOptions, fn)
+		if err == nil || !retryable(err) {
+			return err
+		}
+		if attempt == attempts {
+			return fmt.Errorf("xsql: transaction failed after %d attempts: %w", attempts, err)
+		}
+		t := time.NewTimer(policy.backoff(attempt))
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return fmt.Errorf("xsql: transaction retry: %w (last error: %w)", ctx.Err(), err)
+		case <-t.C:
+		}
+	}
+}
+
+// backoff returns the wait after the given failed attempt: half of
+// BaseDelay·2^(attempt-1), capped at MaxDelay, plus a random amount up to the
+// other half.
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	base, ceil := p.BaseDelay, p.MaxDelay
+	if base <= 0 {
+		base = 10 * time.Millisecond
+	}
+	if ceil <= 0 {
+		ceil = time.Second
+	}
+	d := base
+	for i := 1; i < attempt && d < ceil; i++ {
+		d *= 2
+	}
+	d = min(d, ceil)
+	half := d / 2
+	return half + rand.N(d-half+1)
+}
+
+// IsRetryable reports whether err, or any error it wraps, is a transient
+// transaction conflict that succeeds when the transaction is run again:
+//
+//   - SQLSTATE 40001 (serialization failure) or 40P01 (deadlock), from errors
+//     with a SQLState() string method (pgx, lib/pq) or a string Code field;
+//   - error number 1213 (MySQL deadlock) or 1205 (MySQL lock wait timeout,
+//     SQL Server deadlock victim), from errors with a SQLErrorNumber() method
+//     (go-mssqldb) or an integer Number field (go-sql-driver/mysql).
+//
+// It is the default matcher of [RetryPolicy].
+func IsRetryable(err error) bool {
+	return anyError(err, func(e error) bool {
+		switch sqlState(e) {
+		case "40001", "40P01":
+			return true
+		}
+		switch n, _ := errorNumber(e); n {
+		case 1213, 1205:
+			return true
+		}
+		return false
+	})
+}
+
+// anyError reports whether fn holds for err or any error in its tree.
+func anyError(err error, fn func(error) bool) bool {
+	if err == nil {
+		return false
+	}
+	if fn(err) {
+		return true
+	}
+	switch u := err.(type) {
+	case interface{ Unwrap() error }:
+		return anyError(u.Unwrap(), fn)
+	case interface{ Unwrap() []error }:
+		for _, e := range u.Unwrap() {
+			if anyError(e, fn) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// sqlState returns the SQLSTATE code of a driver error, if it exposes one.
+func sqlState(err error) string {
+	if s, ok := err.(interface{ SQLState() string }); ok {
+		return s.SQLState()
+	}
+	if f, ok := errorField(err, "Code"); ok && f.Kind() == reflect.String {
+		return f.String()
+	}
+	return ""
+}
+
+// errorNumber returns the vendor error number of a driver error, if it
+// exposes one.
+func errorNumber(err error) (int64, bool) {
+	if n, ok := err.(interface{ SQLErrorNumber() int32 }); ok {
+		return int64(n.SQLErrorNumber()), true
+	}
+	f, ok := errorField(err, "Number")
+	if !ok {
+		return 0, false
+	}
+	switch f.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return f.Int(), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int64(f.Uint()), true
+	}
+	return 0, false
+}
+
+// errorField returns the named field of a struct error or pointer to one.
+func errorField(err error, name string) (reflect.Value, bool) {
+	v := reflect.ValueOf(err)
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return reflect.Value{}, false
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return reflect.Value{}, false
+	}
+	f := v.FieldByName(name)
+	return f, f.IsValid()
+}
@@ -0,0 +1,128 @@
+package xsql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testPgError struct{ code string }
+
+func (e *testPgError) Error() string    { return "pg: " + e.code }
+func (e *testPgError) SQLState() string { return e.code }
+
+type testMySQLError struct {
+	Number  uint16
+	Message string
+}
+
+func (e *testMySQLError) Error() string { return e.Message }
+
+type testMSSQLError struct{ n int32 }
+
+func (e testMSSQLError) Error() string         { return "mssql" }
+func (e testMSSQLError) SQLErrorNumber() int32 { return e.n }
+
+type testPqError struct{ Code string }
+
+func (e *testPqError) Error() string { return "pq: " + e.Code }
+
+func TestIsRetryable(t *testing.T) {
+	for err, want := range map[error]bool{
+		&testPgError{"40001"}:                                 true,
+		&testPgError{"40P01"}:                                 true,
+		&testPgError{"23505"}:                                 false,
+		&testPqError{"40001"}:                                 true,
+		&testMySQLError{Number: 1213}:                         true,
+		&testMySQLError{Number: 1062}:                         false,
+		testMSSQLError{1205}:                                  true,
+		fmt.Errorf("xsql: commit: %w", &testPgError{"40001"}): true,
+		errors.Join(errors.New("x"), testMSSQLError{1205}):    true,
+		errors.New("40001"):                                   false,
+		(*testMySQLError)(nil):                                false,
+	} {
+		if got := IsRetryable(err); got != want {
+			t.Fatalf("%v: got %v", err, got)
+		}
+	}
+	if IsRetryable(nil) {
+		t.Fatal("nil is not retryable")
+	}
+}
+
+func TestWithTxRetry(t *testing.T) {
+	ctx := context.Background()
+	var log []string
+	db := newTxDB(t, &log, nil)
+	fast := RetryPolicy{BaseDelay: time.Microsecond}
+
+	calls := 0
+	err := WithTxRetry(ctx, db, fast, func(tx *sql.Tx) error {
+		calls++
+		if calls < 3 {
+			return &testPgError{"40001"}
+		}
+		return nil
+	})
+	if err != nil || calls != 3 || strings.Join(log, ",") != "begin,rollback,begin,rollback,begin,commit" {
+		t.Fatalf("retried: %v, calls=%d, log=%q", err, calls, log)
+	}
+
+	calls = 0
+	err = WithTxRetry(ctx, db, fast, func(*sql.Tx) error { calls++; return &testMySQLError{Number: 1213} })
+	var myErr *testMySQLError
+	if calls != 3 || !errors.As(err, &myErr) || !strings.Contains(err.Error(), "after 3 attempts") {
+		t.Fatalf("exhausted: %v, calls=%d", err, calls)
+	}
+
+	calls = 0
+	boom := errors.New("boom")
+	if err := WithTxRetry(ctx, db, fast, func(*sql.Tx) error { calls++; return boom }); err != boom || calls != 1 {
+		t.Fatalf("not retryable: %v, calls=%d", err, calls)
+	}
+
+	// Custom matcher and options.
+	log, calls = nil, 0
+	policy := RetryPolicy{
+		MaxAttempts: 2,
+		BaseDelay:   time.Microsecond,
+		Retryable:   func(err error) bool { return errors.Is(err, boom) },
+		TxOptions:   TxOptions(TxReadOnly()),
+	}
+	if err := WithTxRetry(ctx, db, policy, func(*sql.Tx) error { calls++; return boom }); !errors.Is(err, boom) || calls != 2 {
+		t.Fatalf("custom: %v, calls=%d", err, calls)
+	}
+	if log[0] != "begin read-only" {
+		t.Fatalf("log = %q", log)
+	}
+
+	cctx, cancel := context.WithCancel(ctx)
+	slow := RetryPolicy{BaseDelay: time.Hour}
+	err = WithTxRetry(cctx, db, slow, func(*sql.Tx) error { cancel(); return &testPgError{"40P01"} })
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("canceled: %v", err)
+	}
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	p := RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: 300 * time.Millisecond}
+	for attempt, bounds := range map[int][2]time.Duration{
+		1: {50 * time.Millisecond, 100 * time.Millisecond},
+		2: {100 * time.Millisecond, 200 * time.Millisecond},
+		3: {150 * time.Millisecond, 300 * time.Millisecond},
+		9: {150 * time.Millisecond, 300 * time.Millisecond},
+	} {
+		for range 20 {
+			if d := p.backoff(attempt); d < bounds[0] || d > bounds[1] {
+				t.Fatalf("attempt %d: %v outside %v", attempt, d, bounds)
+			}
+		}
+	}
+	if d := (RetryPolicy{}).backoff(1); d < 5*time.Millisecond || d > 10*time.Millisecond {
+		t.Fatalf("default: %v", d)
+	}
+}
@@ -0,0 +1,107 @@
+package xsql
+
+import (
+	"database/sql"
+	"reflect"
+)
+
+// RowScanner is implemented by types that scan rows themselves. When T (or,
+// for pointer T, its element type) implements it, Query, Get, ScanRow and the
+// functions built on them skip the mapper and call ScanRow for each row, on a
+// fresh value, after rows.Next. It is an escape hatch for variable column
+// layouts and dynamic schemas; the method must not call rows.Next or
+// rows.Close.
+//
+// Query2, Query2Split, QueryJoined, and QueryPairs ignore RowScanner and use
+// the mapper: they split each row between two destinations, while ScanRow
+// reads the whole row.
+//
+// Example:
+//
+//	type Record struct{ Fields map[string]any }
+//
+//	func (r *Record) ScanRow(rows *sql.Rows) error {
+//	    cols, err := rows.Columns()
+//	    if err != nil {
+//	        return err
+//	    }
+//	    vals := make([]any, len(cols))
+//	    ptrs := make([]any, len(cols))
+//	    for i := range vals {
+//	        ptrs[i] = &vals[i]
+//	    }
+//	    if err := rows.Scan(ptrs...); err != nil {
+//	        return err
+//	    }
+//	    r.Fields = make(map[string]any, len(cols))
+//	    for i, c := range cols {
+//	        r.Fields[c] = vals[i]
+//	    }
+//	    return nil
+//	}
+type RowScanner interface {
+	ScanRow(rows *sql.Rows) error
+}
+
+var rowScannerType = reflect.TypeOf((*RowScanner)(nil)).Elem()
+
+// scanOwn scans the current row through T's RowScanner implementation. ok is
+// false, and nothing is read, if T does not implement it.
+func scanOwn[T any](rows *sql.Rows) (v T, ok bool, err error) {
+	if rs, ok := any(&v).(RowScanner); ok {
+		err = rs.ScanRow(rows)
+		return v, true, err
+	}
+	rt := reflect.TypeOf(v)
+	if rt == nil || rt.Kind() != reflect.Ptr || !rt.Implements(rowScannerType) {
+		return v, false, nil
+	}
+	pv := reflect.New(rt.Elem())
+	if err := pv.Interface().(RowScanner).ScanRow(rows); err != nil {
+		return v, true, err
+	}
+	return pv.Interface().(T), true, nil
+}
+
+// ScanRow scans the current row of rows into T with the same mapping rules and
+// plan cache as [Query] and [Get]. Use it when rows come from your own code,
+// such as a tracing wrapper or database/sql adapter, rather than from xsql.
+//
+// ScanRow does not advance or close rows: call rows.Next before each ScanRow,
+// and check rows.Err and close rows as usual.
+//
+// Example:
+//
+//	rows, err := tracedDB.QueryContext(ctx, `SELECT id, email FROM users`)
+//	if err != nil {
+//	    return err
+//	}
+//	defer rows.Close()
+//	for rows.Next() {
+//	    u, err := xsql.ScanRow[User](rows)
+//	    if err != nil {
+//	        return err
+//	    }
+//	    handle(u)
+//	}
+//	return rows.Err()
+func ScanRow[T any](rows *sql.Rows) (T, error) {
+	return scanWithMapper[T](getMapper(), rows)
+}
+
+// ScanAll scans every remaining row of rows into a slice of T, exactly like
+// [Query] does with the rows it obtains itself, and closes rows. It returns
+// the first scan error, rows.Err, or the Close error, in that order of
+// precedence. A result without rows yields a nil slice and a nil error.
+//
+// Example:
+//
+//	rows, err := tracedDB.QueryContext(ctx, `SELECT id, email FROM users`)
+//	if err != nil {
+//	    return err
+//	}
+//	users, err := xsql.ScanAll[User](rows)
+func ScanAll[T any](rows *sql.Rows) ([]T, error) {
+	m := getMapper()
+	return scanAll[T](m, m.Strict, rows)
+}
@@ -0,0 +1,159 @@
+package xsql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"testing"
+)
+
+func TestScanRow_UserManagedRows(t *testing.T) {
+	type Row struct {
+		ID   int64  `db:"id"`
+		Name string `db:"name"`
+	}
+	db := newTestDB(t, func(string, []driver.NamedValue) ([]string, [][]driver.Value, error) {
+		return []string{"id", "name"}, [][]driver.Value{{int64(1), "a"}, {int64(2), "b"}}, nil
+	})
+	defer func() { _ = db.Close() }()
+
+	rows, err := db.QueryContext(context.Background(), "q")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var got []Row
+	for rows.Next() {
+		r, err := ScanRow[Row](rows)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, r)
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0] != (Row{1, "a"}) || got[1] != (Row{2, "b"}) {
+		t.Fatalf("unexpected rows: %+v", got)
+	}
+}
+
+func TestScanRow_WithoutNext_Error(t *testing.T) {
+	db := newTestDB(t, func(string, []driver.NamedValue) ([]string, [][]driver.Value, error) {
+		return []string{"n"}, [][]driver.Value{{int64(1)}}, nil
+	})
+	defer func() { _ = db.Close() }()
+
+	rows, err := db.QueryContext(context.Background(), "q")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = rows.Close() }()
+	if _, err := ScanRow[int64](rows); err == nil {
+		t.Fatalf("expected error scanning before Next")
+	}
+}
+
+func TestScanAll_ClosesRows(t *testing.T) {
+	db := newTestDB(t, func(string, []driver.NamedValue) ([]string, [][]driver.Value, error) {
+		return []string{"n"}, [][]driver.Value{{int64(1)}, {int64(2)}, {int64(3)}}, nil
+	})
+	defer func() { _ = db.Close() }()
+
+	rows, err := db.QueryContext(context.Background(), "q")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := ScanAll[int64](rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 3 || got[0] != 1 || got[2] != 3 {
+		t.Fatalf("unexpected: %v", got)
+	}
+	if rows.Next() {
+		t.Fatalf("rows should be closed")
+	}
+	if db.Stats().InUse != 0 {
+		t.Fatalf("connection should be released, in use: %d", db.Stats().InUse)
+	}
+}
+
+func TestScanAll_ScanError(t *testing.T) {
+	db := newTestDB(t, func(string, []driver.NamedValue) ([]string, [][]driver.Value, error) {
+		return []string{"a", "b"}, [][]driver.Value{{int64(1), int64(2)}}, nil
+	})
+	defer func() { _ = db.Close() }()
+
+	rows, err := db.QueryContext(context.Background(), "q")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ScanAll[int64](rows); err == nil {
+		t.Fatalf("expected error mapping two columns into int64")
+	}
+	if db.Stats().InUse != 0 {
+		t.Fatalf("connection should be released after an error")
+	}
+}
+
+type dynRecord struct {
+	Fields map[string]any
+}
+
+func (r *dynRecord) ScanRow(rows *sql.Rows) error {
+	cols, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+	vals := make([]any, len(cols))
+	ptrs := make([]any, len(cols))
+	for i := range vals {
+		ptrs[i] = &vals[i]
+	}
+	if err := rows.Scan(ptrs...); err != nil {
+		return err
+	}
+	r.Fields = make(map[string]any, len(cols))
+	for i, c := range cols {
+		r.Fields[c] = vals[i]
+	}
+	return nil
+}
+
+func TestRowScanner_BypassesMapper(t *testing.T) {
+	db := newTestDB(t, func(q string, _ []driver.NamedValue) ([]string, [][]driver.Value, error) {
+		if q == "wide" {
+			return []string{"a", "b", "c"}, [][]driver.Value{{int64(1), "x", nil}}, nil
+		}
+		return []string{"k"}, [][]driver.Value{{"v1"}, {"v2"}}, nil
+	})
+	defer func() { _ = db.Close() }()
+	ctx := context.Background()
+
+	got, err := Query[dynRecord](ctx, db, "narrow")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[1].Fields["k"] != "v2" {
+		t.Fatalf("unexpected records: %+v", got)
+	}
+
+	ptr, err := Get[*dynRecord](ctx, db, "wide", WithStrict())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ptr.Fields) != 3 || ptr.Fields["a"] != int64(1) || ptr.Fields["c"] != nil {
+		t.Fatalf("unexpected record: %+v", ptr)
+	}
+
+	stmt, err := Prepare[dynRecord](ctx, db, "narrow")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = stmt.Close() }()
+	if recs, err := stmt.Query(ctx); err != nil || len(recs) != 2 || recs[0].Fields["k"] != "v1" {
+		t.Fatalf("Stmt.Query = %+v, %v", recs, err)
+	}
+}
@@ -0,0 +1,156 @@
+package xsql
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// nullColumnError is the error for a NULL column scanned into a destination
+// that cannot hold it. It matches ErrNullColumn and wraps the original error.
+type nullColumnError struct {
+	row   int          // zero-based row index, -1 if unknown
+	col   string       // result column name
+	field string       // destination, e.g. "main.User.Address.Zip" or "int64"
+	typ   reflect.Type // field type; nil for non-struct destinations
+	err   error
+}
+
+func (e *nullColumnError) Error() string {
+	msg := fmt.Sprintf("%v column %q into %s", ErrNullColumn, e.col, e.field)
+	if e.typ != nil {
+		msg += " (" + e.typ.String() + ")"
+	}
+	if e.row >= 0 {
+		msg += fmt.Sprintf(" at row %d", e.row)
+	}
+	return msg + ": " + e.err.Error()
+}
+
+func (e *nullColumnError) Unwrap() []error { return []error{ErrNullColumn, e.err} }
+
+// columnError marks a conversion error from the cleanup of plan p with the
+// plan's column index, so scanError can tell which column it came from.
+type columnError struct {
+	p   *plan
+	idx int
+	err error
+}
+
+func (e *columnError) Error() string { return e.err.Error() }
+func (e *columnError) Unwrap() error { return e.err }
+
+// colSpan places the columns of plan p at offset off of the row. Plans built
+// over masked column lists share offset 0; their dropped steps are skipped.
+type colSpan struct {
+	p   *plan
+	off int
+}
+
+// scanError returns err, from rows.Scan or a plan cleanup, with any columnError
+// removed, or a nullColumnError if it was caused by a NULL in the column that
+// failed. The row is scanned again to find that column and check its value,
+// so it must still be current. row is the zero-based row index, or -1 if the
+// caller does not know.
+func scanError(rows *sql.Rows, row int, err error, spans ...colSpan) error {
+	cols, cerr := rows.Columns()
+	if cerr != nil {
+		return err
+	}
+	col, cause := -1, err
+	var ce *columnError
+	if errors.As(err, &ce) {
+		cause = ce.err
+		for _, s := range spans {
+			if s.p == ce.p {
+				col = s.off + ce.idx
+				break
+			}
+		}
+	} else {
+		col = failedColumn(rows, len(cols), spans)
+	}
+	if col < 0 || col >= len(cols) {
+		return cause
+	}
+	vals := make([]any, len(cols))
+	ptrs := make([]any, len(cols))
+	for i := range vals {
+		ptrs[i] = &vals[i]
+	}
+	if rows.Scan(ptrs...) != nil || vals[col] != nil {
+		return cause
+	}
+	for _, s := range spans {
+		i := col - s.off
+		if i < 0 || i >= len(s.p.steps) || s.p.steps[i].kind == stepDrop {
+			continue
+		}
+		e := &nullColumnError{row: row, col: cols[col], field: s.p.rt.String(), err: cause}
+		if s.p.isStruct {
+			fp := s.p.steps[i].fpath
+			e.field += "." + fieldNameByPath(s.p.rt, fp)
+			e.typ = fieldTypeByPath(s.p.rt, fp)
+		}
+		return e
+	}
+	return cause
+}
+
+// failedColumn returns the index of the first of the n columns of the current
+// row that rows.Scan cannot store in the destination the spans give it, or -1.
+// Each column is scanned on its own into a fresh destination of that type,
+// the others into throwaway values, in the order database/sql converts them.
+func failedColumn(rows *sql.Rows, n int, spans []colSpan) int {
+	sinks := make([]any, n)
+	ptrs := make([]any, n)
+	for i := range sinks {
+		ptrs[i] = &sinks[i]
+	}
+	for col := range n {
+		t := destType(col, spans)
+		if t == nil {
+			continue
+		}
+		ptrs[col] = reflect.New(t).Interface()
+		err := rows.Scan(ptrs...)
+		ptrs[col] = &sinks[col]
+		if err != nil {
+			return col
+		}
+	}
+	return -1
+}
+
+// destType returns the type of the value the spans pass to rows.Scan for
+// column col, or nil if the column is dropped.
+func destType(col int, spans []colSpan) reflect.Type {
+	for _, s := range spans {
+		i := col - s.off
+		if i < 0 || i >= len(s.p.steps) {
+			continue
+		}
+		switch st := s.p.steps[i]; {
+		case st.kind == stepDrop:
+			continue
+		case st.kind == stepIndirect:
+			return st.convTo
+		case s.p.isStruct:
+			return fieldTypeByPath(s.p.rt, st.fpath)
+		default:
+			return s.p.rt
+		}
+	}
+	return nil
+}
+
+// atRow records the zero-based row index on a NULL column error from a
+// single-row scan.
+func atRow(err error, row int) error {
+	var ne *nullColumnError
+	if errors.As(err, &ne) && ne.row < 0 {
+		ne.row = row
+	}
+	return err
+}
@@ -0,0 +1,70 @@
+package xsql
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestNullColumnErrors(t *testing.T) {
+	type Stats struct {
+		Visits int64 `db:"visits"`
+		Active bool  `db:"active"`
+	}
+	type Row struct {
+		ID    int64 `db:"id"`
+		Stats `db:",inline"`
+	}
+	db := newTestDB(t, func(q string, _ []driver.NamedValue) ([]string, [][]driver.Value, error) {
+		switch q {
+		case "visits":
+			return []string{"id", "visits", "active"}, [][]driver.Value{
+				{int64(1), int64(3), true},
+				{int64(2), nil, true},
+			}, nil
+		case "active":
+			return []string{"id", "visits", "active"}, [][]driver.Value{{int64(1), int64(3), nil}}, nil
+		case "pairs":
+			return []string{"id", "visits"}, [][]driver.Value{{int64(1), int64(3)}, {int64(2), nil}}, nil
+		case "one":
+			return []string{"n"}, [][]driver.Value{{nil}}, nil
+		}
+		return []string{"id", "visits", "active"}, [][]driver.Value{{"x", int64(3), true}}, nil
+	})
+	defer func() { _ = db.Close() }()
+	ctx := context.Background()
+
+	cases := []struct {
+		name string
+		err  func() error
+		want []string
+	}{
+		{"direct", func() error { _, err := Query[Row](ctx, db, "visits"); return err },
+			[]string{`column "visits"`, "xsql.Row.Stats.Visits (int64)", "at row 1", "converting NULL"}},
+		{"indirect", func() error { _, err := Get[Row](ctx, db, "active"); return err },
+			[]string{`column "active"`, "xsql.Row.Stats.Active (bool)", "at row 0"}},
+		{"pairs", func() error { _, err := QueryPairs[int64, int](ctx, db, "pairs"); return err },
+			[]string{`column "visits" into int at row 1`}},
+		{"non-struct", func() error { _, err := Get[int](ctx, db, "one"); return err },
+			[]string{`column "n" into int at row 0`}},
+	}
+	for _, c := range cases {
+		err := c.err()
+		if !errors.Is(err, ErrNullColumn) {
+			t.Fatalf("%s: expected ErrNullColumn, got %v", c.name, err)
+		}
+		for _, w := range c.want {
+			if !strings.Contains(err.Error(), w) {
+				t.Fatalf("%s: %q missing from %v", c.name, w, err)
+			}
+		}
+	}
+
+	// Errors other than NULL conversions are passed through.
+	_, err := Query[Row](ctx, db, "bad")
+	if err == nil || errors.Is(err, ErrNullColumn) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
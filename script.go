@@ -0,0 +1,125 @@
+package xsql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// ExecScript splits script into statements on top-level semicolons and executes
+// them in order on e, stopping at the first error.
+//
+// Splitting uses the same SQL scanner as [Rebind]: semicolons inside quoted
+// strings and identifiers, line and block comments, and PostgreSQL
+// $tag$…$tag$ bodies do not end a statement, so function definitions survive
+// intact. Empty statements and statements consisting only of comments are
+// skipped. Procedural blocks that use bare semicolons outside of dollar quoting
+// (e.g. SQLite CREATE TRIGGER … BEGIN … END) are not recognized; run those with
+// [Exec] instead.
+//
+// The returned error names the 1-based statement that failed and wraps the
+// driver error. ExecScript does not open a transaction; use [ExecScriptTx] to
+// apply the script atomically.
+//
+// Example:
+//
+//	err := xsql.ExecScript(ctx, db, `
+//	    CREATE TABLE users (id INTEGER PRIMARY KEY, email TEXT);
+//	    INSERT INTO users (email) VALUES ('a@example.com'); -- seed
+//	`)
+func ExecScript(ctx context.Context, e Execer, script string) error {
+	stmts, err := splitStatements(script)
+	if err != nil {
+		return err
+	}
+	return execStatements(ctx, e, stmts)
+}
+
+// execStatements executes stmts in order, stopping at the first error.
+func execStatements(ctx context.Context, e Execer, stmts []string) error {
+	for i, s := range stmts {
+		if _, err := e.ExecContext(ctx, s); err != nil {
+			return fmt.Errorf("xsql: script statement %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+// ExecScriptTx is like [ExecScript] but runs all statements inside a single
+// transaction started on db. The transaction is committed if every statement
+// succeeds and rolled back otherwise.
+//
+// Note that some engines (e.g. MySQL, Oracle) implicitly commit DDL statements,
+// so a failing script may still leave earlier DDL applied there.
+func ExecScriptTx(ctx context.Context, db Beginner, script string) error {
+	stmts, err := splitStatements(script)
+	if err != nil {
+		return err
+	}
+	return WithTx(ctx, db, func(tx *sql.Tx) error {
+		return execStatements(ctx, tx, stmts)
+	})
+}
+
+// splitStatements splits script on semicolons that are not inside quotes,
+// comments, or dollar-quoted bodies. Returned statements are trimmed; blank and
+// comment-only statements are dropped.
+func splitStatements(script string) ([]string, error) {
+	var out []string
+	emit := func(s string) error {
+		blank, err := isBlankSQL(s)
+		if err != nil {
+			return err
+		}
+		if !blank {
+			out = append(out, strings.TrimSpace(s))
+		}
+		return nil
+	}
+
+	start, i := 0, 0
+	for i < len(script) {
+		j, err := skipNonCode(script, i)
+		if err != nil {
+			return nil, err
+		}
+		if j > i {
+			i = j
+			continue
+		}
+		if script[i] == ';' {
+			if err := emit(script[start:i]); err != nil {
+				return nil, err
+			}
+			start = i + 1
+		}
+		i++
+	}
+	if err := emit(script[start:]); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// isBlankSQL reports whether s contains only whitespace and comments.
+func isBlankSQL(s string) (bool, error) {
+	i := 0
+	for i < len(s) {
+		switch {
+		case s[i] == ' ' || s[i] == '\t' || s[i] == '\n' || s[i] == '\r' || s[i] == '\f':
+			i++
+		case hasPrefix(s[i:], "--"):
+			i = skipLineComment(s, i+2)
+		case hasPrefix(s[i:], "/*"):
+			j, err := skipBlockComment(s, i+2)
+			if err != nil {
+				return false, err
+			}
+			i = j
+		default:
+			return false, nil
+		}
+	}
+	return true, nil
+}
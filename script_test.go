@@ -0,0 +1,102 @@
+package xsql
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSplitStatements(t *testing.T) {
+	script := `
+CREATE TABLE t (v TEXT DEFAULT 'a;b');
+-- comment; with semicolon
+INSERT INTO "we;ird" VALUES ('it''s;');
+/* block; comment */
+CREATE FUNCTION f() RETURNS int AS $body$ BEGIN RETURN 1; END; $body$ LANGUAGE plpgsql;;
+SELECT ` + "`a;b`" + `;
+-- trailing comment only
+`
+	got, err := splitStatements(script)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		`CREATE TABLE t (v TEXT DEFAULT 'a;b')`,
+		"-- comment; with semicolon\nINSERT INTO \"we;ird\" VALUES ('it''s;')",
+		"/* block; comment */\nCREATE FUNCTION f() RETURNS int AS $body$ BEGIN RETURN 1; END; $body$ LANGUAGE plpgsql",
+		"SELECT `a;b`",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("split mismatch:\n got=%q\nwant=%q", got, want)
+	}
+}
+
+func TestSplitStatements_Unterminated(t *testing.T) {
+	if _, err := splitStatements(`SELECT 'oops; SELECT 1`); err == nil {
+		t.Fatal("expected error for unterminated string")
+	}
+	if _, err := splitStatements("SELECT 1; /* open"); err == nil {
+		t.Fatal("expected error for unterminated comment")
+	}
+}
+
+func TestExecScript_RunsInOrderAndStopsOnError(t *testing.T) {
+	var seen []string
+	sentinel := errors.New("boom")
+	db := newExecDB(t, func(query string, args []driver.NamedValue) (driver.Result, error) {
+		seen = append(seen, query)
+		if strings.HasPrefix(query, "BAD") {
+			return nil, sentinel
+		}
+		return testResult{}, nil
+	})
+	defer func() { _ = db.Close() }()
+
+	ctx := context.Background()
+	if err := ExecScript(ctx, db, "CREATE TABLE a (x INT); INSERT INTO a VALUES (1);"); err != nil {
+		t.Fatalf("ExecScript: %v", err)
+	}
+	if len(seen) != 2 || seen[0] != "CREATE TABLE a (x INT)" || seen[1] != "INSERT INTO a VALUES (1)" {
+		t.Fatalf("unexpected statements: %q", seen)
+	}
+
+	seen = nil
+	err := ExecScript(ctx, db, "SELECT 1; BAD; SELECT 2")
+	if !errors.Is(err, sentinel) || !strings.Contains(err.Error(), "statement 2") {
+		t.Fatalf("want wrapped error for statement 2, got %v", err)
+	}
+	if len(seen) != 2 {
+		t.Fatalf("should stop after failure, ran %q", seen)
+	}
+}
+
+func TestExecScriptTx_CommitAndRollback(t *testing.T) {
+	var seen []string
+	db := newExecDB(t, func(query string, args []driver.NamedValue) (driver.Result, error) {
+		seen = append(seen, query)
+		if query == "BAD" {
+			return nil, errors.New("boom")
+		}
+		return testResult{}, nil
+	})
+	defer func() { _ = db.Close() }()
+
+	ctx := context.Background()
+	if err := ExecScriptTx(ctx, db, "SELECT 1; SELECT 2"); err != nil {
+		t.Fatalf("ExecScriptTx: %v", err)
+	}
+	if want := []string{"SELECT 1", "SELECT 2", "COMMIT"}; !reflect.DeepEqual(seen, want) {
+		t.Fatalf("got %q want %q", seen, want)
+	}
+
+	seen = nil
+	if err := ExecScriptTx(ctx, db, "SELECT 1; BAD; SELECT 2"); err == nil {
+		t.Fatal("expected error")
+	}
+	if want := []string{"SELECT 1", "BAD", "ROLLBACK"}; !reflect.DeepEqual(seen, want) {
+		t.Fatalf("got %q want %q", seen, want)
+	}
+}
@@ -0,0 +1,224 @@
+package xsql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"sync/atomic"
+)
+
+// ErrTooManyRows is returned by Stmt.One when the query yields more than one row.
+var ErrTooManyRows = errors.New("xsql: expected exactly one row, got more")
+
+// Stmt is a prepared statement whose results are scanned into T.
+//
+// It combines a driver-level prepared statement ([sql.Stmt]) with a pinned scan
+// plan: the plan is resolved on the first execution and reused for as long as
+// the statement keeps returning the same columns, so repeated executions skip
+// column normalization, hashing, and the plan-cache lookup entirely.
+//
+// A Stmt is safe for concurrent use by multiple goroutines. Close it when it
+// is no longer needed.
+type Stmt[T any] struct {
+	stmt *sql.Stmt
+	m    *Mapper
+	pin  *atomic.Pointer[pinnedPlan]
+}
+
+// pinnedPlan is a plan bound to the raw (unnormalized) column names it was
+// resolved for.
+type pinnedPlan struct {
+	cols []string
+	pl   *plan
+}
+
+// Prepare creates a prepared statement for later queries whose rows are
+// scanned into T. db may be a *sql.DB, *sql.Tx, or *sql.Conn; the returned
+// statement follows the lifetime rules of [sql.Stmt] for that handle.
+//
+// Example:
+//
+//	stmt, err := xsql.Prepare[User](ctx, db, `SELECT id, email FROM users WHERE id = $1`)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	defer stmt.Close()
+//
+//	u, err := stmt.Get(ctx, 42)
+func Prepare[T any](ctx context.Context, db Preparer, query string) (*Stmt[T], error) {
+	st, err := db.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return &Stmt[T]{stmt: st, m: getMapper(), pin: new(atomic.Pointer[pinnedPlan])}, nil
+}
+
+// WithTx returns a transaction-specific version of s, mirroring [sql.Tx.Stmt].
+// The returned statement shares s's pinned scan plan, so it does not re-plan
+// on its first execution. It is closed automatically when the transaction
+// commits or rolls back.
+//
+// Example:
+//
+//	getUser, _ := xsql.Prepare[User](ctx, db, `SELECT id, email FROM users WHERE id = $1 FOR UPDATE`)
+//
+//	tx, _ := db.BeginTx(ctx, nil)
+//	u, err := getUser.WithTx(tx).Get(ctx, 42)
+func (s *Stmt[T]) WithTx(tx *sql.Tx) *Stmt[T] {
+	return &Stmt[T]{stmt: tx.Stmt(s.stmt), m: s.m, pin: s.pin}
+}
+
+// Close closes the underlying prepared statement.
+func (s *Stmt[T]) Close() error { return s.stmt.Close() }
+
+// Query executes the prepared statement with args and scans all rows into a
+// slice of T, like [Query]. args may include [QueryOpt] values; [WithMapper]
+// scans with another mapper without disturbing the pinned plan.
+func (s *Stmt[T]) Query(ctx context.Context, args ...any) (out []T, err error) {
+	args, cfg, err := callArgs(args)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := cfg.context(ctx)
+	defer cancel()
+
+	rows, err := s.stmt.QueryContext(ctx, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if cerr := rows.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}()
+
+	var pl *plan
+	for rows.Next() {
+		v, own, scanErr := scanOwn[T](rows)
+		if !own {
+			if pl == nil {
+				if pl, err = s.plan(rows, &cfg); err != nil {
+					return nil, err
+				}
+			}
+			v, scanErr = scanPlan[T](pl, rows)
+		}
+		if scanErr != nil {
+			return nil, atRow(scanErr, len(out))
+		}
+		out = append(out, v)
+	}
+	if ne := rows.Err(); ne != nil {
+		return nil, ne
+	}
+	return out, nil
+}
+
+// Get executes the prepared statement with args and scans the first row into
+// T, like [Get]. It returns [sql.ErrNoRows] if there are no rows.
+func (s *Stmt[T]) Get(ctx context.Context, args ...any) (T, error) {
+	return s.first(ctx, false, args)
+}
+
+// One is like Get but requires exactly one row: it returns [sql.ErrNoRows] if
+// there are none and [ErrTooManyRows] if there is more than one.
+func (s *Stmt[T]) One(ctx context.Context, args ...any) (T, error) {
+	return s.first(ctx, true, args)
+}
+
+func (s *Stmt[T]) first(ctx context.Context, exactlyOne bool, args []any) (out T, err error) {
+	args, cfg, err := callArgs(args)
+	if err != nil {
+		return out, err
+	}
+	ctx, cancel := cfg.context(ctx)
+	defer cancel()
+
+	rows, err := s.stmt.QueryContext(ctx, args...)
+	if err != nil {
+		return out, err
+	}
+	defer func() {
+		if cerr := rows.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}()
+
+	if !rows.Next() {
+		if ne := rows.Err(); ne != nil {
+			return out, ne
+		}
+		return out, sql.ErrNoRows
+	}
+	v, own, err := scanOwn[T](rows)
+	if !own {
+		var pl *plan
+		if pl, err = s.plan(rows, &cfg); err != nil {
+			return out, err
+		}
+		v, err = scanPlan[T](pl, rows)
+	}
+	if err != nil {
+		return out, atRow(err, 0)
+	}
+	if exactlyOne {
+		if rows.Next() {
+			return out, ErrTooManyRows
+		}
+		if ne := rows.Err(); ne != nil {
+			return out, ne
+		}
+	}
+	return v, nil
+}
+
+// plan returns the plan for the columns of rows, checked against the strict
+// mode of the call configured by cfg. The pinned plan is reused if rows has the
+// columns it was built for; otherwise a plan is resolved for the new column
+// set and pinned. A call scanning with another mapper than s's (see
+// [WithMapper]) resolves its plan through that mapper's cache and leaves the
+// pin alone.
+func (s *Stmt[T]) plan(rows *sql.Rows, cfg *callConfig) (*plan, error) {
+	m := cfg.mapper
+	if m == nil {
+		m = s.m
+	}
+	pl, err := s.resolve(rows, m)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.strict || m.Strict {
+		if err := pl.checkStrict(); err != nil {
+			return nil, err
+		}
+	}
+	return pl, nil
+}
+
+// resolve returns the plan of m for the columns of rows, pinning it if m is
+// s's mapper.
+func (s *Stmt[T]) resolve(rows *sql.Rows, m *Mapper) (*plan, error) {
+	cols, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	pinned := m == s.m
+	if pp := s.pin.Load(); pinned && pp != nil && slices.Equal(pp.cols, cols) {
+		return pp.pl, nil
+	}
+	if len(cols) == 0 {
+		return nil, fmt.Errorf("xsql: query returned zero columns")
+	}
+	raw := append([]string(nil), cols...)
+	pl, err := m.planFor(reflect.TypeOf((*T)(nil)).Elem(), cols)
+	if err != nil {
+		return nil, err
+	}
+	if pinned {
+		s.pin.Store(&pinnedPlan{cols: raw, pl: pl})
+	}
+	return pl, nil
+}
@@ -0,0 +1,192 @@
+package xsql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestPrepare_QueryGetOne_PinsPlan(t *testing.T) {
+	type Row struct {
+		ID   int64  `db:"id"`
+		Name string `db:"name"`
+	}
+	var rows [][]driver.Value
+	cols := []string{"id", "name"}
+	db := newTestDB(t, func(q string, args []driver.NamedValue) ([]string, [][]driver.Value, error) {
+		if q != "SELECT id, name FROM t WHERE id > ?" {
+			t.Fatalf("unexpected query: %q", q)
+		}
+		return cols, rows, nil
+	})
+	defer func() { _ = db.Close() }()
+
+	ctx := context.Background()
+	stmt, err := Prepare[Row](ctx, db, "SELECT id, name FROM t WHERE id > ?")
+	if err != nil {
+		t.Fatalf("Prepare: %v", err)
+	}
+	defer func() { _ = stmt.Close() }()
+
+	rows = [][]driver.Value{{int64(1), []byte("a")}, {int64(2), []byte("b")}}
+	all, err := stmt.Query(ctx, 0)
+	if err != nil {
+		t.Fatalf("Query: %v", err)
+	}
+	if len(all) != 2 || all[1].Name != "b" {
+		t.Fatalf("unexpected rows: %+v", all)
+	}
+	pinned := stmt.pin.Load()
+	if pinned == nil {
+		t.Fatal("plan not pinned after first execution")
+	}
+
+	got, err := stmt.Get(ctx, 0)
+	if err != nil || got.ID != 1 {
+		t.Fatalf("Get: %+v, %v", got, err)
+	}
+	if _, err := stmt.One(ctx, 0); !errors.Is(err, ErrTooManyRows) {
+		t.Fatalf("One: want ErrTooManyRows, got %v", err)
+	}
+	if stmt.pin.Load() != pinned {
+		t.Fatal("pinned plan should be reused for identical columns")
+	}
+
+	rows = [][]driver.Value{{int64(3), []byte("c")}}
+	if got, err := stmt.One(ctx, 0); err != nil || got.ID != 3 {
+		t.Fatalf("One: %+v, %v", got, err)
+	}
+
+	rows = nil
+	if _, err := stmt.Get(ctx, 0); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("Get: want sql.ErrNoRows, got %v", err)
+	}
+	if _, err := stmt.One(ctx, 0); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("One: want sql.ErrNoRows, got %v", err)
+	}
+
+	// A different column set re-plans and re-pins.
+	cols = []string{"name"}
+	rows = [][]driver.Value{{[]byte("only")}}
+	got, err = stmt.Get(ctx, 0)
+	if err != nil || got.Name != "only" || got.ID != 0 {
+		t.Fatalf("Get after column change: %+v, %v", got, err)
+	}
+	if stmt.pin.Load() == pinned {
+		t.Fatal("plan should be re-pinned after column change")
+	}
+}
+
+func TestPrepare_Errors(t *testing.T) {
+	sentinel := errors.New("boom")
+	db := newTestDB(t, func(q string, _ []driver.NamedValue) ([]string, [][]driver.Value, error) {
+		return nil, nil, sentinel
+	})
+	defer func() { _ = db.Close() }()
+
+	ctx := context.Background()
+	stmt, err := Prepare[int64](ctx, db, "SELECT 1")
+	if err != nil {
+		t.Fatalf("Prepare: %v", err)
+	}
+	defer func() { _ = stmt.Close() }()
+	if _, err := stmt.Query(ctx); !errors.Is(err, sentinel) {
+		t.Fatalf("Query: want %v, got %v", sentinel, err)
+	}
+	if _, err := stmt.Get(ctx); !errors.Is(err, sentinel) {
+		t.Fatalf("Get: want %v, got %v", sentinel, err)
+	}
+
+	db2 := newTestDB(t, func(q string, _ []driver.NamedValue) ([]string, [][]driver.Value, error) {
+		return []string{"a", "b"}, [][]driver.Value{{int64(1), int64(2)}}, nil
+	})
+	defer func() { _ = db2.Close() }()
+	stmt2, err := Prepare[int64](ctx, db2, "SELECT a, b")
+	if err != nil {
+		t.Fatalf("Prepare: %v", err)
+	}
+	defer func() { _ = stmt2.Close() }()
+	if _, err := stmt2.Query(ctx); err == nil {
+		t.Fatal("expected plan error for two columns into int64")
+	}
+}
+
+func TestStmt_WithTx_SharesPinnedPlan(t *testing.T) {
+	db := newTestDB(t, func(q string, _ []driver.NamedValue) ([]string, [][]driver.Value, error) {
+		return []string{"n"}, [][]driver.Value{{int64(7)}}, nil
+	})
+	defer func() { _ = db.Close() }()
+
+	ctx := context.Background()
+	stmt, err := Prepare[int64](ctx, db, "SELECT n")
+	if err != nil {
+		t.Fatalf("Prepare: %v", err)
+	}
+	defer func() { _ = stmt.Close() }()
+	if _, err := stmt.Get(ctx); err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	pinned := stmt.pin.Load()
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatalf("BeginTx: %v", err)
+	}
+	txStmt := stmt.WithTx(tx)
+	got, err := txStmt.Get(ctx)
+	if err != nil || got != 7 {
+		t.Fatalf("tx Get: %v, %v", got, err)
+	}
+	if txStmt.pin.Load() != pinned || stmt.pin.Load() != pinned {
+		t.Fatal("tx statement should reuse the pinned plan")
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+}
+
+func TestStmt_QueryOpts(t *testing.T) {
+	type Row struct {
+		ID int64 `db:"id"`
+	}
+	db := newTestDB(t, func(q string, args []driver.NamedValue) ([]string, [][]driver.Value, error) {
+		if len(args) != 1 {
+			t.Fatalf("options reached the driver: %v", args)
+		}
+		return []string{"id", "extra"}, [][]driver.Value{{int64(1), nil}}, nil
+	})
+	defer func() { _ = db.Close() }()
+
+	ctx := context.Background()
+	stmt, err := Prepare[Row](ctx, db, "q")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = stmt.Close() }()
+
+	if got, err := stmt.Query(ctx, 1, WithTimeout(time.Minute)); err != nil || len(got) != 1 || got[0].ID != 1 {
+		t.Fatalf("Query: %+v %v", got, err)
+	}
+	pinned := stmt.pin.Load()
+	if _, err := stmt.Get(ctx, 1, WithStrict()); !errors.Is(err, ErrUnmappedColumn) {
+		t.Fatalf("Get strict: %v", err)
+	}
+	if got, err := stmt.One(ctx, 1); err != nil || got.ID != 1 {
+		t.Fatalf("One after a strict call: %+v %v", got, err)
+	}
+
+	strict := NewMapper()
+	strict.Strict = true
+	if _, err := stmt.Query(ctx, 1, WithMapper(strict)); !errors.Is(err, ErrUnmappedColumn) {
+		t.Fatalf("Query WithMapper: %v", err)
+	}
+	if stmt.pin.Load() != pinned {
+		t.Fatal("a call with another mapper replaced the pinned plan")
+	}
+	if _, err := stmt.Query(ctx, 1, sql.Named("x", 1)); !errors.Is(err, ErrMixedArgs) {
+		t.Fatalf("Query mixed args: %v", err)
+	}
+}
@@ -0,0 +1,157 @@
+package xsql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+)
+
+// StreamJSON executes the SQL query, scans each row into T like [Query], and
+// writes it to w as JSON without holding the full result in memory. By default
+// the output is a single JSON array ("[]" when there are no rows); pass
+// [WithNDJSON] among the args for newline-delimited JSON instead.
+//
+// Rows are encoded with encoding/json, so T's `json` tags control the output.
+// If an error occurs mid-stream, w holds a truncated document.
+//
+// Example:
+//
+//	func exportUsers(w http.ResponseWriter, r *http.Request) {
+//	    w.Header().Set("Content-Type", "application/x-ndjson")
+//	    err := xsql.StreamJSON[User](r.Context(), db, w,
+//	        `SELECT id, email FROM users ORDER BY id`, xsql.WithNDJSON())
+//	    if err != nil {
+//	        log.Println("export:", err)
+//	    }
+//	}
+func StreamJSON[T any](ctx context.Context, q Querier, w io.Writer, query string, args ...any) (err error) {
+	args, cfg, err := callArgs(args)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := cfg.context(ctx)
+	defer cancel()
+
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := rows.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}()
+
+	m, strict := cfg.scanMapper()
+	enc := json.NewEncoder(w)
+	s := rowScanner[T]{m: m, strict: strict}
+	n := 0
+	for rows.Next() {
+		v, err := s.scan(rows)
+		if err != nil {
+			return atRow(err, n)
+		}
+		if cfg.ndjson {
+			if err := enc.Encode(v); err != nil {
+				return err
+			}
+			n++
+			continue
+		}
+		b, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		sep := ","
+		if n == 0 {
+			sep = "["
+		}
+		if _, err := io.WriteString(w, sep); err != nil {
+			return err
+		}
+		if _, err := w.Write(b); err != nil {
+			return err
+		}
+		n++
+	}
+	if ne := rows.Err(); ne != nil {
+		return ne
+	}
+	if cfg.ndjson {
+		return nil
+	}
+	end := "]"
+	if n == 0 {
+		end = "[]"
+	}
+	_, err = io.WriteString(w, end)
+	return err
+}
+
+// StreamCSV executes the SQL query and writes the result to w as CSV through
+// encoding/csv: a header record with the result column names as returned by
+// the driver, then one record per row. Rows are written as they are read, so
+// the full result is never held in memory.
+//
+// Values are rendered by database/sql's string conversion: numbers and
+// booleans in their canonical form, times as RFC 3339 with nanoseconds, bytes
+// verbatim, and NULL as an empty field. Of the [QueryOpt] values, only
+// [WithTimeout] applies.
+//
+// Example:
+//
+//	w.Header().Set("Content-Type", "text/csv")
+//	err := xsql.StreamCSV(r.Context(), db, w,
+//	    `SELECT id, email, created_at FROM users WHERE created_at >= $1`, since)
+func StreamCSV(ctx context.Context, q Querier, w io.Writer, query string, args ...any) (err error) {
+	args, cfg, err := callArgs(args)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := cfg.context(ctx)
+	defer cancel()
+
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := rows.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}()
+
+	cols, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+	cw := csv.NewWriter(w)
+	if err := cw.Write(cols); err != nil {
+		return err
+	}
+
+	vals := make([]sql.NullString, len(cols))
+	ptrs := make([]any, len(cols))
+	for i := range vals {
+		ptrs[i] = &vals[i]
+	}
+	record := make([]string, len(cols))
+	for rows.Next() {
+		if err := rows.Scan(ptrs...); err != nil {
+			return err
+		}
+		for i, v := range vals {
+			record[i] = v.String
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	if ne := rows.Err(); ne != nil {
+		return ne
+	}
+	cw.Flush()
+	return cw.Error()
+}
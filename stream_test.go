@@ -0,0 +1,117 @@
+package xsql
+
+import (
+	"bytes"
+	"context"
+	"database/sql/driver"
+	"errors"
+	"testing"
+	"time"
+)
+
+type streamUser struct {
+	ID    int64  `db:"id" json:"id"`
+	Email string `db:"email" json:"email"`
+}
+
+func streamHandler(data [][]driver.Value) DBHandler {
+	return func(string, []driver.NamedValue) ([]string, [][]driver.Value, error) {
+		return []string{"id", "email"}, data, nil
+	}
+}
+
+func TestStreamJSON_ArrayAndNDJSON(t *testing.T) {
+	data := [][]driver.Value{{int64(1), "a@x"}, {int64(2), "b@x"}}
+	db := newTestDB(t, streamHandler(data))
+	defer func() { _ = db.Close() }()
+	ctx := context.Background()
+
+	var buf bytes.Buffer
+	if err := StreamJSON[streamUser](ctx, db, &buf, "q"); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := buf.String(), `[{"id":1,"email":"a@x"},{"id":2,"email":"b@x"}]`; got != want {
+		t.Fatalf("array:\n got %s\nwant %s", got, want)
+	}
+
+	buf.Reset()
+	if err := StreamJSON[streamUser](ctx, db, &buf, "q", WithNDJSON()); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := buf.String(), "{\"id\":1,\"email\":\"a@x\"}\n{\"id\":2,\"email\":\"b@x\"}\n"; got != want {
+		t.Fatalf("ndjson:\n got %q\nwant %q", got, want)
+	}
+}
+
+func TestStreamJSON_Empty(t *testing.T) {
+	db := newTestDB(t, streamHandler(nil))
+	defer func() { _ = db.Close() }()
+
+	var buf bytes.Buffer
+	if err := StreamJSON[streamUser](context.Background(), db, &buf, "q"); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "[]" {
+		t.Fatalf("empty array: got %q", buf.String())
+	}
+	buf.Reset()
+	if err := StreamJSON[streamUser](context.Background(), db, &buf, "q", WithNDJSON()); err != nil {
+		t.Fatal(err)
+	}
+	if buf.Len() != 0 {
+		t.Fatalf("empty ndjson: got %q", buf.String())
+	}
+}
+
+type failWriter struct{ err error }
+
+func (f failWriter) Write([]byte) (int, error) { return 0, f.err }
+
+func TestStreamJSON_WriteError(t *testing.T) {
+	db := newTestDB(t, streamHandler([][]driver.Value{{int64(1), "a"}}))
+	defer func() { _ = db.Close() }()
+
+	boom := errors.New("boom")
+	if err := StreamJSON[streamUser](context.Background(), db, failWriter{boom}, "q"); !errors.Is(err, boom) {
+		t.Fatalf("want write error, got %v", err)
+	}
+}
+
+func TestStreamCSV(t *testing.T) {
+	at := time.Date(2024, 5, 1, 12, 30, 0, 0, time.UTC)
+	db := newTestDB(t, func(string, []driver.NamedValue) ([]string, [][]driver.Value, error) {
+		return []string{"ID", "Email", "active", "score", "created_at"}, [][]driver.Value{
+			{int64(1), []byte("a@x"), true, 1.5, at},
+			{int64(2), "say \"hi\", ok", false, nil, nil},
+		}, nil
+	})
+	defer func() { _ = db.Close() }()
+
+	var buf bytes.Buffer
+	if err := StreamCSV(context.Background(), db, &buf, "q"); err != nil {
+		t.Fatal(err)
+	}
+	want := "ID,Email,active,score,created_at\n" +
+		"1,a@x,true,1.5,2024-05-01T12:30:00Z\n" +
+		"2,\"say \"\"hi\"\", ok\",false,,\n"
+	if buf.String() != want {
+		t.Fatalf("csv:\n got %q\nwant %q", buf.String(), want)
+	}
+}
+
+func TestStreamCSV_HeaderOnlyAndWriteError(t *testing.T) {
+	db := newTestDB(t, streamHandler(nil))
+	defer func() { _ = db.Close() }()
+
+	var buf bytes.Buffer
+	if err := StreamCSV(context.Background(), db, &buf, "q"); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "id,email\n" {
+		t.Fatalf("header only: got %q", buf.String())
+	}
+	boom := errors.New("boom")
+	if err := StreamCSV(context.Background(), db, failWriter{boom}, "q"); !errors.Is(err, boom) {
+		t.Fatalf("want write error, got %v", err)
+	}
+}
@@ -0,0 +1,44 @@
+package xsql
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+)
+
+// RegisterTVP registers slice type T to be passed to the driver as a single
+// table-valued parameter built by fn, instead of being expanded into one
+// placeholder per element, process-wide. It is meant for SQL Server, where a
+// long id list sent as a TVP avoids the 2100-parameter limit and lets the
+// server reuse one plan for every list length; fn typically returns a
+// mssql.TVP holding the rows and the name of the table type.
+//
+// Named parameters of type T bind as one placeholder, and Query, Get, Exec,
+// and the functions built on them replace T arguments with fn's result, as
+// with [RegisterValuer]. Use a dedicated named type (type IDList []int64)
+// rather than a plain slice, so other slices of the element type still expand.
+// RegisterTVP panics if T is not a slice or array type.
+//
+// Example:
+//
+//	type IDList []int64
+//
+//	xsql.RegisterTVP(func(ids IDList) any {
+//	    rows := make([]struct{ ID int64 }, len(ids))
+//	    for i, id := range ids {
+//	        rows[i].ID = id
+//	    }
+//	    return mssql.TVP{TypeName: "dbo.IDList", Value: rows}
+//	})
+//
+//	users, err := xsql.NamedQuery[User](ctx, db, xsql.PlaceholderAtP,
+//	    `SELECT u.id, u.email FROM users u JOIN :ids i ON i.ID = u.id`,
+//	    map[string]any{"ids": IDList(ids)})
+//	// SELECT u.id, u.email FROM users u JOIN @p1 i ON i.ID = u.id
+func RegisterTVP[T any](fn func(v T) any) {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	if k := t.Kind(); k != reflect.Slice && k != reflect.Array {
+		panic(fmt.Sprintf("xsql: RegisterTVP: %s is not a slice or array type", t))
+	}
+	globalConverters.setValuer(t, func(v any) (driver.Value, error) { return fn(v.(T)), nil })
+}
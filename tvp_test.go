@@ -0,0 +1,76 @@
+package xsql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"reflect"
+	"testing"
+)
+
+type testIDList []int64
+
+// testTVP stands in for mssql.TVP.
+type testTVP struct {
+	TypeName string
+	Rows     []int64
+}
+
+// tvpConn is a test connection that accepts testTVP args as is.
+type tvpConn struct{ testConn }
+
+func (c *tvpConn) CheckNamedValue(nv *driver.NamedValue) error {
+	if _, ok := nv.Value.(testTVP); ok {
+		return nil
+	}
+	var err error
+	nv.Value, err = driver.DefaultParameterConverter.ConvertValue(nv.Value)
+	return err
+}
+
+type tvpConnector struct{ testConnector }
+
+func (c *tvpConnector) Connect(context.Context) (driver.Conn, error) {
+	return &tvpConn{testConn{h: c.h}}, nil
+}
+
+func TestRegisterTVP(t *testing.T) {
+	RegisterTVP(func(ids testIDList) any { return testTVP{TypeName: "dbo.IDList", Rows: ids} })
+
+	var gotQuery string
+	var gotArgs []driver.NamedValue
+	db := sql.OpenDB(&tvpConnector{testConnector{h: func(q string, args []driver.NamedValue) ([]string, [][]driver.Value, error) {
+		gotQuery, gotArgs = q, args
+		return nil, nil, nil
+	}}})
+	defer func() { _ = db.Close() }()
+	ctx := context.Background()
+
+	params := map[string]any{"ids": testIDList{1, 2, 3}, "more": []int64{4, 5}}
+	const q = `DELETE FROM t WHERE id IN (SELECT ID FROM :ids) OR id IN (:more)`
+	if _, err := NamedExec(ctx, db, PlaceholderAtP, q, params); err != nil {
+		t.Fatal(err)
+	}
+	if gotQuery != `DELETE FROM t WHERE id IN (SELECT ID FROM @p1) OR id IN (@p2,@p3)` {
+		t.Fatalf("query = %q", gotQuery)
+	}
+	want := testTVP{TypeName: "dbo.IDList", Rows: []int64{1, 2, 3}}
+	if len(gotArgs) != 3 || !reflect.DeepEqual(gotArgs[0].Value, want) {
+		t.Fatalf("args = %+v", gotArgs)
+	}
+
+	// Native named parameters carry the TVP inside sql.Named.
+	if _, err := NamedExec(ctx, db, PlaceholderAtName, q, params); err != nil {
+		t.Fatal(err)
+	}
+	if gotArgs[0].Name != "ids" || !reflect.DeepEqual(gotArgs[0].Value, want) {
+		t.Fatalf("native args = %+v", gotArgs)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected panic for a non-slice type")
+		}
+	}()
+	RegisterTVP(func(int) any { return nil })
+}
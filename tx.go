@@ -0,0 +1,110 @@
+package xsql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// WithTx runs fn inside a transaction started on db. The transaction is
+// committed if fn returns nil and rolled back if fn returns an error or
+// panics; the panic is then propagated. fn's error is returned as is (joined
+// with the rollback error if rolling back fails), and a failed commit returns
+// an error wrapping the driver's.
+//
+// fn must not keep using tx after it returns. It may commit or roll back tx
+// itself, in which case WithTx's own commit or rollback is skipped.
+//
+// Example:
+//
+//	err := xsql.WithTx(ctx, db, func(tx *sql.Tx) error {
+//	    if _, err := xsql.Exec(ctx, tx, `UPDATE accounts SET balance = balance - $1 WHERE id = $2`, amount, from); err != nil {
+//	        return err
+//	    }
+//	    _, err := xsql.Exec(ctx, tx, `UPDATE accounts SET balance = balance + $1 WHERE id = $2`, amount, to)
+//	    return err
+//	})
+func WithTx(ctx context.Context, db Beginner, fn func(tx *sql.Tx) error) error {
+	return runTx(ctx, db, nil, fn)
+}
+
+// WithTxOptions is like [WithTx] but begins the transaction with opts, for an
+// isolation level or a read-only transaction. A nil opts uses the driver's
+// defaults. Build opts directly or with [TxOptions]. The driver returns an
+// error from BeginTx for a level it does not support.
+//
+// Example:
+//
+//	err := xsql.WithTxOptions(ctx, db, xsql.TxOptions(xsql.TxIsolation(sql.LevelSerializable)),
+//	    func(tx *sql.Tx) error {
+//	        stock, err := xsql.Get[int](ctx, tx, `SELECT stock FROM items WHERE id = $1`, id)
+//	        if err != nil {
+//	            return err
+//	        }
+//	        _, err = xsql.Exec(ctx, tx, `UPDATE items SET stock = $1 WHERE id = $2`, stock-1, id)
+//	        return err
+//	    })
+func WithTxOptions(ctx context.Context, db Beginner, opts *sql.TxOptions, fn func(tx *sql.Tx) error) error {
+	return runTx(ctx, db, opts, fn)
+}
+
+// TxOpt sets a field of [sql.TxOptions]; see [TxOptions].
+type TxOpt func(*sql.TxOptions)
+
+// TxOptions returns the sql.TxOptions set by opts, for [WithTxOptions] or
+// BeginTx.
+//
+// Example:
+//
+//	opts := xsql.TxOptions(xsql.TxIsolation(sql.LevelRepeatableRead), xsql.TxReadOnly())
+func TxOptions(opts ...TxOpt) *sql.TxOptions {
+	var o sql.TxOptions
+	for _, fn := range opts {
+		fn(&o)
+	}
+	return &o
+}
+
+// TxIsolation sets the isolation level of a transaction.
+func TxIsolation(level sql.IsolationLevel) TxOpt {
+	return func(o *sql.TxOptions) { o.Isolation = level }
+}
+
+// TxReadOnly makes a transaction read-only.
+func TxReadOnly() TxOpt {
+	return func(o *sql.TxOptions) { o.ReadOnly = true }
+}
+
+// runTx begins a transaction with opts and runs fn in it, as described for
+// WithTx.
+func runTx(ctx context.Context, db Beginner, opts *sql.TxOptions, fn func(tx *sql.Tx) error) error {
+	tx, err := db.BeginTx(ctx, opts)
+	if err != nil {
+		return err
+	}
+	done := false
+	defer func() {
+		if !done { // fn panicked or called runtime.Goexit
+			_ = tx.Rollback()
+		}
+	}()
+	err = fn(tx)
+	done = true
+	if err != nil {
+		if rerr := tx.Rollback(); rerr != nil && !errors.Is(rerr, sql.ErrTxDone) {
+			return errors.Join(err, fmt.Errorf("xsql: rollback: %w", rerr))
+		}
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		if ctx.Err() != nil {
+			return fmt.Errorf("xsql: commit: %w", context.Cause(ctx)) // database/sql rolled tx back
+		}
+		if errors.Is(err, sql.ErrTxDone) {
+			return nil // fn ended tx itself
+		}
+		return fmt.Errorf("xsql: commit: %w", err)
+	}
+	return nil
+}
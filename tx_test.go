@@ -0,0 +1,163 @@
+package xsql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// txConn is a test connection that records transaction events in log.
+type txConn struct {
+	testConn
+	log       *[]string
+	commitErr error
+}
+
+func (c *txConn) BeginTx(_ context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	ev := "begin"
+	if opts.Isolation != 0 {
+		ev += " " + sql.IsolationLevel(opts.Isolation).String()
+	}
+	if opts.ReadOnly {
+		ev += " read-only"
+	}
+	*c.log = append(*c.log, ev)
+	return &txRecorder{c}, nil
+}
+
+type txRecorder struct{ c *txConn }
+
+func (t *txRecorder) Commit() error {
+	*t.c.log = append(*t.c.log, "commit")
+	return t.c.commitErr
+}
+
+func (t *txRecorder) Rollback() error {
+	*t.c.log = append(*t.c.log, "rollback")
+	return nil
+}
+
+type txConnector struct {
+	testConnector
+	log       *[]string
+	commitErr error
+}
+
+func (c *txConnector) Connect(context.Context) (driver.Conn, error) {
+	return &txConn{testConn: testConn{h: c.h}, log: c.log, commitErr: c.commitErr}, nil
+}
+
+// newTxDB returns a test database whose transactions are recorded in log.
+func newTxDB(t *testing.T, log *[]string, commitErr error) *sql.DB {
+	t.Helper()
+	db := sql.OpenDB(&txConnector{
+		testConnector: testConnector{h: func(q string, _ []driver.NamedValue) ([]string, [][]driver.Value, error) {
+			*log = append(*log, q)
+			if strings.HasPrefix(q, "FAIL") {
+				return nil, nil, errors.New("boom")
+			}
+			return nil, nil, nil
+		}},
+		log:       log,
+		commitErr: commitErr,
+	})
+	t.Cleanup(func() { _ = db.Close() })
+	return db
+}
+
+func TestWithTx(t *testing.T) {
+	ctx := context.Background()
+	var log []string
+	db := newTxDB(t, &log, nil)
+
+	err := WithTx(ctx, db, func(tx *sql.Tx) error {
+		_, err := Exec(ctx, tx, "INSERT 1")
+		return err
+	})
+	if err != nil || !reflect.DeepEqual(log, []string{"begin", "INSERT 1", "commit"}) {
+		t.Fatalf("commit: %v, %q", err, log)
+	}
+
+	log = nil
+	err = WithTx(ctx, db, func(tx *sql.Tx) error {
+		_, err := Exec(ctx, tx, "FAIL")
+		return err
+	})
+	if err == nil || err.Error() != "boom" || !reflect.DeepEqual(log, []string{"begin", "FAIL", "rollback"}) {
+		t.Fatalf("rollback: %v, %q", err, log)
+	}
+
+	// fn ending the transaction itself is not an error.
+	log = nil
+	if err := WithTx(ctx, db, func(tx *sql.Tx) error { return tx.Rollback() }); err != nil || len(log) != 2 {
+		t.Fatalf("self rollback: %v, %q", err, log)
+	}
+
+	log = nil
+	func() {
+		defer func() {
+			if p := recover(); p != "oops" {
+				t.Fatalf("recovered %v", p)
+			}
+		}()
+		_ = WithTx(ctx, db, func(*sql.Tx) error { panic("oops") })
+	}()
+	if !reflect.DeepEqual(log, []string{"begin", "rollback"}) {
+		t.Fatalf("panic: %q", log)
+	}
+
+	log = nil
+	failing := newTxDB(t, &log, errors.New("serialization failure"))
+	err = WithTx(ctx, failing, func(*sql.Tx) error { return nil })
+	if err == nil || err.Error() != "xsql: commit: serialization failure" {
+		t.Fatalf("commit error: %v", err)
+	}
+}
+
+func TestWithTxOptions(t *testing.T) {
+	ctx := context.Background()
+	var log []string
+	db := newTxDB(t, &log, nil)
+
+	opts := TxOptions(TxIsolation(sql.LevelSerializable), TxReadOnly())
+	if *opts != (sql.TxOptions{Isolation: sql.LevelSerializable, ReadOnly: true}) {
+		t.Fatalf("opts = %+v", opts)
+	}
+	if err := WithTxOptions(ctx, db, opts, func(*sql.Tx) error { return nil }); err != nil {
+		t.Fatal(err)
+	}
+	if err := WithTxOptions(ctx, db, nil, func(*sql.Tx) error { return nil }); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"begin Serializable read-only", "commit", "begin", "commit"}
+	if !reflect.DeepEqual(log, want) {
+		t.Fatalf("log = %q", log)
+	}
+}
+
+func TestDBTX(t *testing.T) {
+	ctx := context.Background()
+	var log []string
+	db := newTxDB(t, &log, nil)
+	rename := func(q DBTX) error {
+		_, err := Exec(ctx, q, "UPDATE users")
+		return err
+	}
+	if err := rename(db); err != nil {
+		t.Fatal(err)
+	}
+	if err := WithTx(ctx, db, func(tx *sql.Tx) error { return rename(tx) }); err != nil {
+		t.Fatal(err)
+	}
+	if err := WithConn(ctx, db, func(c *sql.Conn) error { return rename(c) }); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"UPDATE users", "begin", "UPDATE users", "commit", "UPDATE users"}
+	if !reflect.DeepEqual(log, want) {
+		t.Fatalf("log = %q", log)
+	}
+}
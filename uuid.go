@@ -0,0 +1,75 @@
+package xsql
+
+import (
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// pickUUID handles destinations whose underlying type is [16]byte (or
+// pointers to them), such as github.com/google/uuid.UUID. The representation
+// is chosen per value: 16 bytes are copied as is, and text in the canonical
+// 36-character form, 32 bare hex digits, braces, or a "urn:uuid:" prefix is
+// decoded. NULL leaves a value destination zero and a pointer nil.
+//
+// Types with their own sql.Scanner or unmarshaler never reach this step.
+func pickUUID(dstType reflect.Type) (reflect.Type, func(dst, src reflect.Value) error, bool) {
+	base, isPtr := dstType, false
+	if base.Kind() == reflect.Ptr {
+		base, isPtr = base.Elem(), true
+	}
+	if base.Kind() != reflect.Array || base.Len() != 16 || base.Elem().Kind() != reflect.Uint8 {
+		return nil, nil, false
+	}
+	return reflect.TypeOf((*any)(nil)).Elem(), func(dst, src reflect.Value) error {
+		var raw []byte
+		switch v := src.Interface().(type) {
+		case nil:
+			dst.SetZero()
+			return nil
+		case []byte:
+			raw = v
+		case string:
+			raw = []byte(v)
+		default:
+			return fmt.Errorf("xsql: cannot scan %T into %s", v, base)
+		}
+		var id [16]byte
+		if len(raw) == 16 {
+			copy(id[:], raw)
+		} else if err := parseUUID(string(raw), &id); err != nil {
+			return err
+		}
+		val := reflect.New(base).Elem()
+		reflect.Copy(val, reflect.ValueOf(id[:]))
+		if isPtr {
+			dst.Set(val.Addr())
+		} else {
+			dst.Set(val)
+		}
+		return nil
+	}, true
+}
+
+func parseUUID(s string, id *[16]byte) error {
+	t := s
+	if len(t) > 9 && strings.EqualFold(t[:9], "urn:uuid:") {
+		t = t[9:]
+	} else if len(t) == 38 && t[0] == '{' && t[37] == '}' {
+		t = t[1:37]
+	}
+	if len(t) == 36 {
+		if t[8] != '-' || t[13] != '-' || t[18] != '-' || t[23] != '-' {
+			return fmt.Errorf("xsql: invalid UUID %q", s)
+		}
+		t = t[:8] + t[9:13] + t[14:18] + t[19:23] + t[24:]
+	}
+	if len(t) != 32 {
+		return fmt.Errorf("xsql: invalid UUID %q", s)
+	}
+	if _, err := hex.Decode(id[:], []byte(t)); err != nil {
+		return fmt.Errorf("xsql: invalid UUID %q", s)
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package xsql
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+)
+
+type testUUID [16]byte
+
+func TestScan_UUID(t *testing.T) {
+	want := testUUID{0x12, 0x3e, 0x45, 0x67, 0xe8, 0x9b, 0x12, 0xd3, 0xa4, 0x56, 0x42, 0x66, 0x14, 0x17, 0x40, 0x00}
+	type Row struct {
+		ID     testUUID  `db:"id"`
+		Parent *testUUID `db:"parent"`
+		Raw    [16]byte  `db:"raw"`
+	}
+	db := newTestDB(t, func(q string, _ []driver.NamedValue) ([]string, [][]driver.Value, error) {
+		switch q {
+		case "bad":
+			return []string{"id"}, [][]driver.Value{{"123e4567-e89b-12d3-a456-42661417400"}}, nil
+		case "one":
+			return []string{"id"}, [][]driver.Value{{"urn:uuid:123e4567-e89b-12d3-a456-426614174000"}}, nil
+		}
+		return []string{"id", "parent", "raw"}, [][]driver.Value{
+			{"123e4567-e89b-12d3-a456-426614174000", []byte(want[:]), "{123E4567-E89B-12D3-A456-426614174000}"},
+			{want[:], nil, "123e4567e89b12d3a456426614174000"},
+		}, nil
+	})
+	defer func() { _ = db.Close() }()
+	ctx := context.Background()
+
+	got, err := Query[Row](ctx, db, "q")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got[0].ID != want || got[0].Parent == nil || *got[0].Parent != want || got[0].Raw != [16]byte(want) {
+		t.Fatalf("row 0: %+v", got[0])
+	}
+	if got[1].ID != want || got[1].Parent != nil || got[1].Raw != [16]byte(want) {
+		t.Fatalf("row 1: %+v", got[1])
+	}
+
+	if id, err := Get[testUUID](ctx, db, "one"); err != nil || id != want {
+		t.Fatalf("Get[testUUID] = %x, %v", id, err)
+	}
+	if _, err := Get[testUUID](ctx, db, "bad"); err == nil {
+		t.Fatalf("expected error for malformed UUID")
+	}
+}
@@ -0,0 +1,184 @@
+package xsql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ErrTxTimeout is returned by [WithTxWatchdog] when the transaction was
+// rolled back for staying open past its timeout.
+var ErrTxTimeout = errors.New("xsql: transaction exceeded its watchdog timeout")
+
+// TxWatchdog configures [WithTxWatchdog].
+type TxWatchdog struct {
+	Timeout   time.Duration   // longest the transaction may stay open; must be positive
+	TxOptions *sql.TxOptions  // begins the transaction, as with WithTxOptions
+	OnTimeout func(TxTimeout) // reports a timeout before the rollback; nil logs a warning with log/slog
+}
+
+// TxTimeout describes a transaction rolled back by [WithTxWatchdog].
+type TxTimeout struct {
+	Timeout   time.Duration
+	Started   time.Time
+	Caller    string // file:line of the WithTxWatchdog call
+	LastQuery string // last statement run through the WatchedTx, if any
+}
+
+// WatchedTx is the transaction passed to the function run by
+// [WithTxWatchdog]. It is a *sql.Tx that also remembers the last statement
+// run through its Query, QueryRow, Exec, and Prepare methods and their
+// Context variants, so a timeout can report where the transaction stalled.
+// The variants without a context run under the watchdog's context, so a
+// timeout aborts them too. It satisfies [DBTX] and can be passed to every
+// xsql helper.
+type WatchedTx struct {
+	*sql.Tx
+	ctx  context.Context
+	last *lastQuery
+}
+
+// WithTxWatchdog runs fn in a transaction like [WithTx], and rolls the
+// transaction back if it is still open after w.Timeout: it cancels the ctx
+// passed to fn, which also aborts fn's in-flight statements, and reports the
+// caller and the last statement run (see [TxTimeout]). A transaction left
+// idle by slow application code holds locks and, on PostgreSQL, keeps vacuum
+// from removing dead rows; the watchdog bounds how long that can last.
+//
+// After a timeout WithTxWatchdog returns an error wrapping [ErrTxTimeout].
+// fn should use the ctx it is given for its statements.
+//
+// Example:
+//
+//	err := xsql.WithTxWatchdog(ctx, db, xsql.TxWatchdog{Timeout: 30 * time.Second},
+//	    func(ctx context.Context, tx *xsql.WatchedTx) error {
+//	        _, err := xsql.Exec(ctx, tx, `UPDATE jobs SET state = 'done' WHERE id = $1`, id)
+//	        return err
+//	    })
+//	if errors.Is(err, xsql.ErrTxTimeout) {
+//	    // rolled back
+//	}
+func WithTxWatchdog(ctx context.Context, db Beginner, w TxWatchdog, fn func(ctx context.Context, tx *WatchedTx) error) error {
+	if w.Timeout <= 0 {
+		return fmt.Errorf("xsql: watchdog timeout must be positive, got %s", w.Timeout)
+	}
+	info := TxTimeout{Timeout: w.Timeout, Started: time.Now()}
+	if _, file, line, ok := runtime.Caller(1); ok {
+		info.Caller = fmt.Sprintf("%s:%d", file, line)
+	}
+	last := &lastQuery{}
+	wctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	// state moves from watchRunning to watchTimedOut when the timer fires
+	// first, or to watchDone when the transaction finishes first.
+	var state atomic.Int32
+	timer := time.AfterFunc(w.Timeout, func() {
+		if !state.CompareAndSwap(watchRunning, watchTimedOut) {
+			return
+		}
+		info.LastQuery = last.get()
+		if w.OnTimeout != nil {
+			w.OnTimeout(info)
+		} else {
+			slog.Warn("xsql: transaction open past its watchdog timeout; rolling back",
+				"timeout", info.Timeout, "caller", info.Caller, "last_query", info.LastQuery)
+		}
+		cancel(ErrTxTimeout) // database/sql rolls back a transaction whose context is done
+	})
+	defer timer.Stop()
+
+	err := runTx(wctx, db, w.TxOptions, func(tx *sql.Tx) error {
+		return fn(wctx, &WatchedTx{Tx: tx, ctx: wctx, last: last})
+	})
+	timedOut := !state.CompareAndSwap(watchRunning, watchDone)
+	if err != nil && timedOut && !errors.Is(err, ErrTxTimeout) {
+		err = fmt.Errorf("%w: %w", ErrTxTimeout, err)
+	}
+	return err
+}
+
+const (
+	watchRunning = iota
+	watchTimedOut
+	watchDone
+)
+
+// Query runs a query in the transaction under the watchdog's context; see
+// [sql.Tx.Query].
+func (t *WatchedTx) Query(query string, args ...any) (*sql.Rows, error) {
+	return t.QueryContext(t.ctx, query, args...)
+}
+
+// QueryRow runs a query returning at most one row under the watchdog's
+// context; see [sql.Tx.QueryRow].
+func (t *WatchedTx) QueryRow(query string, args ...any) *sql.Row {
+	return t.QueryRowContext(t.ctx, query, args...)
+}
+
+// Exec runs a statement in the transaction under the watchdog's context; see
+// [sql.Tx.Exec].
+func (t *WatchedTx) Exec(query string, args ...any) (sql.Result, error) {
+	return t.ExecContext(t.ctx, query, args...)
+}
+
+// Prepare prepares a statement in the transaction under the watchdog's
+// context; see [sql.Tx.Prepare].
+func (t *WatchedTx) Prepare(query string) (*sql.Stmt, error) {
+	return t.PrepareContext(t.ctx, query)
+}
+
+// Stmt returns a transaction-specific statement from stmt under the
+// watchdog's context; see [sql.Tx.Stmt].
+func (t *WatchedTx) Stmt(stmt *sql.Stmt) *sql.Stmt {
+	return t.Tx.StmtContext(t.ctx, stmt)
+}
+
+// QueryContext runs a query in the transaction; see [sql.Tx.QueryContext].
+func (t *WatchedTx) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	t.last.set(query)
+	return t.Tx.QueryContext(ctx, query, args...)
+}
+
+// QueryRowContext runs a query returning at most one row; see
+// [sql.Tx.QueryRowContext].
+func (t *WatchedTx) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	t.last.set(query)
+	return t.Tx.QueryRowContext(ctx, query, args...)
+}
+
+// ExecContext runs a statement in the transaction; see [sql.Tx.ExecContext].
+func (t *WatchedTx) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	t.last.set(query)
+	return t.Tx.ExecContext(ctx, query, args...)
+}
+
+// PrepareContext prepares a statement in the transaction; see
+// [sql.Tx.PrepareContext].
+func (t *WatchedTx) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	t.last.set(query)
+	return t.Tx.PrepareContext(ctx, query)
+}
+
+// lastQuery holds the last statement run in a watched transaction.
+type lastQuery struct {
+	mu sync.Mutex
+	q  string
+}
+
+func (l *lastQuery) set(q string) {
+	l.mu.Lock()
+	l.q = q
+	l.mu.Unlock()
+}
+
+func (l *lastQuery) get() string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.q
+}
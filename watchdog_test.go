@@ -0,0 +1,112 @@
+package xsql
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWithTxWatchdog(t *testing.T) {
+	ctx := context.Background()
+	var log []string
+	db := newTxDB(t, &log, nil)
+
+	var got TxTimeout
+	w := TxWatchdog{Timeout: 20 * time.Millisecond, OnTimeout: func(i TxTimeout) { got = i }}
+	err := WithTxWatchdog(ctx, db, w, func(ctx context.Context, tx *WatchedTx) error {
+		if _, err := Exec(ctx, tx, "UPDATE jobs"); err != nil {
+			return err
+		}
+		<-ctx.Done() // idle until the watchdog fires
+		_, err := Exec(ctx, tx, "UPDATE late")
+		return err
+	})
+	if !errors.Is(err, ErrTxTimeout) {
+		t.Fatalf("err = %v", err)
+	}
+	if got.LastQuery != "UPDATE jobs" || got.Timeout != w.Timeout || !strings.Contains(got.Caller, "watchdog_test.go") {
+		t.Fatalf("timeout info = %+v", got)
+	}
+
+	// A transaction that finishes in time commits normally. (A new database:
+	// database/sql rolls back the timed-out one in the background.)
+	var log2 []string
+	db2 := newTxDB(t, &log2, nil)
+	err = WithTxWatchdog(ctx, db2, TxWatchdog{Timeout: time.Minute}, func(ctx context.Context, tx *WatchedTx) error {
+		_, err := Exec(ctx, tx, "UPDATE jobs")
+		return err
+	})
+	if err != nil || strings.Join(log2, ",") != "begin,UPDATE jobs,commit" {
+		t.Fatalf("in time: %v, %q", err, log2)
+	}
+
+	if err := WithTxWatchdog(ctx, db2, TxWatchdog{}, func(context.Context, *WatchedTx) error { return nil }); err == nil {
+		t.Fatal("expected error for zero timeout")
+	}
+}
+
+func TestWithTxWatchdog_DefaultLog(t *testing.T) {
+	var buf bytes.Buffer
+	prev := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(&buf, nil)))
+	t.Cleanup(func() { slog.SetDefault(prev) })
+
+	var log []string
+	db := newTxDB(t, &log, nil)
+	err := WithTxWatchdog(context.Background(), db, TxWatchdog{Timeout: time.Millisecond},
+		func(ctx context.Context, tx *WatchedTx) error {
+			rows, err := tx.QueryContext(ctx, "SELECT pending")
+			if err != nil {
+				return err
+			}
+			_ = rows.Close()
+			<-ctx.Done()
+			return context.Cause(ctx)
+		})
+	if !errors.Is(err, ErrTxTimeout) {
+		t.Fatalf("err = %v", err)
+	}
+	if out := buf.String(); !strings.Contains(out, "watchdog") || !strings.Contains(out, `last_query="SELECT pending"`) {
+		t.Fatalf("log = %q", out)
+	}
+}
+
+func TestWithTxWatchdog_PlainMethods(t *testing.T) {
+	var log []string
+	db := newTxDB(t, &log, nil)
+
+	var got TxTimeout
+	w := TxWatchdog{Timeout: 20 * time.Millisecond, OnTimeout: func(i TxTimeout) { got = i }}
+	err := WithTxWatchdog(context.Background(), db, w, func(ctx context.Context, tx *WatchedTx) error {
+		if _, err := tx.Exec("UPDATE plain"); err != nil {
+			return err
+		}
+		<-ctx.Done()
+		return context.Cause(ctx)
+	})
+	if !errors.Is(err, ErrTxTimeout) || got.LastQuery != "UPDATE plain" {
+		t.Fatalf("err = %v, last query = %q", err, got.LastQuery)
+	}
+}
+
+func TestWithTxWatchdog_ErrorBeforeTimeout(t *testing.T) {
+	var log []string
+	db := newTxDB(t, &log, nil)
+
+	errBoom := errors.New("boom")
+	fired := make(chan struct{}, 1)
+	w := TxWatchdog{Timeout: 50 * time.Millisecond, OnTimeout: func(TxTimeout) { fired <- struct{}{} }}
+	err := WithTxWatchdog(context.Background(), db, w, func(context.Context, *WatchedTx) error { return errBoom })
+	if !errors.Is(err, errBoom) || errors.Is(err, ErrTxTimeout) {
+		t.Fatalf("err = %v", err)
+	}
+	select {
+	case <-fired:
+		t.Fatal("watchdog fired after the transaction finished")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
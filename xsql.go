@@ -17,7 +17,47 @@ type Execer interface {
 	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
 }
 
+// DBTX is implemented by *sql.DB, *sql.Tx, and *sql.Conn: anything that can
+// both query and execute. Repositories can accept a DBTX to run the same
+// code inside or outside a transaction, and pass it on to every xsql helper
+// that takes a Querier or an Execer.
+//
+// Example:
+//
+//	type UserRepo struct{ db xsql.DBTX }
+//
+//	func (r UserRepo) Rename(ctx context.Context, id int64, name string) error {
+//	    _, err := xsql.Exec(ctx, r.db, `UPDATE users SET name = $1 WHERE id = $2`, name, id)
+//	    return err
+//	}
+//
+//	err := xsql.WithTx(ctx, db, func(tx *sql.Tx) error {
+//	    return UserRepo{tx}.Rename(ctx, 7, "Ada")
+//	})
+type DBTX interface {
+	Querier
+	Execer
+}
+
+var (
+	_ DBTX = (*sql.DB)(nil)
+	_ DBTX = (*sql.Tx)(nil)
+	_ DBTX = (*sql.Conn)(nil)
+)
+
 // Beginner is implemented by *sql.DB and *sql.Conn. It starts a transaction.
 type Beginner interface {
 	BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
 }
+
+// Preparer is implemented by *sql.DB, *sql.Tx, and *sql.Conn. It creates a
+// prepared statement.
+type Preparer interface {
+	PrepareContext(ctx context.Context, query string) (*sql.Stmt, error)
+}
+
+// Conner is implemented by *sql.DB. It reserves a single connection from the
+// pool for a sequence of operations.
+type Conner interface {
+	Conn(ctx context.Context) (*sql.Conn, error)
+}
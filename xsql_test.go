@@ -28,9 +28,16 @@ type testConn struct {
 	h DBHandler
 }
 
-func (c *testConn) Prepare(string) (driver.Stmt, error) { return nil, driver.ErrSkip }
-func (c *testConn) Close() error                        { return nil }
-func (c *testConn) Begin() (driver.Tx, error)           { return nil, driver.ErrSkip }
+func (c *testConn) Prepare(query string) (driver.Stmt, error) {
+	return &testStmt{c: c, query: query}, nil
+}
+func (c *testConn) Close() error              { return nil }
+func (c *testConn) Begin() (driver.Tx, error) { return testTx{}, nil }
+
+type testTx struct{}
+
+func (testTx) Commit() error   { return nil }
+func (testTx) Rollback() error { return nil }
 
 func (c *testConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
 	cols, data, err := c.h(query, args)
@@ -40,6 +47,35 @@ func (c *testConn) QueryContext(ctx context.Context, query string, args []driver
 	return &testRows{cols: cols, data: data}, nil
 }
 
+// ExecContext runs exec statements through the same handler; returned rows are ignored.
+func (c *testConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	if _, _, err := c.h(query, args); err != nil {
+		return nil, err
+	}
+	return driver.RowsAffected(0), nil
+}
+
+// testStmt is a prepared statement that defers to the connection's handler.
+type testStmt struct {
+	c     *testConn
+	query string
+}
+
+func (s *testStmt) Close() error  { return nil }
+func (s *testStmt) NumInput() int { return -1 }
+func (s *testStmt) Exec([]driver.Value) (driver.Result, error) {
+	return nil, errors.New("testStmt.Exec not supported")
+}
+func (s *testStmt) Query([]driver.Value) (driver.Rows, error) {
+	return nil, errors.New("testStmt.Query should not be called; use QueryContext")
+}
+func (s *testStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	return s.c.QueryContext(ctx, s.query, args)
+}
+func (s *testStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	return s.c.ExecContext(ctx, s.query, args)
+}
+
 type testRows struct {
 	cols []string
 	data [][]driver.Value